@@ -0,0 +1,198 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/alex-held/dfctl-kit/pkg/system"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+var errNoGoRoot = errors.New("directory is no go sdk")
+var errAlreadyInstalled = errors.New("go version is already installed")
+
+// adoptOptions are the options of the adopt command
+type adoptOptions struct {
+	// Move moves the sdk into the install path instead of copying it
+	Move bool
+	// Use links the adopted version as current version
+	Use bool
+}
+
+func newAdoptCmd() *cobra.Command {
+	var opts adoptOptions
+	var force bool
+	cmd := &cobra.Command{
+		Use:   "adopt <goroot>",
+		Short: "imports an existing go installation",
+		Long: "imports a go sdk installed by other means, e.g. by Homebrew or the official installer into /usr/local/go, into the install path, " +
+			"detecting its version from its VERSION file. The sdk is copied unless --move is passed, which breaks package managers owning it",
+		Example: "  dfctl-go adopt /usr/local/go --use",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateArgsForSubcommand("adopt", args, 1); err != nil {
+				return err
+			}
+			e := defaultExecutor()
+			e.Force = force
+			return e.Adopt(args[0], opts)
+		},
+	}
+	cmd.Flags().BoolVar(&opts.Move, "move", false, "move the sdk into the install path instead of copying it")
+	cmd.Flags().BoolVar(&opts.Use, "use", false, "link the adopted version as current version")
+	cmd.Flags().BoolVar(&force, "force", false, "replace the version if it is installed already")
+	return cmd
+}
+
+// Adopt imports the sdk at root and links it as current version if requested
+func (e *executor) Adopt(root string, opts adoptOptions) error {
+	version, dir, err := e.adopt(root, opts.Move)
+	if err != nil {
+		return err
+	}
+	e.infof("adopted go %s from %s into %s\n", version, root, dir)
+	if !opts.Use {
+		return nil
+	}
+	if err = e.Use(version); err != nil {
+		return err
+	}
+	e.infof("using go %s\n", version)
+	return nil
+}
+
+// adopt copies or moves the sdk at root into the version directory of its version and writes its manifest.
+// Installed versions are only replaced with Force, after the sdk was staged.
+func (e *executor) adopt(root string, move bool) (Version, string, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return "", "", err
+	}
+	// Homebrew keeps the sdk in the libexec directory of the formula
+	if _, err := sdkVersion(e.Fs, root); err != nil {
+		if _, lerr := sdkVersion(e.Fs, filepath.Join(root, "libexec")); lerr == nil {
+			root = filepath.Join(root, "libexec")
+		}
+	}
+	version, err := sdkVersion(e.Fs, root)
+	if err != nil {
+		return "", "", fmt.Errorf("%w; root=%s; err=%v", errNoGoRoot, root, err)
+	}
+	if exists, _ := afero.DirExists(e.Fs, filepath.Join(root, "bin")); !exists {
+		return "", "", fmt.Errorf("%w; root=%s; it contains no bin directory", errNoGoRoot, root)
+	}
+	if rel, err := filepath.Rel(e.InstallPath, root); err == nil && !strings.HasPrefix(rel, "..") {
+		return "", "", fmt.Errorf("%s is managed by dfctl-go already", root)
+	}
+	e.Summary.addVersion(version)
+	existing, err := e.versionPath(version)
+	if err == nil && !e.Force {
+		return "", "", fmt.Errorf("%w; version=%s; dir=%s; pass --force to replace it", errAlreadyInstalled, version, existing)
+	}
+
+	dir := filepath.Join(e.InstallPath, version.String())
+	staging := dir + stagingSuffix
+	if err = e.Fs.RemoveAll(staging); err != nil {
+		return "", "", fmt.Errorf("failed to remove the leftover staging directory %s; %w", staging, err)
+	}
+	if err = e.Fs.MkdirAll(e.InstallPath, os.ModePerm); err != nil {
+		return "", "", fmt.Errorf("failed to create install directory at %s; %w", e.InstallPath, err)
+	}
+	defer func() {
+		_ = e.Fs.RemoveAll(staging)
+	}()
+	moved := move && e.Fs.Rename(root, staging) == nil
+	if !moved {
+		if err = copyTree(e.Fs, root, staging); err != nil {
+			return "", "", fmt.Errorf("failed to copy go %s from %s; err=%v", version, root, err)
+		}
+	}
+
+	ri := system.OSRuntimeInfoGetter{}.Get()
+	m := installManifest{Version: version, Source: root, InstalledAt: time.Now().UTC(), OS: ri.OS, Arch: ri.Arch, InstalledBy: hostFingerprint()}
+	if m.Checksums, err = criticalFiles(e.Fs, staging); err != nil {
+		return "", "", fmt.Errorf("failed to checksum go sdk %s; err=%v", version, err)
+	}
+	if m.Files, err = countFiles(e.Fs, staging); err != nil {
+		return "", "", fmt.Errorf("failed to count the files of go sdk %s; err=%v", version, err)
+	}
+	if err = e.writeManifest(staging, m); err != nil {
+		return "", "", err
+	}
+	// the installed version is only replaced once the adopted sdk is staged completely
+	if existing != "" {
+		if err = e.Fs.RemoveAll(existing); err != nil {
+			return "", "", fmt.Errorf("failed to replace go %s in %s; err=%v", version, existing, err)
+		}
+	}
+	if err = e.Fs.Rename(staging, dir); err != nil {
+		return "", "", fmt.Errorf("failed to move go sdk %s into place at %s; %w", version, dir, err)
+	}
+	if move && !moved {
+		// the sdk could not be renamed across file systems, so the copy replaces it
+		if err = e.Fs.RemoveAll(root); err != nil {
+			log.Warn().Err(err).Msgf("adopted go %s but failed to remove %s", version, root)
+		}
+	}
+	e.record(historyEntry{Op: historyInstall, Version: version, Source: root})
+	e.rehashIfEnabled()
+	return version, dir, nil
+}
+
+// copyTree copies the directories, regular files and symlinks below src to dst, keeping their permissions
+func copyTree(fs afero.Fs, src, dst string) error {
+	return afero.Walk(fs, src, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		switch mode := fi.Mode(); {
+		case mode.IsDir():
+			// the owner must be able to create the contents of read-only directories
+			return fs.MkdirAll(target, mode.Perm()|0700)
+		case mode&os.ModeSymlink != 0:
+			reader, ok := fs.(afero.LinkReader)
+			linker, lok := fs.(afero.Linker)
+			if !ok || !lok {
+				return fmt.Errorf("cannot copy the symlink %s", path)
+			}
+			link, err := reader.ReadlinkIfPossible(path)
+			if err != nil {
+				return err
+			}
+			return linker.SymlinkIfPossible(link, target)
+		case mode.IsRegular():
+			return copyFile(fs, path, target, mode.Perm())
+		}
+		log.Debug().Msgf("skipping the special file %s", path)
+		return nil
+	})
+}
+
+// copyFile copies the regular file src to dst with the permissions perm
+func copyFile(fs afero.Fs, src, dst string, perm os.FileMode) error {
+	in, err := fs.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := fs.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+)
+
+// writeGoRoot creates a minimal go sdk of version at root
+func writeGoRoot(root, version string) {
+	_ = os.MkdirAll(filepath.Join(root, "bin"), os.ModePerm)
+	_ = os.MkdirAll(filepath.Join(root, "src", "fmt"), os.ModePerm)
+	_ = os.WriteFile(filepath.Join(root, "VERSION"), []byte(version+"\ntime 2021-09-09T15:27:21Z\n"), 0644)
+	_ = os.WriteFile(filepath.Join(root, "bin", "go"), []byte("#!/bin/sh\n"), 0755)
+	_ = os.WriteFile(filepath.Join(root, "src", "fmt", "print.go"), []byte("package fmt\n"), 0644)
+	_ = os.Symlink("go", filepath.Join(root, "bin", "golink"))
+}
+
+// fullFs fails to create files like a full disk
+type fullFs struct {
+	afero.Fs
+}
+
+func (fs fullFs) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	if flag&os.O_CREATE != 0 {
+		return nil, syscall.ENOSPC
+	}
+	return fs.Fs.OpenFile(name, flag, perm)
+}
+
+func TestAdopt(t *testing.T) {
+	testutils.Run(t, "Adopt", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		root := filepath.Join(testutils.TempDir(t, "usr", "local"), "go")
+		var out *Buffer
+
+		g.BeforeEach(func() {
+			createVersionDirs()
+			writeGoRoot(root, "go1.18.2")
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+			_ = os.RemoveAll(root)
+		})
+
+		newSut := func() *executor {
+			out = &Buffer{&bytes.Buffer{}}
+			sut := defaultExecutor()
+			sut.Streams.Out = out
+			return sut
+		}
+
+		g.It("copies the sdk into the install path", func() {
+			Ω(newSut().Adopt(root, adoptOptions{})).Should(Succeed())
+			dir := filepath.Join(InstallPath, "1.18.2")
+			Ω(out.String()).Should(Equal("adopted go 1.18.2 from " + root + " into " + dir + "\n"))
+			Ω(filepath.Join(dir, "bin", "go")).Should(BeARegularFile())
+			Ω(filepath.Join(dir, "src", "fmt", "print.go")).Should(BeARegularFile())
+			Ω(os.Readlink(filepath.Join(dir, "bin", "golink"))).Should(Equal("go"))
+			Ω(filepath.Join(root, "bin", "go")).Should(BeARegularFile())
+
+			m, err := newSut().readManifest(dir)
+			Ω(err).Should(Succeed())
+			Ω(m.Source).Should(Equal(root))
+			Ω(m.Files).Should(Equal(3))
+			Ω(m.Checksums).Should(HaveKey("bin/go"))
+		})
+
+		g.It("moves the sdk with --move", func() {
+			Ω(newSut().Adopt(root, adoptOptions{Move: true})).Should(Succeed())
+			Ω(filepath.Join(InstallPath, "1.18.2", "bin", "go")).Should(BeARegularFile())
+			Ω(root).ShouldNot(BeADirectory())
+		})
+
+		g.It("links the adopted version with --use", func() {
+			Ω(newSut().Adopt(root, adoptOptions{Use: true})).Should(Succeed())
+			Ω(out.String()).Should(ContainSubstring("using go 1.18.2"))
+			Ω(newSut().current()).Should(Equal(Version("1.18.2")))
+		})
+
+		g.It("finds the sdk in the libexec directory of Homebrew", func() {
+			formula := filepath.Join(filepath.Dir(root), "opt", "go")
+			writeGoRoot(filepath.Join(formula, "libexec"), "go1.18.3")
+			defer os.RemoveAll(formula)
+			Ω(newSut().Adopt(formula, adoptOptions{})).Should(Succeed())
+			Ω(filepath.Join(InstallPath, "1.18.3", "bin", "go")).Should(BeARegularFile())
+		})
+
+		g.It("refuses installed versions unless forced", func() {
+			writeGoRoot(root, "go1.17.1")
+			err := newSut().Adopt(root, adoptOptions{})
+			Ω(errors.Is(err, errAlreadyInstalled)).Should(BeTrue())
+
+			sut := newSut()
+			sut.Force = true
+			Ω(sut.Adopt(root, adoptOptions{})).Should(Succeed())
+			Ω(filepath.Join(InstallPath, "v1.17.1")).ShouldNot(BeADirectory())
+			Ω(filepath.Join(InstallPath, "1.17.1", "bin", "go")).Should(BeARegularFile())
+		})
+
+		g.It("keeps the installed version if the forced adoption fails", func() {
+			writeGoRoot(root, "go1.17.1")
+			sut := newSut()
+			sut.Force = true
+			sut.Fs = fullFs{sut.Fs}
+			err := sut.Adopt(root, adoptOptions{})
+			Ω(err).Should(MatchError(ContainSubstring("failed to copy go 1.17.1")))
+			Ω(filepath.Join(InstallPath, "v1.17.1")).Should(BeADirectory())
+			Ω(filepath.Join(InstallPath, "1.17.1"+stagingSuffix)).ShouldNot(BeADirectory())
+		})
+
+		g.It("refuses directories which are no go sdk", func() {
+			dir := testutils.TempDir(t, "empty")
+			_ = os.MkdirAll(dir, os.ModePerm)
+			err := newSut().Adopt(dir, adoptOptions{})
+			Ω(errors.Is(err, errNoGoRoot)).Should(BeTrue())
+		})
+	})
+}
+
+func TestCopyTree(t *testing.T) {
+	testutils.Run(t, "copyTree", func(g *goblin.G) {
+		g.It("copies files with their permissions", func() {
+			fs := afero.NewMemMapFs()
+			_ = afero.WriteFile(fs, "/src/bin/go", []byte("go"), 0755)
+			_ = afero.WriteFile(fs, "/src/README", []byte("readme"), 0644)
+			Ω(copyTree(fs, "/src", "/dst")).Should(Succeed())
+			fi, err := fs.Stat("/dst/bin/go")
+			Ω(err).Should(Succeed())
+			Ω(fi.Mode().Perm()).Should(Equal(os.FileMode(0755)))
+			Ω(afero.ReadFile(fs, "/dst/README")).Should(Equal([]byte("readme")))
+		})
+	})
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	semver2 "github.com/Masterminds/semver"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	errInvalidAlias = errors.New("invalid alias")
+	errUnknownAlias = errors.New("unknown alias")
+)
+
+// aliasNamePattern matches alias names, which start with a letter to tell them apart from versions
+var aliasNamePattern = regexp.MustCompile(`^[a-zA-Z][\w.-]*$`)
+
+func newAliasCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "alias",
+		Short: "manages named versions like lts, which are accepted everywhere a version is",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "set <name> <version>",
+		Short: "names a version, partial version, channel or constraint",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateArgsForSubcommand("alias set", args, 2); err != nil {
+				return err
+			}
+			return defaultExecutor().SetAlias(args[0], args[1])
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "lists the aliases",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateArgsForSubcommand("alias list", args, 0); err != nil {
+				return err
+			}
+			return defaultExecutor().ListAliases()
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "rm <name>",
+		Short: "removes an alias",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateArgsForSubcommand("alias rm", args, 1); err != nil {
+				return err
+			}
+			return defaultExecutor().RemoveAlias(args[0])
+		},
+	})
+	return cmd
+}
+
+// validateAlias fails if name cannot be told apart from a version or target is no version argument
+func validateAlias(name, target string) error {
+	switch lower := strings.ToLower(name); {
+	case !aliasNamePattern.MatchString(name), lower == KeywordLatest, lower == KeywordStable, lower == KeywordSystem, lower == KeywordTip:
+		return fmt.Errorf("%w; name=%s; names start with a letter and must not be a keyword", errInvalidAlias, name)
+	}
+	if _, err := ParseVersion(name); err == nil {
+		return fmt.Errorf("%w; name=%s; names must not be versions", errInvalidAlias, name)
+	}
+	switch lower := strings.ToLower(target); {
+	case lower == KeywordLatest, lower == KeywordStable:
+		return nil
+	}
+	if _, ok, err := parseChannel(target); ok {
+		return err
+	}
+	if _, err := ParseVersion(target); err == nil {
+		return nil
+	}
+	if _, err := semver2.NewConstraint(target); err == nil {
+		return nil
+	}
+	return fmt.Errorf("%w; version=%s is no version, partial version, channel or constraint", errInvalidAlias, target)
+}
+
+// expandAlias returns the version argument named by the alias arg or arg itself if it names no alias
+func (e *executor) expandAlias(arg string) string {
+	cfg, err := e.config()
+	if err != nil {
+		return arg
+	}
+	if target, ok := cfg.Aliases[arg]; ok {
+		log.Debug().Msgf("expanded alias %s to %s", arg, target)
+		return target
+	}
+	return arg
+}
+
+// updateAliases applies update to the aliases of the user config file, keeping its other settings.
+// The aliases of the system and project configs are neither read nor written.
+func (e *executor) updateAliases(update func(aliases map[string]string) error) error {
+	doc := yaml.MapSlice{}
+	data, err := afero.ReadFile(e.Fs, e.ConfigFile)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read config file %s; err=%v", e.ConfigFile, err)
+	}
+	if err = yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse config file %s; err=%v", e.ConfigFile, err)
+	}
+	aliases := map[string]string{}
+	for _, item := range doc {
+		if item.Key != "aliases" {
+			continue
+		}
+		values, err := yaml.Marshal(item.Value)
+		if err == nil {
+			err = yaml.Unmarshal(values, &aliases)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to parse the aliases of config file %s; err=%v", e.ConfigFile, err)
+		}
+	}
+	if err = update(aliases); err != nil {
+		return err
+	}
+
+	found := false
+	for i := range doc {
+		if doc[i].Key == "aliases" {
+			doc[i].Value, found = aliases, true
+		}
+	}
+	if !found {
+		doc = append(doc, yaml.MapItem{Key: "aliases", Value: aliases})
+	}
+	if len(aliases) == 0 {
+		kept := doc[:0]
+		for _, item := range doc {
+			if item.Key != "aliases" {
+				kept = append(kept, item)
+			}
+		}
+		doc = kept
+	}
+
+	if data, err = yaml.Marshal(doc); err != nil {
+		return err
+	}
+	if err = e.Fs.MkdirAll(filepath.Dir(e.ConfigFile), os.ModePerm); err != nil {
+		return err
+	}
+	if err = afero.WriteFile(e.Fs, e.ConfigFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write config file %s; err=%v", e.ConfigFile, err)
+	}
+	return nil
+}
+
+// SetAlias names the version argument target
+func (e *executor) SetAlias(name, target string) error {
+	if err := validateAlias(name, target); err != nil {
+		return err
+	}
+	err := e.updateAliases(func(aliases map[string]string) error {
+		aliases[name] = target
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	e.infof("%s -> %s\n", name, target)
+	return nil
+}
+
+// aliasLayer returns the config layer the alias name of the effective config comes from
+func (e *executor) aliasLayer(name string) (configLayer, bool) {
+	_, origins, err := e.mergedConfig()
+	if err != nil {
+		return configLayer{}, false
+	}
+	origin, ok := origins["aliases."+name]
+	return origin.Layer, ok
+}
+
+// RemoveAlias removes the alias name from the user config
+func (e *executor) RemoveAlias(name string) error {
+	err := e.updateAliases(func(aliases map[string]string) error {
+		if _, ok := aliases[name]; !ok {
+			if layer, ok := e.aliasLayer(name); ok {
+				return fmt.Errorf("%w; name=%s; it is defined in the %s config %s, edit that file to remove it", errUnknownAlias, name, layer.Scope, layer.File)
+			}
+			return fmt.Errorf("%w; name=%s", errUnknownAlias, name)
+		}
+		delete(aliases, name)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	e.infof("removed alias %s\n", name)
+	return nil
+}
+
+// ListAliases prints the aliases sorted by name
+func (e *executor) ListAliases() error {
+	cfg, err := e.config()
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(cfg.Aliases))
+	for name := range cfg.Aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	w := tabwriter.NewWriter(e.Streams.Out, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAME\tVERSION")
+	for _, name := range names {
+		_, _ = fmt.Fprintf(w, "%s\t%s\n", name, cfg.Aliases[name])
+	}
+	return w.Flush()
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+func TestAliases(t *testing.T) {
+	testutils.Run(t, "Aliases", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		ConfigFile = filepath.Join(testutils.TempDir(t), "go.yaml")
+		server := newReleaseServer("1.22.1", "1.21.9", "1.21.8")
+		var out *Buffer
+
+		g.BeforeEach(func() {
+			createVersionDirs()
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+			_ = os.Remove(ConfigFile)
+		})
+
+		g.After(func() {
+			server.Close()
+		})
+
+		newSut := func() *executor {
+			out = &Buffer{&bytes.Buffer{}}
+			sut := defaultExecutor()
+			sut.URL = server.URL
+			sut.Streams.Out = out
+			return sut
+		}
+
+		g.It("resolves aliases everywhere a version is accepted", func() {
+			Ω(newSut().SetAlias("lts", "1.21")).Should(Succeed())
+			Ω(newSut().resolveVersion(context.Background(), "lts", remoteScope, false)).Should(Equal(Version("1.21.9")))
+			Ω(newSut().SetAlias("old", "1.16")).Should(Succeed())
+			Ω(newSut().resolveVersion(context.Background(), "old", installedScope, false)).Should(Equal(Version("1.16.8")))
+		})
+
+		g.It("keeps the other settings of the config", func() {
+			_ = os.MkdirAll(filepath.Dir(ConfigFile), os.ModePerm)
+			_ = os.WriteFile(ConfigFile, []byte("pins: [\"1.20\"]\n"), 0644)
+			Ω(newSut().SetAlias("lts", "1.21.9")).Should(Succeed())
+			cfg, err := newSut().config()
+			Ω(err).Should(Succeed())
+			Ω(cfg.Pins).Should(Equal([]string{"1.20"}))
+			Ω(cfg.Aliases).Should(Equal(map[string]string{"lts": "1.21.9"}))
+			Ω(validateConfig(mustReadFile(ConfigFile))).Should(BeEmpty())
+		})
+
+		g.It("lists the aliases", func() {
+			Ω(newSut().SetAlias("lts", "1.21.9")).Should(Succeed())
+			Ω(newSut().SetAlias("edge", "1.23@rc")).Should(Succeed())
+			sut := newSut()
+			Ω(sut.ListAliases()).Should(Succeed())
+			Ω(out.String()).Should(Equal("NAME  VERSION\nedge  1.23@rc\nlts   1.21.9\n"))
+		})
+
+		g.It("removes aliases", func() {
+			Ω(newSut().SetAlias("lts", "1.21.9")).Should(Succeed())
+			Ω(newSut().RemoveAlias("lts")).Should(Succeed())
+			Ω(mustReadFile(ConfigFile)).ShouldNot(ContainSubstring("aliases"))
+			Ω(errors.Is(newSut().RemoveAlias("lts"), errUnknownAlias)).Should(BeTrue())
+		})
+
+		g.It("only changes the aliases of the user config", func() {
+			system := filepath.Join(filepath.Dir(ConfigFile), "system.yaml")
+			_ = os.WriteFile(system, []byte("aliases:\n  corp: 1.21.8\n"), 0644)
+			defer os.Remove(system)
+			newLayeredSut := func() *executor {
+				sut := newSut()
+				sut.SystemConfigFile = system
+				return sut
+			}
+			Ω(newLayeredSut().SetAlias("lts", "1.21.9")).Should(Succeed())
+			Ω(mustReadFile(ConfigFile)).ShouldNot(ContainSubstring("corp"))
+			Ω(newLayeredSut().RemoveAlias("lts")).Should(Succeed())
+			Ω(mustReadFile(ConfigFile)).ShouldNot(ContainSubstring("aliases"))
+
+			err := newLayeredSut().RemoveAlias("corp")
+			Ω(errors.Is(err, errUnknownAlias)).Should(BeTrue())
+			Ω(err).Should(MatchError(ContainSubstring("it is defined in the system config " + system)))
+			Ω(mustReadFile(system)).Should(ContainSubstring("corp"))
+		})
+
+		g.It("rejects names which cannot be told apart from versions", func() {
+			for _, name := range []string{"1.21", "latest", "-", "v1.21.9"} {
+				Ω(errors.Is(newSut().SetAlias(name, "1.21.9"), errInvalidAlias)).Should(BeTrue(), name)
+			}
+		})
+
+		g.It("rejects keywords as names", func() {
+			for _, name := range []string{"latest", "Stable", "system", "tip"} {
+				Ω(errors.Is(newSut().SetAlias(name, "1.21.9"), errInvalidAlias)).Should(BeTrue(), name)
+			}
+		})
+
+		g.It("rejects invalid versions", func() {
+			Ω(errors.Is(newSut().SetAlias("lts", "one twenty"), errInvalidAlias)).Should(BeTrue())
+		})
+	})
+}
+
+func mustReadFile(file string) []byte {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// autoInstall reports whether use installs missing versions, which --install or auto_install of the config enable
+func (e *executor) autoInstall(flag bool) bool {
+	if flag {
+		return true
+	}
+	cfg, err := e.config()
+	return err == nil && cfg.AutoInstall
+}
+
+// resolveUseVersion resolves arg against the installed versions like use does.
+// If missing versions are installed, partial versions without installed release resolve against the release feed instead.
+func (e *executor) resolveUseVersion(ctx context.Context, arg string, includeUnstable, install bool) (Version, error) {
+	version, err := e.resolveVersion(ctx, arg, installedScope, includeUnstable)
+	if err != nil && install && errors.Is(err, errNoMatchingRelease) {
+		return e.resolveVersion(ctx, arg, remoteScope, includeUnstable)
+	}
+	return version, err
+}
+
+// missing reports whether version is neither installed nor an external sdk
+func (e *executor) missing(version Version) bool {
+	if _, err := e.versionPath(version); err == nil || version == SystemVersion {
+		return false
+	}
+	_, ok := e.external(version)
+	return !ok
+}
+
+// UseInstalling makes version the current version like Use and installs it first if it is missing
+func (e *executor) UseInstalling(version Version) error {
+	if e.missing(version) {
+		return e.InstallAndUse(version)
+	}
+	return e.Use(version)
+}
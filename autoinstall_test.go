@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+func TestUseInstalling(t *testing.T) {
+	testutils.Run(t, "use --install", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		server := newReleaseServer("1.22.1", "1.21.9")
+		var out *Buffer
+		var sut *executor
+		var externalRoots []string
+
+		g.BeforeEach(func() {
+			externalRoots, ExternalGoRoots = ExternalGoRoots, nil
+			createVersionDirs()
+			out = &Buffer{&bytes.Buffer{}}
+			sut = defaultExecutor()
+			sut.URL = server.URL
+			sut.ConfigFile = filepath.Join(InstallPath, "go.yaml")
+			sut.Streams.Out = out
+		})
+
+		g.AfterEach(func() {
+			ExternalGoRoots = externalRoots
+			_ = os.RemoveAll(InstallPath)
+		})
+
+		g.After(func() {
+			server.Close()
+		})
+
+		g.It("installs missing versions before linking them", func() {
+			Ω(sut.UseInstalling("1.22.1")).Should(Succeed())
+			Ω(filepath.Join(InstallPath, "1.22.1")).Should(BeADirectory())
+			Ω(sut.current()).Should(Equal(Version("1.22.1")))
+			Ω(out.String()).Should(Equal("installed go 1.22.1\nusing go 1.22.1\n"))
+		})
+
+		g.It("links installed versions without installing them", func() {
+			Ω(sut.UseInstalling("1.16.8")).Should(Succeed())
+			Ω(sut.current()).Should(Equal(Version("1.16.8")))
+			Ω(out.String()).Should(BeEmpty())
+		})
+
+		g.It("resolves partial versions against the release feed if no release is installed", func() {
+			_, err := sut.resolveUseVersion(context.Background(), "1.21", false, false)
+			Ω(errors.Is(err, errNoMatchingRelease)).Should(BeTrue())
+			Ω(sut.resolveUseVersion(context.Background(), "1.21", false, true)).Should(Equal(Version("1.21.9")))
+			Ω(sut.resolveUseVersion(context.Background(), "1.16", false, true)).Should(Equal(Version("1.16.8")))
+		})
+
+		g.It("installs missing versions if enabled in the config", func() {
+			Ω(sut.autoInstall(false)).Should(BeFalse())
+			Ω(sut.autoInstall(true)).Should(BeTrue())
+			_ = afero.WriteFile(afero.NewOsFs(), sut.ConfigFile, []byte("auto_install: true\n"), 0644)
+			Ω(sut.autoInstall(false)).Should(BeTrue())
+		})
+	})
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/alex-held/dfctl-kit/pkg/system"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+
+	"github.com/alex-held/dfctl-go/pkg/godist"
+)
+
+// bootstrapDir is the directory next to the install path holding the bootstrap toolchains of source builds,
+// which are kept apart from the installed versions so they are never listed, used or upgraded
+const bootstrapDir = "go-bootstrap"
+
+var errUnsuitableBootstrap = errors.New("unsuitable bootstrap toolchain")
+
+func newBootstrapCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bootstrap",
+		Short: "manages the go toolchains used to build go from source",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list-remote",
+		Short: "lists the bootstrap toolchain required to build each release line from source",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateArgsForSubcommand("bootstrap list-remote", args, 0); err != nil {
+				return err
+			}
+			return defaultExecutor().ListRemoteBootstraps(c.Context())
+		},
+	})
+	cmd.AddCommand(mutating(&cobra.Command{
+		Use:   "install <version>",
+		Short: "installs the bootstrap toolchain required to build version from source",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateArgsForSubcommand("bootstrap install", args, 1); err != nil {
+				return err
+			}
+			e := defaultExecutor()
+			version, err := e.resolveVersion(c.Context(), args[0], remoteScope, true)
+			if err != nil {
+				return err
+			}
+			_, err = e.InstallBootstrap(c.Context(), version)
+			return err
+		},
+	}))
+	return cmd
+}
+
+// bootstrapPath returns the directory holding the bootstrap toolchains
+func (e *executor) bootstrapPath() string {
+	return filepath.Join(filepath.Dir(e.InstallPath), bootstrapDir)
+}
+
+// bootstrapFor returns the toolchain bootstrapping a source build of target:
+// the newest stable release of the release feed older than the release train of target which satisfies the
+// documented minimum, or the minimum itself if the feed has no such release
+func bootstrapFor(target Version, remote []Version) (Version, error) {
+	minimum, err := godist.BootstrapVersion(target.String())
+	if err != nil {
+		return "", err
+	}
+	min, err := ParseVersion(minimum)
+	if err != nil {
+		return "", err
+	}
+	for _, v := range remote {
+		if v.Compare(min) >= 0 && Version(v.Minor()+".0").Compare(Version(target.Minor()+".0")) < 0 {
+			return v, nil
+		}
+	}
+	return min, nil
+}
+
+// InstallBootstrap installs the bootstrap toolchain of target into the bootstrap directory unless it is installed
+// and returns its GOROOT
+func (e *executor) InstallBootstrap(ctx context.Context, target Version) (string, error) {
+	remote, err := e.remoteVersions(ctx, false)
+	if err != nil {
+		log.Debug().Err(err).Msg("resolving the bootstrap toolchain without the release feed")
+	}
+	bootstrap, err := bootstrapFor(target, remote)
+	if err != nil {
+		return "", err
+	}
+	return e.installBootstrapVersion(bootstrap, target)
+}
+
+// installBootstrapVersion installs go bootstrap into the bootstrap directory unless it is installed and returns its GOROOT
+func (e *executor) installBootstrapVersion(bootstrap, target Version) (string, error) {
+	root := filepath.Join(e.bootstrapPath(), bootstrap.String())
+	if exists, _ := afero.DirExists(e.Fs, root); exists {
+		log.Debug().Msgf("bootstrap toolchain go %s is installed at %s", bootstrap, root)
+		return root, nil
+	}
+	archive, err := e.dlArchive(bootstrap)
+	if err != nil {
+		return "", err
+	}
+	if err = e.installArchiveInto(bootstrap, archive, e.artifactURL(system.OSRuntimeInfoGetter{}.Get(), bootstrap), root); err != nil {
+		return "", err
+	}
+	_, _ = fmt.Fprintf(e.Streams.Out, "installed bootstrap toolchain go %s for go %s\n", bootstrap, target)
+	return root, nil
+}
+
+// suitableBootstrap reports whether v can bootstrap a source build of target:
+// it must be a stable release satisfying the documented minimum of an older release train than target
+func suitableBootstrap(v, target Version) (bool, error) {
+	minimum, err := godist.BootstrapVersion(target.String())
+	if err != nil {
+		return false, err
+	}
+	min, err := ParseVersion(minimum)
+	if err != nil {
+		return false, err
+	}
+	return v.IsStable() && v.Compare(min) >= 0 && Version(v.Minor()+".0").Compare(Version(target.Minor()+".0")) < 0, nil
+}
+
+// bootstrapCandidate is a go sdk on the machine which may bootstrap a source build
+type bootstrapCandidate struct {
+	Version Version
+	Root    string
+}
+
+// bootstrapCandidates returns the installed bootstrap toolchains, the installed versions and the system go
+func (e *executor) bootstrapCandidates() []bootstrapCandidate {
+	var candidates []bootstrapCandidate
+	if dirs, err := afero.ReadDir(e.Fs, e.bootstrapPath()); err == nil {
+		for _, dir := range dirs {
+			if v, err := ParseVersion(dir.Name()); err == nil && dir.IsDir() {
+				candidates = append(candidates, bootstrapCandidate{Version: v, Root: filepath.Join(e.bootstrapPath(), dir.Name())})
+			}
+		}
+	}
+	if installs, err := e.installations(); err == nil {
+		for _, i := range installs {
+			candidates = append(candidates, bootstrapCandidate{Version: i.Version, Root: filepath.Join(e.InstallPath, i.Dir)})
+		}
+	}
+	if root, err := e.systemGoroot(); err == nil {
+		if v, err := sdkVersion(e.Fs, root); err == nil {
+			candidates = append(candidates, bootstrapCandidate{Version: v, Root: root})
+		}
+	}
+	return candidates
+}
+
+// resolveBootstrap returns the GOROOT of the toolchain bootstrapping a source build of target.
+// The toolchain pinned with --bootstrap or the bootstrap key of the config, either a version or the GOROOT of a go sdk, is used if set.
+// Otherwise the newest suitable installed bootstrap toolchain, installed version or system go is used,
+// and the bootstrap toolchain of target is installed if there is none.
+func (e *executor) resolveBootstrap(ctx context.Context, target Version) (string, error) {
+	pin := e.Bootstrap
+	if pin == "" {
+		cfg, err := e.config()
+		if err != nil {
+			return "", err
+		}
+		pin = cfg.Bootstrap
+	}
+	if pin != "" {
+		return e.pinnedBootstrap(pin, target)
+	}
+
+	var best bootstrapCandidate
+	for _, c := range e.bootstrapCandidates() {
+		if ok, err := suitableBootstrap(c.Version, target); err != nil {
+			return "", err
+		} else if ok && (best.Root == "" || c.Version.Compare(best.Version) > 0) {
+			best = c
+		}
+	}
+	if best.Root != "" {
+		log.Debug().Msgf("bootstrapping go %s with go %s at %s", target, best.Version, best.Root)
+		return best.Root, nil
+	}
+	return e.InstallBootstrap(ctx, target)
+}
+
+// pinnedBootstrap returns the GOROOT of the pinned bootstrap toolchain, which is installed into the bootstrap directory
+// if it is a version which is neither installed nor managed externally
+func (e *executor) pinnedBootstrap(pin string, target Version) (string, error) {
+	var root string
+	var version Version
+	if exists, _ := afero.DirExists(e.Fs, pin); exists {
+		v, err := sdkVersion(e.Fs, pin)
+		if err != nil {
+			return "", fmt.Errorf("%w; bootstrap=%s; err=%v", errUnsuitableBootstrap, pin, err)
+		}
+		if root, err = filepath.Abs(pin); err != nil {
+			return "", err
+		}
+		version = v
+	} else {
+		v, err := ParseVersion(pin)
+		if err != nil {
+			return "", fmt.Errorf("%w; bootstrap=%s; pass a version or the GOROOT of a go sdk", errUnsuitableBootstrap, pin)
+		}
+		version = v
+	}
+	ok, err := suitableBootstrap(version, target)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		minimum, _ := godist.BootstrapVersion(target.String())
+		return "", fmt.Errorf("%w; bootstrap=%s; go %s needs a release of go %s or newer older than go %s", errUnsuitableBootstrap, version, target, minimum, target.Minor())
+	}
+	if root != "" {
+		return root, nil
+	}
+	if root, err = e.goroot(version); err == nil {
+		return root, nil
+	}
+	return e.installBootstrapVersion(version, target)
+}
+
+// ListRemoteBootstraps prints the bootstrap toolchain of the newest release of every release train of the feed
+func (e *executor) ListRemoteBootstraps(ctx context.Context) error {
+	remote, err := e.remoteVersions(ctx, false)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(e.Streams.Out, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "MINOR\tREQUIRES\tBOOTSTRAP\tINSTALLED")
+	seen := map[string]bool{}
+	for _, v := range remote {
+		if seen[v.Minor()] {
+			continue
+		}
+		seen[v.Minor()] = true
+		minimum, err := godist.BootstrapVersion(v.String())
+		if err != nil {
+			continue
+		}
+		bootstrap, err := bootstrapFor(v, remote)
+		if err != nil {
+			continue
+		}
+		installed, _ := afero.DirExists(e.Fs, filepath.Join(e.bootstrapPath(), bootstrap.String()))
+		_, _ = fmt.Fprintf(w, "%s\t>=%s\t%s\t%t\n", v.Minor(), minimum, bootstrap, installed)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/env"
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestBootstrap(t *testing.T) {
+	testutils.Run(t, "Bootstrap", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		server := newReleaseServer("1.23.2", "1.22.8", "1.21.13", "1.20.14")
+		var out *Buffer
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(filepath.Dir(InstallPath))
+		})
+
+		g.After(func() {
+			server.Close()
+		})
+
+		newSut := func() *executor {
+			out = &Buffer{&bytes.Buffer{}}
+			sut := defaultExecutor()
+			sut.URL = server.URL
+			sut.Streams.Out = out
+			return sut
+		}
+
+		g.It("selects the newest release older than the target satisfying the minimum", func() {
+			remote := []Version{"1.23.2", "1.22.8", "1.21.13", "1.20.14", "1.20.5"}
+			Ω(bootstrapFor("1.23.2", remote)).Should(Equal(Version("1.22.8")))
+			Ω(bootstrapFor("1.22.1", remote)).Should(Equal(Version("1.21.13")))
+			Ω(bootstrapFor("1.24.0", []Version{"1.23.2", "1.22.5"})).Should(Equal(Version("1.23.2")))
+			Ω(bootstrapFor("1.26.0", remote)).Should(Equal(Version("1.24.6")))
+		})
+
+		g.It("installs the bootstrap toolchain apart from the installed versions", func() {
+			sut := newSut()
+			root, err := sut.InstallBootstrap(context.Background(), "1.23.2")
+			Ω(err).Should(Succeed())
+			Ω(root).Should(Equal(filepath.Join(filepath.Dir(InstallPath), bootstrapDir, "1.22.8")))
+			Ω(filepath.Join(root, "bin", "go")).Should(BeARegularFile())
+			Ω(filepath.Join(InstallPath, "1.22.8")).ShouldNot(BeADirectory())
+			Ω(out.String()).Should(Equal("installed bootstrap toolchain go 1.22.8 for go 1.23.2\n"))
+		})
+
+		g.It("reuses installed bootstrap toolchains", func() {
+			_, err := newSut().InstallBootstrap(context.Background(), "1.23.2")
+			Ω(err).Should(Succeed())
+			_, err = newSut().InstallBootstrap(context.Background(), "1.23.0")
+			Ω(err).Should(Succeed())
+			Ω(out.String()).Should(BeEmpty())
+		})
+
+		g.It("lists the bootstrap toolchains of the remote release lines", func() {
+			_, err := newSut().InstallBootstrap(context.Background(), "1.23.2")
+			Ω(err).Should(Succeed())
+			sut := newSut()
+			Ω(sut.ListRemoteBootstraps(context.Background())).Should(Succeed())
+			Ω(out.String()).Should(Equal("" +
+				"MINOR  REQUIRES   BOOTSTRAP  INSTALLED\n" +
+				"1.23   >=1.20.6   1.22.8     true\n" +
+				"1.22   >=1.20.6   1.21.13    false\n" +
+				"1.21   >=1.17.13  1.20.14    false\n" +
+				"1.20   >=1.17.13  1.17.13    false\n"))
+		})
+	})
+}
+
+func TestResolveBootstrap(t *testing.T) {
+	testutils.Run(t, "resolveBootstrap", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		server := newReleaseServer("1.23.2", "1.22.8", "1.21.13", "1.20.14")
+		system := testutils.TempDir(t, "system")
+		var out *Buffer
+
+		g.BeforeEach(func() {
+			env.Overrides.Vars = env.Vars{"PATH": ""}
+		})
+
+		g.AfterEach(func() {
+			env.ClearOverrides()
+			_ = os.RemoveAll(filepath.Dir(InstallPath))
+			_ = os.RemoveAll(system)
+		})
+
+		g.After(func() {
+			server.Close()
+		})
+
+		newSut := func() *executor {
+			out = &Buffer{&bytes.Buffer{}}
+			sut := defaultExecutor()
+			sut.URL = server.URL
+			sut.ConfigFile = filepath.Join(filepath.Dir(InstallPath), "go.yaml")
+			sut.Streams.Out = out
+			return sut
+		}
+
+		g.It("uses the newest suitable installed version", func() {
+			createVersionDirs()
+			Ω(newSut().resolveBootstrap(context.Background(), "1.18.0")).Should(Equal(filepath.Join(InstallPath, "v1.17.1")))
+			Ω(filepath.Join(filepath.Dir(InstallPath), bootstrapDir)).ShouldNot(BeADirectory())
+		})
+
+		g.It("uses a suitable system go", func() {
+			writeGoRoot(system, "go1.21.5")
+			env.Overrides.Vars = env.Vars{"PATH": filepath.Join(system, "bin")}
+			Ω(newSut().resolveBootstrap(context.Background(), "1.22.1")).Should(Equal(system))
+		})
+
+		g.It("installs the bootstrap toolchain if no suitable go sdk is found", func() {
+			createVersionDirs()
+			writeGoRoot(system, "go1.22.0")
+			env.Overrides.Vars = env.Vars{"PATH": filepath.Join(system, "bin")}
+			Ω(newSut().resolveBootstrap(context.Background(), "1.22.1")).Should(Equal(filepath.Join(filepath.Dir(InstallPath), bootstrapDir, "1.21.13")))
+			Ω(out.String()).Should(Equal("installed bootstrap toolchain go 1.21.13 for go 1.22.1\n"))
+		})
+
+		g.It("installs the version pinned in the config", func() {
+			sut := newSut()
+			_ = os.MkdirAll(filepath.Dir(sut.ConfigFile), os.ModePerm)
+			_ = os.WriteFile(sut.ConfigFile, []byte("bootstrap: 1.20.14\n"), 0644)
+			Ω(sut.resolveBootstrap(context.Background(), "1.22.1")).Should(Equal(filepath.Join(filepath.Dir(InstallPath), bootstrapDir, "1.20.14")))
+		})
+
+		g.It("prefers the pinned goroot over the config", func() {
+			writeGoRoot(system, "go1.21.5")
+			sut := newSut()
+			_ = os.MkdirAll(filepath.Dir(sut.ConfigFile), os.ModePerm)
+			_ = os.WriteFile(sut.ConfigFile, []byte("bootstrap: 1.20.14\n"), 0644)
+			sut.Bootstrap = system
+			Ω(sut.resolveBootstrap(context.Background(), "1.22.1")).Should(Equal(system))
+		})
+
+		g.It("rejects pinned versions which cannot bootstrap the target", func() {
+			sut := newSut()
+			sut.Bootstrap = "1.22.8"
+			_, err := sut.resolveBootstrap(context.Background(), "1.22.1")
+			Ω(errors.Is(err, errUnsuitableBootstrap)).Should(BeTrue())
+			sut.Bootstrap = "1.19.13"
+			_, err = sut.resolveBootstrap(context.Background(), "1.22.1")
+			Ω(errors.Is(err, errUnsuitableBootstrap)).Should(BeTrue())
+		})
+	})
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+	"runtime/debug"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// buildInfo describes the running dfctl-go binary for bug reports
+type buildInfo struct {
+	Version string `json:"version"`
+	Commit  string `json:"commit"`
+	Date    string `json:"date"`
+	BuiltBy string `json:"built_by"`
+	// GoVersion is the go runtime the binary was built with
+	GoVersion string `json:"go_version"`
+	Platform  string `json:"platform"`
+}
+
+// currentBuildInfo returns the build info set by release.sh with -ldflags.
+// Binaries built with go install report the module version instead of dev.
+func currentBuildInfo() buildInfo {
+	info := buildInfo{
+		Version:   version,
+		Commit:    commit,
+		Date:      date,
+		BuiltBy:   builtBy,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok && info.Version == "dev" && bi.Main.Version != "" && bi.Main.Version != "(devel)" {
+		info.Version = bi.Main.Version
+	}
+	return info
+}
+
+// write prints the build info as aligned key value lines
+func (b buildInfo) write(out io.Writer) error {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintf(w, "version:\t%s\n", b.Version)
+	_, _ = fmt.Fprintf(w, "commit:\t%s\n", b.Commit)
+	_, _ = fmt.Fprintf(w, "built at:\t%s\n", b.Date)
+	_, _ = fmt.Fprintf(w, "built by:\t%s\n", b.BuiltBy)
+	_, _ = fmt.Fprintf(w, "go version:\t%s\n", b.GoVersion)
+	_, _ = fmt.Fprintf(w, "platform:\t%s\n", b.Platform)
+	return w.Flush()
+}
+
+// versionTemplate is printed by --version; it matches the output of the version command
+func versionTemplate() string {
+	buf := &bytes.Buffer{}
+	_ = currentBuildInfo().write(buf)
+	return buf.String()
+}
+
+func newVersionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "version",
+		Short: "prints the version and build info of dfctl-go",
+		Long:  "prints the version, commit, build date, builder, go runtime version and platform of dfctl-go, which bug reports should contain",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateArgsForSubcommand("version", args, 0); err != nil {
+				return err
+			}
+			return defaultExecutor().Version(outputFormat == outputJSON)
+		},
+	}
+	addJSONAlias(cmd)
+	return cmd
+}
+
+// Version prints the build info of the running binary; --quiet only prints the version
+func (e *executor) Version(asJSON bool) error {
+	info := currentBuildInfo()
+	if asJSON {
+		enc := json.NewEncoder(e.Streams.Out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(info)
+	}
+	if e.Quiet {
+		_, _ = fmt.Fprintln(e.Streams.Out, info.Version)
+		return nil
+	}
+
+	return info.write(e.Streams.Out)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"runtime"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestVersionCmd(t *testing.T) {
+	testutils.Run(t, "Version", func(g *goblin.G) {
+		var out *Buffer
+
+		g.BeforeEach(func() {
+			out = &Buffer{&bytes.Buffer{}}
+		})
+
+		g.It("prints the build info", func() {
+			sut := defaultExecutor()
+			sut.Streams.Out = out
+			Ω(sut.Version(false)).Should(Succeed())
+			Ω(out.String()).Should(ContainSubstring("version:     " + currentBuildInfo().Version + "\n"))
+			Ω(out.String()).Should(ContainSubstring("commit:      none\n"))
+			Ω(out.String()).Should(ContainSubstring("go version:  " + runtime.Version() + "\n"))
+			Ω(out.String()).Should(ContainSubstring("platform:    " + runtime.GOOS + "/" + runtime.GOARCH + "\n"))
+		})
+
+		g.It("prints the build info as json", func() {
+			sut := defaultExecutor()
+			sut.Streams.Out = out
+			Ω(sut.Version(true)).Should(Succeed())
+			var info buildInfo
+			Ω(json.Unmarshal(out.Bytes(), &info)).Should(Succeed())
+			Ω(info).Should(Equal(currentBuildInfo()))
+			Ω(out.String()).Should(ContainSubstring(`"built_by": "unknown"`))
+		})
+
+		g.It("only prints the version with --quiet", func() {
+			sut := defaultExecutor()
+			sut.Streams.Out = out
+			sut.Quiet = true
+			Ω(sut.Version(false)).Should(Succeed())
+			Ω(out.String()).Should(Equal(currentBuildInfo().Version + "\n"))
+		})
+
+		g.It("prints the build info for --version", func() {
+			Ω(versionTemplate()).Should(HavePrefix("version:     " + currentBuildInfo().Version + "\n"))
+		})
+	})
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// exitTotalFailure is the exit code of bulk operations where every item failed
+	exitTotalFailure = 1
+	// exitPartialFailure is the exit code of bulk operations where only some items failed
+	exitPartialFailure = 2
+)
+
+// bulkFailure is the failure of a single item of a bulk operation
+type bulkFailure struct {
+	Item string
+	Err  error
+}
+
+// bulkError aggregates the failures of a bulk operation which continued past individual failures
+type bulkError struct {
+	// Op describes the operation, e.g. "upgrade"
+	Op string
+	// Noun describes the items, e.g. "minor lines"
+	Noun     string
+	Total    int
+	Failures []bulkFailure
+}
+
+// newBulkError returns the aggregated error of the failures or nil if nothing failed
+func newBulkError(op, noun string, total int, failures []bulkFailure) error {
+	if len(failures) == 0 {
+		return nil
+	}
+	return &bulkError{Op: op, Noun: noun, Total: total, Failures: failures}
+}
+
+func (b *bulkError) Error() string {
+	items := make([]string, 0, len(b.Failures))
+	for _, f := range b.Failures {
+		items = append(items, f.Item)
+	}
+	return fmt.Sprintf("failed to %s %d of %d %s; failed=%s", b.Op, len(b.Failures), b.Total, b.Noun, strings.Join(items, ","))
+}
+
+// Partial reports whether some items of the bulk operation succeeded
+func (b *bulkError) Partial() bool {
+	return len(b.Failures) < b.Total
+}
+
+// ExitCode returns the exit code reflecting partial or total failure
+func (b *bulkError) ExitCode() int {
+	if b.Partial() {
+		return exitPartialFailure
+	}
+	return exitTotalFailure
+}
+
+// Is reports whether any of the aggregated failures matches target
+func (b *bulkError) Is(target error) bool {
+	for _, f := range b.Failures {
+		if errors.Is(f.Err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// render writes a table of the failed items to w
+func (b *bulkError) render(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintf(tw, "FAILED\tERROR\n")
+	for _, f := range b.Failures {
+		_, _ = fmt.Fprintf(tw, "%s\t%v\n", f.Item, f.Err)
+	}
+	_ = tw.Flush()
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+func TestBulkError(t *testing.T) {
+	testutils.Run(t, "BulkError", func(g *goblin.G) {
+		g.It("is nil without failures", func() {
+			Ω(newBulkError("upgrade", "minor lines", 3, nil)).Should(BeNil())
+		})
+
+		g.It("reports partial failures", func() {
+			err := newBulkError("upgrade", "minor lines", 3, []bulkFailure{{Item: "1.20", Err: errNoMatchingRelease}})
+			var bulkErr *bulkError
+			Ω(errors.As(err, &bulkErr)).Should(BeTrue())
+			Ω(err.Error()).Should(Equal("failed to upgrade 1 of 3 minor lines; failed=1.20"))
+			Ω(bulkErr.Partial()).Should(BeTrue())
+			Ω(bulkErr.ExitCode()).Should(Equal(exitPartialFailure))
+		})
+
+		g.It("reports total failures", func() {
+			err := newBulkError("verify", "versions", 2, []bulkFailure{
+				{Item: "1.20.1", Err: errModified},
+				{Item: "1.21.3", Err: errNoManifest},
+			})
+			Ω(err.(*bulkError).ExitCode()).Should(Equal(exitTotalFailure))
+		})
+
+		g.It("matches the aggregated errors", func() {
+			err := newBulkError("verify", "versions", 2, []bulkFailure{{Item: "1.20.1", Err: fmt.Errorf("wrapped; %w", errModified)}})
+			Ω(errors.Is(err, errModified)).Should(BeTrue())
+			Ω(errors.Is(err, errNoManifest)).Should(BeFalse())
+		})
+
+		g.It("renders a failure table", func() {
+			buf := &bytes.Buffer{}
+			newBulkError("verify", "versions", 2, []bulkFailure{
+				{Item: "1.20.1", Err: errModified},
+				{Item: "1.21.3", Err: errNoManifest},
+			}).(*bulkError).render(buf)
+			Ω(buf.String()).Should(MatchRegexp(`FAILED\s+ERROR\n1\.20\.1\s+` + errModified.Error() + `\n1\.21\.3\s+` + errNoManifest.Error()))
+		})
+	})
+}
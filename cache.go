@@ -0,0 +1,251 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/alex-held/dfctl-kit/pkg/env"
+	"github.com/alex-held/dfctl-kit/pkg/system"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+// CacheEnv configures the directory of the archive cache, e.g. to share it between machines
+const CacheEnv = "DFCTL_GO_CACHE"
+
+var errNotCached = errors.New("archive is not cached")
+
+// CachePath is the directory of the archive cache
+var CachePath = cachePath()
+
+func cachePath() string {
+	if dir := env.GetVars().Get(CacheEnv); dir != "" {
+		return dir
+	}
+	return filepath.Join(env.Home(), "cache", "go")
+}
+
+// cachedArchive is an archive in the cache at <version>/<os>-<arch>/<sha256>/<filename>
+type cachedArchive struct {
+	Version  Version
+	Platform string
+	SHA256   string
+	Path     string
+	Size     int64
+}
+
+func newCacheCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "manages the cache of downloaded archives",
+		Long:  "manages the cache of downloaded archives, which installs use instead of downloading the archive again; set " + CacheEnv + " to move or share the cache",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "dir",
+		Short: "prints the cache directory",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateArgsForSubcommand("cache dir", args, 0); err != nil {
+				return err
+			}
+			e := defaultExecutor()
+			_, _ = fmt.Fprintln(e.Streams.Out, e.CachePath)
+			return nil
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "lists the cached archives",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateArgsForSubcommand("cache list", args, 0); err != nil {
+				return err
+			}
+			return defaultExecutor().ListCache()
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "size",
+		Short: "prints the size of the cache",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateArgsForSubcommand("cache size", args, 0); err != nil {
+				return err
+			}
+			e := defaultExecutor()
+			size, err := dirSize(e.Fs, e.CachePath)
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			_, _ = fmt.Fprintln(e.Streams.Out, formatBytes(size))
+			return nil
+		},
+	})
+	cmd.AddCommand(mutating(&cobra.Command{
+		Use:   "clean",
+		Short: "removes all cached archives",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateArgsForSubcommand("cache clean", args, 0); err != nil {
+				return err
+			}
+			return defaultExecutor().CleanCache()
+		},
+	}))
+	return cmd
+}
+
+// cacheFile returns the path of the archive f in the cache
+func (e *executor) cacheFile(f ReleaseFile) string {
+	return filepath.Join(e.CachePath, MustParseVersion(f.Version).String(), f.OS+"-"+f.Arch, f.SHA256, f.Filename)
+}
+
+// cachedArchive returns the cached copy of the archive f, if its checksum matches
+func (e *executor) cachedArchive(f ReleaseFile) (*bytes.Buffer, bool) {
+	if f.SHA256 == "" {
+		return nil, false
+	}
+	data, err := afero.ReadFile(e.Fs, e.cacheFile(f))
+	if err != nil {
+		return nil, false
+	}
+	if sum := sha256.Sum256(data); hex.EncodeToString(sum[:]) != f.SHA256 {
+		log.Warn().Msgf("ignoring corrupt cached archive %s", e.cacheFile(f))
+		return nil, false
+	}
+	log.Debug().Msgf("using cached archive %s", e.cacheFile(f))
+	return bytes.NewBuffer(data), true
+}
+
+// cacheArchive stores the downloaded archive f in the cache after verifying its checksum
+func (e *executor) cacheArchive(f ReleaseFile, data []byte) error {
+	if sum := sha256.Sum256(data); hex.EncodeToString(sum[:]) != f.SHA256 {
+		return fmt.Errorf("%w; file=%s; expected=%s; actual=%s", errChecksumMismatch, f.Filename, f.SHA256, hex.EncodeToString(sum[:]))
+	}
+	target := e.cacheFile(f)
+	if err := e.Fs.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+		return err
+	}
+	if err := afero.WriteFile(e.Fs, target+".partial", data, 0644); err != nil {
+		return err
+	}
+	return e.Fs.Rename(target+".partial", target)
+}
+
+// dlCachedArchive returns the archive f from the cache or downloads and caches it
+func (e *executor) dlCachedArchive(ctx context.Context, f ReleaseFile) (*bytes.Buffer, error) {
+	if archive, ok := e.cachedArchive(f); ok {
+		return archive, nil
+	}
+	if archive, ok, err := e.dlZstdArchive(ctx, f.Filename); ok || err != nil {
+		return archive, err
+	}
+	buf := &bytes.Buffer{}
+	if err := e.downloadWithProgress(ctx, e.URL+"/dl/"+f.Filename, buf, f.Filename); err != nil {
+		return nil, err
+	}
+	if err := e.cacheArchive(f, buf.Bytes()); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// cachedArchiveOf returns a cached archive of version for the platform without consulting the release feed;
+// the checksum of the archive is verified against the checksum in its cache key
+func (e *executor) cachedArchiveOf(version Version, ri system.RuntimeInfo) (*bytes.Buffer, error) {
+	archives, err := e.cachedArchives()
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range archives {
+		if a.Version.Compare(version) != 0 || a.Platform != ri.OS+"-"+ri.Arch {
+			continue
+		}
+		f := ReleaseFile{Filename: filepath.Base(a.Path), OS: ri.OS, Arch: ri.Arch, Version: "go" + version.GoName(), SHA256: a.SHA256}
+		if archive, ok := e.cachedArchive(f); ok {
+			return archive, nil
+		}
+	}
+	return nil, fmt.Errorf("%w; version=%s; platform=%s-%s; install it once while online to cache it", errNotCached, version, ri.OS, ri.Arch)
+}
+
+// cachedVersions returns the versions with cached archives for the platform in descending order
+func (e *executor) cachedVersions(includeUnstable bool) (versions []Version, err error) {
+	archives, err := e.cachedArchives()
+	if err != nil {
+		return nil, err
+	}
+	ri := system.OSRuntimeInfoGetter{}.Get()
+	seen := map[Version]bool{}
+	for _, a := range archives {
+		if a.Platform == ri.OS+"-"+ri.Arch && !seen[a.Version] && (includeUnstable || a.Version.IsStable()) {
+			seen[a.Version] = true
+			versions = append(versions, a.Version)
+		}
+	}
+	sortVersions(versions)
+	return versions, nil
+}
+
+// cachedArchives returns the archives in the cache
+func (e *executor) cachedArchives() (archives []cachedArchive, err error) {
+	files, err := afero.Glob(e.Fs, filepath.Join(e.CachePath, "*", "*", "*", "*"))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		fi, err := e.Fs.Stat(file)
+		if err != nil || !fi.Mode().IsRegular() || filepath.Ext(file) == ".partial" {
+			continue
+		}
+		shaDir := filepath.Dir(file)
+		platformDir := filepath.Dir(shaDir)
+		version, err := ParseVersion(filepath.Base(filepath.Dir(platformDir)))
+		if err != nil {
+			continue
+		}
+		archives = append(archives, cachedArchive{
+			Version:  version,
+			Platform: filepath.Base(platformDir),
+			SHA256:   filepath.Base(shaDir),
+			Path:     file,
+			Size:     fi.Size(),
+		})
+	}
+	return archives, nil
+}
+
+// ListCache prints the cached archives
+func (e *executor) ListCache() error {
+	archives, err := e.cachedArchives()
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(e.Streams.Out, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "VERSION\tPLATFORM\tSIZE\tSHA256")
+	for _, a := range archives {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", a.Version, a.Platform, formatBytes(a.Size), a.SHA256)
+	}
+	return w.Flush()
+}
+
+// CleanCache removes the cache and prints the freed disk space
+func (e *executor) CleanCache() error {
+	size, err := dirSize(e.Fs, e.CachePath)
+	if os.IsNotExist(err) {
+		_, _ = fmt.Fprintln(e.Streams.Out, "cache is empty")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err = e.Fs.RemoveAll(e.CachePath); err != nil {
+		return fmt.Errorf("failed to remove the cache %s; err=%v", e.CachePath, err)
+	}
+	_, _ = fmt.Fprintf(e.Streams.Out, "freed %s\n", formatBytes(size))
+	return nil
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/system"
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestCache(t *testing.T) {
+	testutils.Run(t, "Cache", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		server := newReleaseServer("1.22.1")
+		var out *Buffer
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(CachePath)
+		})
+
+		g.After(func() {
+			server.Close()
+		})
+
+		newSut := func() *executor {
+			out = &Buffer{&bytes.Buffer{}}
+			sut := defaultExecutor()
+			sut.URL = server.URL
+			sut.Streams.Out = out
+			return sut
+		}
+
+		g.It("caches downloaded archives", func() {
+			archive, err := newSut().dlArchive("1.22.1")
+			Ω(err).Should(Succeed())
+			Ω(archive.Bytes()).Should(Equal(archiveData))
+
+			archives, err := newSut().cachedArchives()
+			Ω(err).Should(Succeed())
+			Ω(archives).Should(HaveLen(1))
+			Ω(archives[0].Version).Should(Equal(Version("1.22.1")))
+			Ω(archives[0].Size).Should(Equal(int64(len(archiveData))))
+		})
+
+		g.It("uses cached archives instead of downloading them", func() {
+			sut := newSut()
+			_, err := sut.dlArchive("1.22.1")
+			Ω(err).Should(Succeed())
+			f, err := sut.releaseFile(context.Background(), formatGoArchiveArtifactName(system.OSRuntimeInfoGetter{}.Get(), "1.22.1"))
+			Ω(err).Should(Succeed())
+
+			archive, ok := newSut().cachedArchive(f)
+			Ω(ok).Should(BeTrue())
+			Ω(archive.Bytes()).Should(Equal(archiveData))
+		})
+
+		g.It("ignores corrupt cached archives", func() {
+			sut := newSut()
+			_, _ = sut.dlArchive("1.22.1")
+			archives, _ := sut.cachedArchives()
+			_ = os.WriteFile(archives[0].Path, []byte("corrupt"), 0644)
+			f, _ := sut.releaseFile(context.Background(), filepath.Base(archives[0].Path))
+
+			_, ok := sut.cachedArchive(f)
+			Ω(ok).Should(BeFalse())
+		})
+
+		g.It("lists and cleans the cache", func() {
+			sut := newSut()
+			_, _ = sut.dlArchive("1.22.1")
+			Ω(sut.ListCache()).Should(Succeed())
+			Ω(out.String()).Should(ContainSubstring("1.22.1"))
+
+			sut = newSut()
+			Ω(sut.CleanCache()).Should(Succeed())
+			Ω(out.String()).Should(ContainSubstring("freed"))
+			Ω(CachePath).ShouldNot(BeADirectory())
+		})
+	})
+}
+
+func TestOfflineInstall(t *testing.T) {
+	testutils.Run(t, "offline install", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		server := newReleaseServer("1.22.1", "1.21.5")
+
+		g.BeforeEach(func() {
+			sut := defaultExecutor()
+			sut.URL = server.URL
+			_, err := sut.dlArchive("1.22.1")
+			Ω(err).Should(Succeed())
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(CachePath)
+		})
+
+		g.After(func() {
+			server.Close()
+		})
+
+		newSut := func(offline bool) *executor {
+			sut := defaultExecutor()
+			sut.URL = "http://127.0.0.1:1"
+			sut.Offline = offline
+			return sut
+		}
+
+		g.It("installs cached archives without network", func() {
+			archive, err := newSut(false).dlArchive("1.22.1")
+			Ω(err).Should(Succeed())
+			Ω(archive.Bytes()).Should(Equal(archiveData))
+		})
+
+		g.It("installs cached archives offline", func() {
+			archive, err := newSut(true).dlArchive("1.22.1")
+			Ω(err).Should(Succeed())
+			Ω(archive.Bytes()).Should(Equal(archiveData))
+		})
+
+		g.It("fails offline for archives missing in the cache", func() {
+			_, err := newSut(true).dlArchive("1.21.5")
+			Ω(errors.Is(err, errNotCached)).Should(BeTrue())
+		})
+
+		g.It("resolves versions against the cache offline", func() {
+			Ω(newSut(true).resolveVersion(context.Background(), "1.22", remoteScope, false)).Should(Equal(Version("1.22.1")))
+			Ω(newSut(true).resolveVersion(context.Background(), "stable", remoteScope, false)).Should(Equal(Version("1.22.1")))
+		})
+	})
+}
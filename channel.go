@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// ChannelRC follows the release candidates of a release train until its final release is published, e.g. 1.23@rc
+const ChannelRC = "rc"
+
+var errUnknownChannel = errors.New("unknown release channel")
+
+// channelPattern matches channel pins naming a release train and a channel, e.g. 1.23@rc or go1.23@rc
+var channelPattern = regexp.MustCompile(`^(?:go|v)?(\d+\.\d+)@(\w+)$`)
+
+// parseChannel returns the release train of a channel pin like 1.23@rc.
+// ok is false if arg is no channel pin.
+func parseChannel(arg string) (train string, ok bool, err error) {
+	m := channelPattern.FindStringSubmatch(strings.ToLower(arg))
+	if m == nil {
+		return "", false, nil
+	}
+	if m[2] != ChannelRC {
+		return "", true, fmt.Errorf("%w; channel=%s; supported channels are %s", errUnknownChannel, m[2], ChannelRC)
+	}
+	return m[1], true, nil
+}
+
+// inChannel reports whether v is a release candidate or a final release of the release train
+func inChannel(v Version, train string) bool {
+	return v.Minor() == train && (v.IsStable() || v.IsReleaseCandidate())
+}
+
+// latestInChannel returns the newest release of the rc channel of train, skipping quarantined versions.
+// Final releases sort above their release candidates, so the final release wins once it is published.
+// versions must be sorted in descending order.
+func latestInChannel(versions []Version, train string, cfg *Config) (Version, bool) {
+	for _, v := range versions {
+		if inChannel(v, train) && (cfg == nil || !cfg.quarantined(v)) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// resolveChannel resolves the channel pin arg of the release train to the newest release of the channel available in scope
+func (e *executor) resolveChannel(ctx context.Context, arg, train string, scope resolveScope) (Version, error) {
+	candidates, err := e.scopedVersions(ctx, scope, true)
+	if err != nil {
+		return "", err
+	}
+	if v, ok := latestInChannel(candidates, train, nil); ok {
+		log.Debug().Msgf("resolved channel %s to %s", arg, v)
+		return v, nil
+	}
+	return "", fmt.Errorf("%w; channel=%s", errNoMatchingRelease, arg)
+}
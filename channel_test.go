@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+func TestParseChannel(t *testing.T) {
+	testutils.Run(t, "parseChannel", func(g *goblin.G) {
+		g.It("parses rc channels", func() {
+			train, ok, err := parseChannel("go1.23@rc")
+			Ω(err).Should(Succeed())
+			Ω(ok).Should(BeTrue())
+			Ω(train).Should(Equal("1.23"))
+		})
+
+		g.It("ignores versions", func() {
+			_, ok, err := parseChannel("1.23.1")
+			Ω(err).Should(Succeed())
+			Ω(ok).Should(BeFalse())
+		})
+
+		g.It("rejects unknown channels", func() {
+			_, ok, err := parseChannel("1.23@nightly")
+			Ω(ok).Should(BeTrue())
+			Ω(errors.Is(err, errUnknownChannel)).Should(BeTrue())
+		})
+	})
+}
+
+func TestRCChannel(t *testing.T) {
+	testutils.Run(t, "RCChannel", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		ConfigFile = filepath.Join(testutils.TempDir(t), "go.yaml")
+		rc := newReleaseServer("1.23.0-rc.2", "1.23.0-rc.1", "1.23.0-beta.1", "1.22.5")
+		final := newReleaseServer("1.23.0", "1.23.0-rc.2", "1.23.0-rc.1", "1.22.5")
+		var out *Buffer
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+		})
+
+		g.After(func() {
+			rc.Close()
+			final.Close()
+		})
+
+		newSut := func(url string) *executor {
+			out = &Buffer{&bytes.Buffer{}}
+			sut := defaultExecutor()
+			sut.URL = url
+			sut.Streams.Out = out
+			return sut
+		}
+
+		g.It("resolves to the newest release candidate before the final release", func() {
+			Ω(newSut(rc.URL).resolveVersion(context.Background(), "1.23@rc", remoteScope, false)).Should(Equal(Version("1.23.0-rc.2")))
+		})
+
+		g.It("resolves to the final release once it is published", func() {
+			Ω(newSut(final.URL).resolveVersion(context.Background(), "1.23@rc", remoteScope, false)).Should(Equal(Version("1.23.0")))
+		})
+
+		g.It("resolves against installed versions", func() {
+			_ = os.MkdirAll(filepath.Join(InstallPath, "1.23.0-rc.1"), os.ModePerm)
+			Ω(newSut(rc.URL).resolveVersion(context.Background(), "1.23@rc", installedScope, false)).Should(Equal(Version("1.23.0-rc.1")))
+		})
+
+		g.It("fails for trains without release candidates", func() {
+			_, err := newSut(rc.URL).resolveVersion(context.Background(), "1.24@rc", remoteScope, false)
+			Ω(errors.Is(err, errNoMatchingRelease)).Should(BeTrue())
+		})
+
+		g.It("matches release candidates and final releases of the train", func() {
+			Ω(pinMatches("1.23@rc", "1.23.0-rc.1")).Should(BeTrue())
+			Ω(pinMatches("1.23@rc", "1.23.1")).Should(BeTrue())
+			Ω(pinMatches("1.23@rc", "1.23.0-beta.1")).Should(BeFalse())
+			Ω(pinMatches("1.23@rc", "1.22.5")).Should(BeFalse())
+		})
+
+		g.It("keeps channel pins in project pins", func() {
+			dir := filepath.Join(testutils.TempDir(t), "project")
+			_ = os.MkdirAll(dir, os.ModePerm)
+			defer os.RemoveAll(dir)
+			Ω(newSut(rc.URL).SetLocal(context.Background(), dir, "1.23@rc")).Should(Succeed())
+			Ω(os.ReadFile(filepath.Join(dir, ProjectVersionFile))).Should(Equal([]byte("1.23@rc\n")))
+		})
+
+		g.Describe("upgrade", func() {
+			g.BeforeEach(func() {
+				_ = os.MkdirAll(filepath.Join(InstallPath, "1.23.0-rc.1"), os.ModePerm)
+				symlink(afero.NewOsFs(), filepath.Join(InstallPath, "1.23.0-rc.1"), filepath.Join(InstallPath, "current"))
+			})
+
+			g.It("follows the channel of a current release candidate", func() {
+				sut := newSut(rc.URL)
+				Ω(sut.Upgrade(context.Background(), "", false)).Should(Succeed())
+				Ω(sut.current()).Should(Equal(Version("1.23.0-rc.2")))
+				Ω(out.String()).Should(ContainSubstring("upgraded go 1.23.0-rc.1 to 1.23.0-rc.2"))
+			})
+
+			g.It("upgrades to the final release", func() {
+				sut := newSut(final.URL)
+				Ω(sut.Upgrade(context.Background(), "1.23@rc", true)).Should(Succeed())
+				Ω(sut.current()).Should(Equal(Version("1.23.0")))
+				Ω(filepath.Join(InstallPath, "1.23.0-rc.1")).ShouldNot(BeADirectory())
+			})
+		})
+	})
+}
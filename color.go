@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// values of --color
+const (
+	colorAuto   = "auto"
+	colorAlways = "always"
+	colorNever  = "never"
+)
+
+// NoColorEnv disables colors in auto mode if set to a non-empty value, see https://no-color.org
+const NoColorEnv = "NO_COLOR"
+
+// ansi escape sequences used to style the output
+const (
+	styleBold  = "\033[1m"
+	styleDim   = "\033[2m"
+	styleGreen = "\033[32m"
+	styleReset = "\033[0m"
+)
+
+// colorMode is set by --color
+var colorMode = colorAuto
+
+func validateColorMode(mode string) error {
+	switch mode {
+	case colorAuto, colorAlways, colorNever:
+		return nil
+	}
+	return fmt.Errorf("unsupported --color %q; supported are %s, %s and %s", mode, colorAuto, colorAlways, colorNever)
+}
+
+// colorEnabled reports whether output written to w is colored.
+// In auto mode colors are used for terminals unless NO_COLOR is set or --porcelain is passed.
+func colorEnabled(w interface{}) bool {
+	switch colorMode {
+	case colorAlways:
+		return true
+	case colorNever:
+		return false
+	}
+	return os.Getenv(NoColorEnv) == "" && !porcelain && isTerminal(w)
+}
+
+// colorize wraps s in the given styles if the executor prints colors
+func (e *executor) colorize(s string, styles ...string) string {
+	if !e.Color || len(styles) == 0 {
+		return s
+	}
+	return strings.Join(styles, "") + s + styleReset
+}
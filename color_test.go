@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestColorEnabled(t *testing.T) {
+	testutils.Run(t, "colorEnabled", func(g *goblin.G) {
+		var mode string
+		var noColor string
+		var hadNoColor bool
+
+		g.BeforeEach(func() {
+			mode = colorMode
+			noColor, hadNoColor = os.LookupEnv(NoColorEnv)
+			_ = os.Unsetenv(NoColorEnv)
+		})
+
+		g.AfterEach(func() {
+			colorMode = mode
+			if hadNoColor {
+				_ = os.Setenv(NoColorEnv, noColor)
+			} else {
+				_ = os.Unsetenv(NoColorEnv)
+			}
+		})
+
+		g.It("does not color buffers in auto mode", func() {
+			colorMode = colorAuto
+			Ω(colorEnabled(&bytes.Buffer{})).Should(BeFalse())
+		})
+
+		g.It("always colors with --color always", func() {
+			colorMode = colorAlways
+			_ = os.Setenv(NoColorEnv, "1")
+			Ω(colorEnabled(&bytes.Buffer{})).Should(BeTrue())
+		})
+
+		g.It("never colors with --color never", func() {
+			colorMode = colorNever
+			Ω(colorEnabled(os.Stdout)).Should(BeFalse())
+		})
+
+		g.It("respects NO_COLOR in auto mode", func() {
+			colorMode = colorAuto
+			_ = os.Setenv(NoColorEnv, "1")
+			Ω(colorEnabled(os.Stdout)).Should(BeFalse())
+		})
+	})
+}
+
+func TestValidateColorMode(t *testing.T) {
+	testutils.Run(t, "validateColorMode", func(g *goblin.G) {
+		g.It("accepts auto, always and never", func() {
+			for _, mode := range []string{colorAuto, colorAlways, colorNever} {
+				Ω(validateColorMode(mode)).Should(Succeed())
+			}
+		})
+
+		g.It("rejects other modes", func() {
+			Ω(validateColorMode("sometimes")).Should(MatchError(ContainSubstring(`unsupported --color "sometimes"`)))
+		})
+	})
+}
+
+func TestColorize(t *testing.T) {
+	testutils.Run(t, "colorize", func(g *goblin.G) {
+		g.It("wraps the text in the styles", func() {
+			Ω((&executor{Color: true}).colorize("1.21.3", styleBold)).Should(Equal("\033[1m1.21.3\033[0m"))
+		})
+
+		g.It("returns the text as is without colors", func() {
+			Ω((&executor{}).colorize("1.21.3", styleBold)).Should(Equal("1.21.3"))
+		})
+	})
+}
@@ -0,0 +1,335 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alex-held/dfctl-kit/pkg/env"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+// completionSetup describes how completions are set up for a shell; paths are relative to the home directory
+type completionSetup struct {
+	// RC is the startup file of the shell
+	RC string
+	// Snippet is the line of RC loading the completions
+	Snippet string
+	// File is the completion file the shell loads on demand, if the shell supports one
+	File string
+	// FileSetup is the line of RC letting the shell find File, if the shell does not look there by default
+	FileSetup string
+	// Hook is the line of RC putting the managed go on PATH
+	Hook string
+}
+
+var completionSetups = map[string]completionSetup{
+	"bash": {
+		RC:      ".bashrc",
+		Snippet: "source <(dfctl-go completion bash)",
+		File:    ".local/share/bash-completion/completions/dfctl-go",
+		Hook:    `eval "$(dfctl-go hook bash)"`,
+	},
+	"zsh": {
+		RC:        ".zshrc",
+		Snippet:   "source <(dfctl-go completion zsh); compdef _dfctl-go dfctl-go",
+		File:      ".zfunc/_dfctl-go",
+		FileSetup: "fpath+=(~/.zfunc); autoload -Uz compinit && compinit",
+		Hook:      `eval "$(dfctl-go hook zsh)"`,
+	},
+	"fish": {
+		RC:      ".config/fish/config.fish",
+		Snippet: "dfctl-go completion fish | source",
+		File:    ".config/fish/completions/dfctl-go.fish",
+		Hook:    "dfctl-go hook fish | source",
+	},
+	"powershell": {
+		RC:      ".config/powershell/Microsoft.PowerShell_profile.ps1",
+		Snippet: "dfctl-go completion powershell | Out-String | Invoke-Expression",
+		Hook:    "dfctl-go env --shell powershell | Out-String | Invoke-Expression",
+	},
+}
+
+// completionShells returns the sorted shells completions are generated for
+func completionShells() (shells []string) {
+	for shell := range completionSetups {
+		shells = append(shells, shell)
+	}
+	sort.Strings(shells)
+	return shells
+}
+
+func newCompletionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "completion",
+		Short: "generates the autocompletion script for the specified shell",
+	}
+	for _, shell := range completionShells() {
+		shell := shell
+		cmd.AddCommand(&cobra.Command{
+			Use:   shell,
+			Short: "generates the autocompletion script for " + shell,
+			Long:  "generates the autocompletion script for " + shell + "; load it by adding the following line to ~/" + completionSetups[shell].RC + ":\n\n  " + completionSetups[shell].Snippet,
+			RunE: func(c *cobra.Command, args []string) error {
+				if err := validateArgsForSubcommand("completion "+shell, args, 0); err != nil {
+					return err
+				}
+				return genCompletion(c.Root(), shell, c.OutOrStdout())
+			},
+		})
+	}
+	var shell string
+	installCmd := &cobra.Command{
+		Use:   "install",
+		Short: "installs the autocompletion script where the shell loads it on demand",
+		Long:  "writes the autocompletion script for the shell, which defaults to $SHELL, into the completion directory of the shell, e.g. ~/.local/share/bash-completion/completions for bash or $ZSH/completions for oh-my-zsh",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateArgsForSubcommand("completion install", args, 0); err != nil {
+				return err
+			}
+			if shell == "" {
+				shell = filepath.Base(env.GetVars().Get("SHELL"))
+			}
+			return defaultExecutor().InstallCompletion(c.Root(), shell)
+		},
+	}
+	installCmd.Flags().StringVar(&shell, "shell", "", "shell to install the completions for: "+strings.Join(completionShells(), ", "))
+	cmd.AddCommand(installCmd)
+	cmd.AddCommand(&cobra.Command{
+		Use:       "doctor [shell]",
+		Short:     "checks whether completions are set up correctly",
+		Long:      "checks whether the completions and the managed go are set up correctly for the shell, which defaults to $SHELL, and prints copy-pasteable fixes",
+		ValidArgs: completionShells(),
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateMaxArgsForSubcommand("completion doctor", args, 1); err != nil {
+				return err
+			}
+			shell := filepath.Base(env.GetVars().Get("SHELL"))
+			if len(args) == 1 {
+				shell = args[0]
+			}
+			return defaultExecutor().CompletionDoctor(c.Context(), c.Root(), shell)
+		},
+	})
+	return cmd
+}
+
+// genCompletion writes the completion script of root for shell to w
+func genCompletion(root *cobra.Command, shell string, w io.Writer) error {
+	switch shell {
+	case "bash":
+		return root.GenBashCompletionV2(w, true)
+	case "zsh":
+		return root.GenZshCompletion(w)
+	case "fish":
+		return root.GenFishCompletion(w, true)
+	case "powershell":
+		return root.GenPowerShellCompletionWithDesc(w)
+	}
+	return fmt.Errorf("%w; shell=%s", errUnsupportedShell, shell)
+}
+
+// completionFile returns the path of the completion file of shell honoring $XDG_DATA_HOME, $XDG_CONFIG_HOME and
+// the completion directory of oh-my-zsh, or an empty path if the shell loads no completion files
+func completionFile(shell string) string {
+	vars := env.GetVars()
+	switch {
+	case completionSetups[shell].File == "":
+		return ""
+	case shell == "bash" && vars.Get("XDG_DATA_HOME") != "":
+		return filepath.Join(vars.Get("XDG_DATA_HOME"), "bash-completion", "completions", "dfctl-go")
+	case shell == "fish" && vars.Get("XDG_CONFIG_HOME") != "":
+		return filepath.Join(vars.Get("XDG_CONFIG_HOME"), "fish", "completions", "dfctl-go.fish")
+	case shell == "zsh" && vars.Get("ZSH") != "":
+		return filepath.Join(vars.Get("ZSH"), "completions", "_dfctl-go")
+	}
+	return filepath.Join(vars.Get("HOME"), completionSetups[shell].File)
+}
+
+// InstallCompletion writes the completion script of root for shell into the completion file of the shell
+// and prints the line of its RC needed to load it, if it is missing
+func (e *executor) InstallCompletion(root *cobra.Command, shell string) error {
+	setup, ok := completionSetups[shell]
+	if !ok {
+		return fmt.Errorf("%w; shell=%s; pass one of %s", errUnsupportedShell, shell, strings.Join(completionShells(), ", "))
+	}
+	file := completionFile(shell)
+	if file == "" {
+		return fmt.Errorf("%s loads no completion files; add '%s' to ~/%s instead", shell, setup.Snippet, setup.RC)
+	}
+	script := &bytes.Buffer{}
+	if err := genCompletion(root, shell, script); err != nil {
+		return err
+	}
+	if err := e.Fs.MkdirAll(filepath.Dir(file), os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create the completion directory %s; err=%v", filepath.Dir(file), err)
+	}
+	if err := afero.WriteFile(e.Fs, file, script.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write the completion file %s; err=%v", file, err)
+	}
+	e.infof("installed the %s completions at %s\n", shell, file)
+
+	rc := filepath.Join(env.GetVars().Get("HOME"), setup.RC)
+	if setup.FileSetup != "" && filepath.Dir(file) == filepath.Join(env.GetVars().Get("HOME"), filepath.Dir(setup.File)) {
+		if data, err := afero.ReadFile(e.Fs, rc); err != nil || !bytes.Contains(data, []byte(filepath.Dir(setup.File))) {
+			e.infof("add the following line to %s to load them:\n\n  %s\n", rc, setup.FileSetup)
+		}
+	}
+	return nil
+}
+
+// CompletionDoctor checks the completion setup of shell and prints fixes for failed checks
+func (e *executor) CompletionDoctor(ctx context.Context, root *cobra.Command, shell string) error {
+	setup, ok := completionSetups[shell]
+	if !ok {
+		return fmt.Errorf("%w; shell=%s; pass one of %s", errUnsupportedShell, shell, strings.Join(completionShells(), ", "))
+	}
+	home := env.GetVars().Get("HOME")
+	rc := filepath.Join(home, setup.RC)
+	file := completionFile(shell)
+
+	checks := []doctorCheck{
+		{
+			Name: "shell",
+			Run: func(context.Context) (string, error) {
+				return shell, nil
+			},
+		},
+		{
+			Name: "dfctl-go on PATH",
+			Run: func(context.Context) (string, error) {
+				path, err := lookPath("dfctl-go", env.GetVars().Get("PATH"))
+				if err != nil {
+					return "", fmt.Errorf("the completions call dfctl-go, which is not on PATH")
+				}
+				return path, nil
+			},
+			Hint: "move dfctl-go into a directory on PATH, e.g. ~/.local/bin",
+		},
+		{
+			Name: "init snippet",
+			Run: func(context.Context) (string, error) {
+				if file != "" {
+					if exists, _ := afero.Exists(e.Fs, file); exists {
+						return "completions are installed at " + file, nil
+					}
+				}
+				data, err := afero.ReadFile(e.Fs, rc)
+				if err != nil || !bytes.Contains(data, []byte("dfctl-go completion")) {
+					return "", fmt.Errorf("%s does not load the completions", rc)
+				}
+				return rc + " loads the completions", nil
+			},
+			Hint: fmt.Sprintf("echo '%s' >> %s", setup.Snippet, rc),
+		},
+		{
+			Name: "completion script",
+			Run: func(context.Context) (string, error) {
+				installed, err := afero.ReadFile(e.Fs, file)
+				if file == "" || err != nil {
+					return "generated when the shell starts", nil
+				}
+				generated := &bytes.Buffer{}
+				if err = genCompletion(root, shell, generated); err != nil {
+					return "", err
+				}
+				if !bytes.Equal(installed, generated.Bytes()) {
+					return "", fmt.Errorf("%s is outdated", file)
+				}
+				return file + " is up to date", nil
+			},
+			Hint: fmt.Sprintf("dfctl-go completion %s > %s", shell, file),
+		},
+		{
+			Name: "PATH",
+			Run:  e.checkPath,
+			Hint: fmt.Sprintf("echo '%s' >> %s", setup.Hook, rc),
+		},
+	}
+	return e.runDoctorChecks(ctx, checks, doctorOptions{})
+}
+
+// completionTimeout bounds fetching the release feed for completions if it is not cached yet
+const completionTimeout = 2 * time.Second
+
+// completeInstalledVersion completes the single version argument of use and uninstall with the installed versions
+func completeInstalledVersion(c *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	versions, err := defaultExecutor().installedVersions()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return completeVersions(versions, nil, args, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeUsableVersion completes the single version argument of use with the installed versions and the system keyword
+func completeUsableVersion(c *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	versions, err := defaultExecutor().installedVersions()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return completeVersions(versions, []string{KeywordSystem}, args, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeRemoteVersions completes the version arguments of install with the stable releases of the cached release feed
+func completeRemoteVersions(c *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	versions, err := defaultExecutor().completionReleases(c.Context())
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return completeVersions(versions, []string{KeywordLatest, KeywordStable}, args, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completionReleases returns the stable versions of the cached release feed in descending order,
+// or fetches the feed if it is not cached yet
+func (e *executor) completionReleases(ctx context.Context) ([]Version, error) {
+	releases, err := e.cachedReleases()
+	if err != nil {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		ctx, cancel := context.WithTimeout(ctx, completionTimeout)
+		defer cancel()
+		if releases, err = e.releases(ctx); err != nil {
+			return nil, err
+		}
+	}
+	var versions []Version
+	for _, r := range releases {
+		if v, err := ParseVersion(r.Version); err == nil && v.IsStable() {
+			versions = append(versions, v)
+		}
+	}
+	sortVersions(versions)
+	return versions, nil
+}
+
+// completeVersions returns the keywords and versions starting with toComplete which are not among args yet
+func completeVersions(versions []Version, keywords []string, args []string, toComplete string) (completions []string) {
+	given := map[string]bool{}
+	for _, arg := range args {
+		given[arg] = true
+	}
+	candidates := append([]string{}, keywords...)
+	for _, v := range versions {
+		candidates = append(candidates, v.String())
+	}
+	for _, candidate := range candidates {
+		if !given[candidate] && strings.HasPrefix(candidate, toComplete) {
+			completions = append(completions, candidate)
+		}
+	}
+	return completions
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/env"
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestCompletionDoctor(t *testing.T) {
+	testutils.Run(t, "CompletionDoctor", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		home := testutils.TempDir(t, "home")
+		bin := testutils.TempDir(t, "bin")
+		var out *Buffer
+
+		g.BeforeEach(func() {
+			_ = os.MkdirAll(bin, os.ModePerm)
+			_ = os.MkdirAll(filepath.Join(home, ".config", "fish", "completions"), os.ModePerm)
+			_ = os.WriteFile(filepath.Join(bin, "dfctl-go"), []byte{}, 0755)
+			_ = os.WriteFile(filepath.Join(home, ".zshrc"), []byte("source <(dfctl-go completion zsh)\n"), 0644)
+			env.Overrides.Vars = env.Vars{"HOME": home, "PATH": bin + ":" + filepath.Join(InstallPath, "current", "bin")}
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(home)
+			_ = os.RemoveAll(bin)
+			env.ClearOverrides()
+		})
+
+		newSut := func() *executor {
+			out = &Buffer{&bytes.Buffer{}}
+			sut := defaultExecutor()
+			sut.Streams.Out = out
+			return sut
+		}
+
+		g.It("passes for a complete setup", func() {
+			Ω(newSut().CompletionDoctor(context.Background(), NewCmd(), "zsh")).Should(Succeed())
+			Ω(out.String()).ShouldNot(ContainSubstring("[fail]"))
+		})
+
+		g.It("prints the init snippet for missing completions", func() {
+			err := newSut().CompletionDoctor(context.Background(), NewCmd(), "bash")
+			Ω(errors.Is(err, errUnhealthy)).Should(BeTrue())
+			Ω(out.String()).Should(ContainSubstring("echo 'source <(dfctl-go completion bash)' >> " + filepath.Join(home, ".bashrc")))
+		})
+
+		g.It("detects outdated completion files", func() {
+			_ = os.WriteFile(filepath.Join(home, ".config", "fish", "completions", "dfctl-go.fish"), []byte("complete -c dfctl-go\n"), 0644)
+			Ω(newSut().CompletionDoctor(context.Background(), NewCmd(), "fish")).ShouldNot(Succeed())
+			Ω(out.String()).Should(ContainSubstring("[fail] completion script"))
+			Ω(out.String()).Should(ContainSubstring("dfctl-go completion fish > "))
+		})
+
+		g.It("detects dfctl-go missing on PATH", func() {
+			env.Overrides.Vars = env.Vars{"HOME": home, "PATH": filepath.Join(InstallPath, "current", "bin")}
+			Ω(newSut().CompletionDoctor(context.Background(), NewCmd(), "zsh")).ShouldNot(Succeed())
+			Ω(out.String()).Should(ContainSubstring("[fail] dfctl-go on PATH"))
+		})
+
+		g.It("rejects unsupported shells", func() {
+			Ω(errors.Is(newSut().CompletionDoctor(context.Background(), NewCmd(), "tcsh"), errUnsupportedShell)).Should(BeTrue())
+		})
+	})
+}
+
+func TestCompleteVersions(t *testing.T) {
+	testutils.Run(t, "completeVersions", func(g *goblin.G) {
+		versions := []Version{"1.22.1", "1.21.9", "1.21.3"}
+
+		g.It("completes the keywords and versions starting with the prefix", func() {
+			Ω(completeVersions(versions, []string{KeywordLatest}, nil, "")).Should(Equal([]string{"latest", "1.22.1", "1.21.9", "1.21.3"}))
+			Ω(completeVersions(versions, []string{KeywordLatest}, nil, "1.21")).Should(Equal([]string{"1.21.9", "1.21.3"}))
+		})
+
+		g.It("skips versions passed before", func() {
+			Ω(completeVersions(versions, nil, []string{"1.21.9"}, "1.21")).Should(Equal([]string{"1.21.3"}))
+		})
+	})
+}
+
+func TestCompletionReleases(t *testing.T) {
+	testutils.Run(t, "completionReleases", func(g *goblin.G) {
+		server := newReleaseServer("1.22.1", "1.22.0-rc.1", "1.21.9")
+		var sut *executor
+
+		g.BeforeEach(func() {
+			sut = defaultExecutor()
+			sut.URL = server.URL
+			sut.CachePath = testutils.TempDir(t, "cache")
+		})
+
+		g.After(func() {
+			server.Close()
+		})
+
+		g.It("fetches and caches the release feed if it is not cached yet", func() {
+			Ω(sut.completionReleases(context.Background())).Should(Equal([]Version{"1.22.1", "1.21.9"}))
+			Ω(filepath.Join(sut.CachePath, releaseFeedFile)).Should(BeARegularFile())
+		})
+
+		g.It("completes from the cached release feed without network", func() {
+			_, _ = sut.releases(context.Background())
+			sut.URL = "http://127.0.0.1:0"
+			Ω(sut.completionReleases(context.Background())).Should(Equal([]Version{"1.22.1", "1.21.9"}))
+		})
+	})
+}
+
+func TestInstallCompletion(t *testing.T) {
+	testutils.Run(t, "InstallCompletion", func(g *goblin.G) {
+		home := testutils.TempDir(t, "home")
+		var out *Buffer
+		var sut *executor
+
+		g.BeforeEach(func() {
+			env.Overrides.Vars = env.Vars{"HOME": home}
+			out = &Buffer{&bytes.Buffer{}}
+			sut = defaultExecutor()
+			sut.Streams.Out = out
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(home)
+			env.ClearOverrides()
+		})
+
+		g.It("writes the bash completions into the bash-completion directory", func() {
+			Ω(sut.InstallCompletion(NewCmd(), "bash")).Should(Succeed())
+			Ω(filepath.Join(home, ".local", "share", "bash-completion", "completions", "dfctl-go")).Should(BeARegularFile())
+		})
+
+		g.It("honors XDG_CONFIG_HOME for fish", func() {
+			env.Overrides.Vars = env.Vars{"HOME": home, "XDG_CONFIG_HOME": filepath.Join(home, "config")}
+			Ω(sut.InstallCompletion(NewCmd(), "fish")).Should(Succeed())
+			Ω(filepath.Join(home, "config", "fish", "completions", "dfctl-go.fish")).Should(BeARegularFile())
+		})
+
+		g.It("prints the fpath setup for zsh", func() {
+			Ω(sut.InstallCompletion(NewCmd(), "zsh")).Should(Succeed())
+			Ω(filepath.Join(home, ".zfunc", "_dfctl-go")).Should(BeARegularFile())
+			Ω(out.String()).Should(ContainSubstring("fpath+=(~/.zfunc)"))
+		})
+
+		g.It("uses the completion directory of oh-my-zsh", func() {
+			env.Overrides.Vars = env.Vars{"HOME": home, "ZSH": filepath.Join(home, ".oh-my-zsh")}
+			Ω(sut.InstallCompletion(NewCmd(), "zsh")).Should(Succeed())
+			Ω(filepath.Join(home, ".oh-my-zsh", "completions", "_dfctl-go")).Should(BeARegularFile())
+			Ω(out.String()).ShouldNot(ContainSubstring("fpath"))
+		})
+
+		g.It("rejects shells without completion files", func() {
+			Ω(sut.InstallCompletion(NewCmd(), "powershell")).Should(MatchError(ContainSubstring("powershell loads no completion files")))
+			Ω(errors.Is(sut.InstallCompletion(NewCmd(), "tcsh"), errUnsupportedShell)).Should(BeTrue())
+		})
+	})
+}
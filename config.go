@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/alex-held/dfctl-kit/pkg/env"
+	"gopkg.in/yaml.v2"
+)
+
+var ConfigFile = filepath.Join(env.Configs(), "go.yaml")
+
+// Config is the user configuration of dfctl-go
+type Config struct {
+	// Pins hold back release trains (e.g. 1.20) from being upgraded
+	Pins []string `yaml:"pins,omitempty"`
+	// Quarantine lists versions which must never be selected when resolving upgrades
+	Quarantine []string `yaml:"quarantine,omitempty"`
+	// Notifications configures desktop notifications about finished installs
+	Notifications NotificationConfig `yaml:"notifications,omitempty"`
+	// Integrations configures platform integrations, e.g. after switching the current version
+	Integrations IntegrationsConfig `yaml:"integrations,omitempty"`
+	// Extraction configures how archives are extracted, e.g. inside a sandbox
+	Extraction ExtractionConfig `yaml:"extraction,omitempty"`
+	// Aliases name versions, e.g. lts: 1.21.9, which are accepted everywhere a version is
+	Aliases map[string]string `yaml:"aliases,omitempty"`
+	// AutoInstall lets use install missing versions before linking them, like use --install
+	AutoInstall bool `yaml:"auto_install,omitempty"`
+	// Log configures the log file
+	Log LogConfig `yaml:"log,omitempty"`
+	// Bootstrap pins the toolchain building go from source, either a version like 1.22.8 or the GOROOT of a go sdk
+	Bootstrap string `yaml:"bootstrap,omitempty"`
+}
+
+// config returns the effective config merged from the system, user and project configs
+func (e *executor) config() (*Config, error) {
+	merged, _, err := e.mergedConfig()
+	if err != nil {
+		return nil, err
+	}
+	data, err := yaml.Marshal(merged)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &Config{}
+	if err = yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse the merged config; err=%v", err)
+	}
+	return cfg, nil
+}
+
+// pinned reports whether the release train of v is held back by a pin
+func (c *Config) pinned(v Version) bool {
+	for _, pin := range c.Pins {
+		if Version(pin).Minor() == v.Minor() {
+			return true
+		}
+	}
+	return false
+}
+
+// quarantined reports whether v must not be selected
+func (c *Config) quarantined(v Version) bool {
+	for _, q := range c.Quarantine {
+		if Version(q).Compare(v) == 0 {
+			return true
+		}
+	}
+	return false
+}
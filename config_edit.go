@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/alex-held/dfctl-kit/pkg/env"
+	"github.com/alex-held/dfctl-kit/pkg/system"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+var errConfigInvalid = errors.New("config is invalid")
+
+func newConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "manages the configuration of dfctl-go",
+	}
+	cmd.AddCommand(newConfigShowCmd())
+	cmd.AddCommand(&cobra.Command{
+		Use:   "edit",
+		Short: "opens the config file in $EDITOR and validates it on save",
+		Long:  "opens a copy of the config file " + ConfigFile + " in $VISUAL or $EDITOR; the config file is only replaced once the edited copy is valid",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateArgsForSubcommand("config edit", args, 0); err != nil {
+				return err
+			}
+			return defaultExecutor().EditConfig()
+		},
+	})
+	return cmd
+}
+
+// validateConfig returns every problem of the config, unknown keys are reported with their line
+func validateConfig(data []byte) (problems []error) {
+	cfg := &Config{}
+	if err := yaml.UnmarshalStrict(data, cfg); err != nil {
+		var typeErr *yaml.TypeError
+		if errors.As(err, &typeErr) {
+			for _, msg := range typeErr.Errors {
+				problems = append(problems, errors.New(msg))
+			}
+			return problems
+		}
+		return []error{err}
+	}
+	for _, pin := range cfg.Pins {
+		if _, err := ParseVersion(pin); err != nil {
+			problems = append(problems, fmt.Errorf("pins: %s is no version", pin))
+		}
+	}
+	for _, q := range cfg.Quarantine {
+		if _, err := ParseVersion(q); err != nil {
+			problems = append(problems, fmt.Errorf("quarantine: %s is no version", q))
+		}
+	}
+	for name, target := range cfg.Aliases {
+		if err := validateAlias(name, target); err != nil {
+			problems = append(problems, fmt.Errorf("aliases: %v", err))
+		}
+	}
+	if cfg.Notifications.After < 0 {
+		problems = append(problems, fmt.Errorf("notifications.after: must not be negative"))
+	}
+	return problems
+}
+
+// editor returns the command line of the editor of the user
+func editor() []string {
+	vars := env.GetVars()
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if cmd := strings.Fields(vars.Get(name)); len(cmd) > 0 {
+			return cmd
+		}
+	}
+	if system.Get().OS == "windows" {
+		return []string{"notepad"}
+	}
+	return []string{"vi"}
+}
+
+// EditConfig lets the user edit a copy of the config file until it is valid or the user gives up.
+// The config file is only replaced with a valid config.
+func (e *executor) EditConfig() error {
+	data, err := afero.ReadFile(e.Fs, e.ConfigFile)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read config file %s; err=%v", e.ConfigFile, err)
+	}
+
+	tmp, err := afero.TempFile(e.Fs, "", "dfctl-go-*.yaml")
+	if err != nil {
+		return err
+	}
+	_ = tmp.Close()
+	defer func() { _ = e.Fs.Remove(tmp.Name()) }()
+	if err = afero.WriteFile(e.Fs, tmp.Name(), data, 0600); err != nil {
+		return err
+	}
+
+	in := bufio.NewReader(e.Streams.In)
+	for {
+		argv := append(editor(), tmp.Name())
+		cmd := exec.Command(argv[0], argv[1:]...)
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = e.Streams.In, e.Streams.Out, e.Streams.Err
+		if err = cmd.Run(); err != nil {
+			return fmt.Errorf("failed to run editor %s; err=%v", argv[0], err)
+		}
+
+		edited, err := afero.ReadFile(e.Fs, tmp.Name())
+		if err != nil {
+			return err
+		}
+		problems := validateConfig(edited)
+		if len(problems) == 0 {
+			if err = e.Fs.MkdirAll(filepath.Dir(e.ConfigFile), os.ModePerm); err != nil {
+				return err
+			}
+			if err = afero.WriteFile(e.Fs, e.ConfigFile, edited, 0644); err != nil {
+				return fmt.Errorf("failed to write config file %s; err=%v", e.ConfigFile, err)
+			}
+			_, _ = fmt.Fprintf(e.Streams.Out, "saved %s\n", e.ConfigFile)
+			return nil
+		}
+
+		_, _ = fmt.Fprintf(e.Streams.Err, "%s is invalid:\n", e.ConfigFile)
+		for _, p := range problems {
+			_, _ = fmt.Fprintf(e.Streams.Err, "  %v\n", p)
+		}
+		_, _ = fmt.Fprint(e.Streams.Err, "edit again? [Y/n] ")
+		answer, _ := in.ReadString('\n')
+		if answer == "" || strings.HasPrefix(strings.ToLower(strings.TrimSpace(answer)), "n") {
+			return fmt.Errorf("%w; %d problems; %s is unchanged", errConfigInvalid, len(problems), e.ConfigFile)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/env"
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestValidateConfig(t *testing.T) {
+	testutils.Run(t, "validateConfig", func(g *goblin.G) {
+
+		g.It("accepts valid configs", func() {
+			Ω(validateConfig([]byte("pins: [\"1.20\"]\nnotifications:\n  enabled: true\n  after: 1m\n"))).Should(BeEmpty())
+		})
+
+		g.It("reports unknown keys with their line", func() {
+			problems := validateConfig([]byte("pins: [\"1.20\"]\npinz: []\n"))
+			Ω(problems).Should(HaveLen(1))
+			Ω(problems[0].Error()).Should(ContainSubstring("line 2: field pinz not found"))
+		})
+
+		g.It("reports invalid versions", func() {
+			problems := validateConfig([]byte("quarantine: [\"one.two\"]\n"))
+			Ω(problems).Should(HaveLen(1))
+			Ω(problems[0].Error()).Should(Equal("quarantine: one.two is no version"))
+		})
+	})
+}
+
+func TestEditConfig(t *testing.T) {
+	testutils.Run(t, "EditConfig", func(g *goblin.G) {
+		dir := testutils.TempDir(t, "edit")
+		editorScript := filepath.Join(dir, "editor.sh")
+
+		g.BeforeEach(func() {
+			ConfigFile = filepath.Join(dir, "configs", "go.yaml")
+			_ = os.MkdirAll(dir, os.ModePerm)
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(dir)
+			env.ClearOverrides()
+		})
+
+		// useEditor writes content into the edited file
+		useEditor := func(content string) {
+			_ = os.WriteFile(editorScript, []byte("#!/bin/sh\nprintf '"+content+"' > \"$1\"\n"), 0755)
+			env.Overrides.Vars = env.Vars{"EDITOR": editorScript}
+		}
+
+		newSut := func(in string) *executor {
+			sut := defaultExecutor()
+			sut.Streams.In = io.NopCloser(strings.NewReader(in))
+			sut.Streams.Out = &Buffer{&bytes.Buffer{}}
+			sut.Streams.Err = &Buffer{&bytes.Buffer{}}
+			return sut
+		}
+
+		g.It("saves valid configs", func() {
+			useEditor(`pins: ["1.20"]\n`)
+			Ω(newSut("").EditConfig()).Should(Succeed())
+			data, err := os.ReadFile(ConfigFile)
+			Ω(err).Should(Succeed())
+			Ω(string(data)).Should(Equal("pins: [\"1.20\"]\n"))
+		})
+
+		g.It("keeps the config file unchanged if the user gives up", func() {
+			useEditor(`pinz: []\n`)
+			err := newSut("n\n").EditConfig()
+			Ω(errors.Is(err, errConfigInvalid)).Should(BeTrue())
+			Ω(ConfigFile).ShouldNot(BeAnExistingFile())
+		})
+	})
+}
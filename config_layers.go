@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// SystemConfigFile is the machine-wide config, e.g. provisioned by IT departments with mirrors and policies,
+// which the user and project configs override
+var SystemConfigFile = systemConfigFile(runtime.GOOS)
+
+// ProjectConfigFile configures dfctl-go for a project directory and its children, overriding the user config
+const ProjectConfigFile = ".dfctl-go.yaml"
+
+// scopes of the config layers from lowest to highest precedence
+const (
+	scopeSystem  = "system"
+	scopeUser    = "user"
+	scopeProject = "project"
+)
+
+// systemConfigFile returns the path of the machine-wide config on the given platform
+func systemConfigFile(goos string) string {
+	switch goos {
+	case "windows":
+		programData := os.Getenv("ProgramData")
+		if programData == "" {
+			programData = `C:\ProgramData`
+		}
+		return filepath.Join(programData, "dfctl", "go.yaml")
+	case "darwin":
+		return "/Library/Application Support/dfctl/go.yaml"
+	}
+	return "/etc/dfctl/go.yaml"
+}
+
+// configLayer is a config file merged into the effective config
+type configLayer struct {
+	Scope string
+	File  string
+}
+
+func (l configLayer) String() string {
+	return fmt.Sprintf("%s (%s)", l.Scope, l.File)
+}
+
+// configValue is a leaf value of the effective config and the layer it came from
+type configValue struct {
+	Value interface{}
+	Layer configLayer
+}
+
+// configLayers returns the config files from the lowest to the highest precedence:
+// the system config, the user config and the nearest ProjectConfigFile of the working directory or its parents
+func (e *executor) configLayers() []configLayer {
+	layers := []configLayer{{Scope: scopeSystem, File: e.SystemConfigFile}, {Scope: scopeUser, File: e.ConfigFile}}
+	if wd, err := os.Getwd(); err == nil {
+		if _, file, err := findUpwards(e.Fs, wd, ProjectConfigFile); err == nil {
+			layers = append(layers, configLayer{Scope: scopeProject, File: file})
+		}
+	}
+	return layers
+}
+
+// mergedConfig merges the config layers and returns every leaf key like notifications.enabled with the layer it came from.
+// Maps are merged key by key, any other value of a layer replaces the value of the layers below.
+func (e *executor) mergedConfig() (merged map[interface{}]interface{}, origins map[string]configValue, err error) {
+	merged, origins = map[interface{}]interface{}{}, map[string]configValue{}
+	for _, layer := range e.configLayers() {
+		if layer.File == "" {
+			continue
+		}
+		data, err := afero.ReadFile(e.Fs, layer.File)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read config file %s; err=%v", layer.File, err)
+		}
+		values := map[interface{}]interface{}{}
+		if err = yaml.Unmarshal(data, &values); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse config file %s; err=%v", layer.File, err)
+		}
+		mergeConfigValues(merged, values, "", layer, origins)
+	}
+	return merged, origins, nil
+}
+
+// mergeConfigValues merges src of layer into dst and records the origin of every merged leaf key below prefix
+func mergeConfigValues(dst, src map[interface{}]interface{}, prefix string, layer configLayer, origins map[string]configValue) {
+	for k, v := range src {
+		key := fmt.Sprint(k)
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if values, ok := v.(map[interface{}]interface{}); ok {
+			existing, ok := dst[k].(map[interface{}]interface{})
+			if !ok {
+				existing = map[interface{}]interface{}{}
+				dst[k] = existing
+				delete(origins, key)
+			}
+			mergeConfigValues(existing, values, key, layer, origins)
+			continue
+		}
+		dst[k] = v
+		for origin := range origins {
+			if strings.HasPrefix(origin, key+".") {
+				delete(origins, origin)
+			}
+		}
+		origins[key] = configValue{Value: v, Layer: layer}
+	}
+}
+
+func newConfigShowCmd() *cobra.Command {
+	var origin bool
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "prints the effective configuration",
+		Long: "prints the configuration merged from the system config " + SystemConfigFile + ", the user config " + ConfigFile +
+			" and the nearest " + ProjectConfigFile + " of the working directory, each overriding the ones before",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateArgsForSubcommand("config show", args, 0); err != nil {
+				return err
+			}
+			return defaultExecutor().ShowConfig(origin)
+		},
+	}
+	cmd.Flags().BoolVar(&origin, "origin", false, "print every value with the config file it came from")
+	return cmd
+}
+
+// ShowConfig prints the effective config as yaml, or every value with its origin
+func (e *executor) ShowConfig(origin bool) error {
+	merged, origins, err := e.mergedConfig()
+	if err != nil {
+		return err
+	}
+	if !origin {
+		data, err := yaml.Marshal(merged)
+		if err != nil {
+			return err
+		}
+		_, _ = e.Streams.Out.Write(data)
+		return nil
+	}
+
+	keys := make([]string, 0, len(origins))
+	for key := range origins {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	w := tabwriter.NewWriter(e.Streams.Out, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "KEY\tVALUE\tORIGIN")
+	for _, key := range keys {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", key, formatConfigValue(origins[key].Value), origins[key].Layer)
+	}
+	return w.Flush()
+}
+
+// formatConfigValue formats lists like [1.20, 1.21] and other values as is
+func formatConfigValue(v interface{}) string {
+	list, ok := v.([]interface{})
+	if !ok {
+		return fmt.Sprint(v)
+	}
+	items := make([]string, 0, len(list))
+	for _, item := range list {
+		items = append(items, fmt.Sprint(item))
+	}
+	return "[" + strings.Join(items, ", ") + "]"
+}
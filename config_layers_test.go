@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+)
+
+func TestConfigLayers(t *testing.T) {
+	testutils.Run(t, "config layers", func(g *goblin.G) {
+		var dir, wd string
+		var out *Buffer
+		var sut *executor
+		fs := afero.NewOsFs()
+
+		g.BeforeEach(func() {
+			dir = filepath.Join(testutils.TempDir(t), "layers")
+			_ = fs.MkdirAll(filepath.Join(dir, "project", "sub"), 0755)
+			_ = afero.WriteFile(fs, filepath.Join(dir, "system.yaml"), []byte("pins: [\"1.20\"]\nnotifications:\n  enabled: true\n  after: 30s\naliases:\n  lts: 1.21.9\n"), 0644)
+			_ = afero.WriteFile(fs, filepath.Join(dir, "user.yaml"), []byte("notifications:\n  after: 1m\naliases:\n  edge: 1.23.0\n"), 0644)
+			wd, _ = os.Getwd()
+			_ = os.Chdir(filepath.Join(dir, "project", "sub"))
+			out = &Buffer{&bytes.Buffer{}}
+			sut = defaultExecutor()
+			sut.Streams.Out = out
+			sut.SystemConfigFile = filepath.Join(dir, "system.yaml")
+			sut.ConfigFile = filepath.Join(dir, "user.yaml")
+		})
+
+		g.AfterEach(func() {
+			_ = os.Chdir(wd)
+			_ = os.RemoveAll(filepath.Dir(dir))
+		})
+
+		g.It("merges the user config over the system config", func() {
+			cfg, err := sut.config()
+			Ω(err).Should(Succeed())
+			Ω(cfg.Pins).Should(Equal([]string{"1.20"}))
+			Ω(cfg.Notifications.Enabled).Should(BeTrue())
+			Ω(cfg.Notifications.After.String()).Should(Equal("1m0s"))
+			Ω(cfg.Aliases).Should(Equal(map[string]string{"lts": "1.21.9", "edge": "1.23.0"}))
+		})
+
+		g.It("merges the nearest project config over the user config", func() {
+			_ = afero.WriteFile(fs, filepath.Join(dir, "project", ProjectConfigFile), []byte("pins: [\"1.22\"]\n"), 0644)
+			cfg, err := sut.config()
+			Ω(err).Should(Succeed())
+			Ω(cfg.Pins).Should(Equal([]string{"1.22"}))
+			Ω(cfg.Notifications.Enabled).Should(BeTrue())
+		})
+
+		g.It("works without any config file", func() {
+			sut.SystemConfigFile = filepath.Join(dir, "missing.yaml")
+			sut.ConfigFile = filepath.Join(dir, "missing.yaml")
+			cfg, err := sut.config()
+			Ω(err).Should(Succeed())
+			Ω(cfg.Pins).Should(BeEmpty())
+		})
+
+		g.It("fails for invalid config files", func() {
+			_ = afero.WriteFile(fs, sut.ConfigFile, []byte("pins: [\n"), 0644)
+			_, err := sut.config()
+			Ω(err).Should(MatchError(ContainSubstring("failed to parse config file " + sut.ConfigFile)))
+		})
+
+		g.It("shows the origin of every value", func() {
+			project := filepath.Join(dir, "project", ProjectConfigFile)
+			_ = afero.WriteFile(fs, project, []byte("pins: [\"1.22\", \"1.23\"]\n"), 0644)
+			Ω(sut.ShowConfig(true)).Should(Succeed())
+			Ω(out.String()).Should(ContainSubstring("KEY"))
+			Ω(out.String()).Should(MatchRegexp(`aliases\.edge\s+1\.23\.0\s+user \(` + sut.ConfigFile + `\)`))
+			Ω(out.String()).Should(MatchRegexp(`aliases\.lts\s+1\.21\.9\s+system \(` + sut.SystemConfigFile + `\)`))
+			Ω(out.String()).Should(MatchRegexp(`notifications\.after\s+1m\s+user`))
+			Ω(out.String()).Should(MatchRegexp(`notifications\.enabled\s+true\s+system`))
+			Ω(out.String()).Should(MatchRegexp(`pins\s+\[1\.22, 1\.23\]\s+project \(` + project + `\)`))
+		})
+
+		g.It("shows the effective config as yaml", func() {
+			Ω(sut.ShowConfig(false)).Should(Succeed())
+			Ω(out.String()).Should(ContainSubstring("after: 1m\n"))
+			Ω(out.String()).Should(ContainSubstring("lts: 1.21.9\n"))
+		})
+
+		g.It("locates the system config of the platform", func() {
+			Ω(systemConfigFile("linux")).Should(Equal("/etc/dfctl/go.yaml"))
+			Ω(systemConfigFile("darwin")).Should(Equal("/Library/Application Support/dfctl/go.yaml"))
+		})
+	})
+}
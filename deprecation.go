@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// Deprecation is a behavior scheduled for removal
+type Deprecation struct {
+	// ID identifies the deprecation in warnings and the deprecations command
+	ID string
+	// Since is the release which deprecated the behavior
+	Since string
+	// RemovedIn is the release targeted for removing the behavior
+	RemovedIn string
+	// Message describes the change and how to migrate
+	Message string
+}
+
+const deprecationUseGlobalScope = "use-global-scope"
+
+// Deprecations lists all active deprecations
+var Deprecations = []Deprecation{
+	{
+		ID:        deprecationUseGlobalScope,
+		Since:     "v0.2.0",
+		RemovedIn: "v1.0.0",
+		Message:   "use inside a project with a pin will pin the project instead of changing the global version; run 'dfctl-go global' to change the machine wide default",
+	},
+}
+
+// noDeprecationWarnings silences deprecation warnings, set by the --no-deprecation-warnings flag
+var noDeprecationWarnings bool
+
+func newDeprecationsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "deprecations",
+		Short: "lists deprecated behavior and when it is removed",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateArgsForSubcommand("deprecations", args, 0); err != nil {
+				return err
+			}
+			return defaultExecutor().ListDeprecations()
+		},
+	}
+}
+
+// ListDeprecations prints all active deprecations
+func (e *executor) ListDeprecations() error {
+	w := tabwriter.NewWriter(e.Streams.Out, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tSINCE\tREMOVAL\tDESCRIPTION")
+	for _, d := range Deprecations {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", d.ID, d.Since, d.RemovedIn, d.Message)
+	}
+	return w.Flush()
+}
+
+// deprecated warns about the use of the deprecation with id unless warnings are disabled
+func (e *executor) deprecated(id string) {
+	if e.NoDeprecationWarnings {
+		return
+	}
+	for _, d := range Deprecations {
+		if d.ID == id {
+			_, _ = fmt.Fprintf(e.Streams.Err, "warning: %s (deprecated in %s, removal in %s; silence with --no-deprecation-warnings)\n", d.Message, d.Since, d.RemovedIn)
+			return
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestDeprecations(t *testing.T) {
+	testutils.Run(t, "Deprecations", func(g *goblin.G) {
+		var out, errOut *Buffer
+
+		newSut := func() *executor {
+			out, errOut = &Buffer{&bytes.Buffer{}}, &Buffer{&bytes.Buffer{}}
+			sut := defaultExecutor()
+			sut.Streams.Out = out
+			sut.Streams.Err = errOut
+			return sut
+		}
+
+		g.It("warns with the removal version", func() {
+			newSut().deprecated(deprecationUseGlobalScope)
+			Ω(errOut.String()).Should(ContainSubstring("removal in v1.0.0"))
+		})
+
+		g.It("does not warn with NoDeprecationWarnings", func() {
+			sut := newSut()
+			sut.NoDeprecationWarnings = true
+			sut.deprecated(deprecationUseGlobalScope)
+			Ω(errOut.String()).Should(BeEmpty())
+		})
+
+		g.It("lists all deprecations", func() {
+			Ω(newSut().ListDeprecations()).Should(Succeed())
+			Ω(out.String()).Should(ContainSubstring("ID"))
+			Ω(out.String()).Should(ContainSubstring(deprecationUseGlobalScope))
+		})
+	})
+}
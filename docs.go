@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+)
+
+// formats of docs gen
+const (
+	docsMarkdown = "markdown"
+	docsMan      = "man"
+)
+
+func newDocsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:    "docs",
+		Short:  "generates the reference documentation",
+		Hidden: true,
+	}
+	var dir string
+	var formats []string
+	genCmd := &cobra.Command{
+		Use:   "gen",
+		Short: "generates man pages and a markdown reference of every command",
+		Long:  "generates a man page into <dir>/man1 and a markdown page into <dir>/markdown for every command, so packagers can ship manuals",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateArgsForSubcommand("docs gen", args, 0); err != nil {
+				return err
+			}
+			return defaultExecutor().GenDocs(c.Root(), dir, formats)
+		},
+	}
+	genCmd.Flags().StringVar(&dir, "dir", "docs", "directory the documentation is written to")
+	genCmd.Flags().StringSliceVar(&formats, "format", []string{docsMarkdown, docsMan}, "formats to generate: markdown and man")
+	cmd.AddCommand(genCmd)
+	return cmd
+}
+
+// GenDocs writes the documentation of root and its available subcommands in the formats into dir.
+// The pages are rendered by cobra/doc, but written through e.Fs instead of the doc tree functions, which write to the os file system.
+func (e *executor) GenDocs(root *cobra.Command, dir string, formats []string) error {
+	for _, format := range formats {
+		var sub string
+		var name func(c *cobra.Command) string
+		var gen func(c *cobra.Command, w io.Writer) error
+		switch format {
+		case docsMarkdown:
+			sub, name, gen = docsMarkdown, markdownDocName, doc.GenMarkdown
+		case docsMan:
+			sub, name, gen = "man1", manDocName, genManDoc
+		default:
+			return fmt.Errorf("unsupported docs format %q; supported are %s and %s", format, docsMarkdown, docsMan)
+		}
+		out := filepath.Join(dir, sub)
+		if err := e.Fs.MkdirAll(out, 0755); err != nil {
+			return fmt.Errorf("failed to create docs directory %s; err=%v", out, err)
+		}
+		for _, c := range documentedCommands(root) {
+			// the generated pages are reproducible without the date of the auto gen tag
+			c.DisableAutoGenTag = true
+			buf := &bytes.Buffer{}
+			if err := gen(c, buf); err != nil {
+				return fmt.Errorf("failed to render the %s docs of %s; err=%v", format, c.CommandPath(), err)
+			}
+			file := filepath.Join(out, name(c))
+			if err := afero.WriteFile(e.Fs, file, buf.Bytes(), 0644); err != nil {
+				return fmt.Errorf("failed to write %s; err=%v", file, err)
+			}
+		}
+		e.infof("generated the %s docs in %s\n", format, out)
+	}
+	return nil
+}
+
+// documentedCommands returns c and its available subcommands depth first, skipping hidden commands and help topics like cobra/doc
+func documentedCommands(c *cobra.Command) []*cobra.Command {
+	commands := []*cobra.Command{c}
+	for _, sub := range c.Commands() {
+		if sub.IsAvailableCommand() && !sub.IsAdditionalHelpTopicCommand() {
+			commands = append(commands, documentedCommands(sub)...)
+		}
+	}
+	return commands
+}
+
+// markdownDocName names the markdown page of c like dfctl-go_cache_clean.md, which cobra/doc links to
+func markdownDocName(c *cobra.Command) string {
+	return strings.ReplaceAll(c.CommandPath(), " ", "_") + ".md"
+}
+
+// manDocName names the man page of c like dfctl-go-cache-clean.1, which cobra/doc refers to
+func manDocName(c *cobra.Command) string {
+	return strings.ReplaceAll(c.CommandPath(), " ", "-") + ".1"
+}
+
+// genManDoc renders the man page of c in section 1
+func genManDoc(c *cobra.Command, w io.Writer) error {
+	// the header is filled with the title of c by doc.GenMan, so every page needs its own
+	header := &doc.GenManHeader{Section: "1", Source: "dfctl-go " + version, Manual: "dfctl-go Manual"}
+	return doc.GenMan(c, header, w)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+)
+
+func TestGenDocs(t *testing.T) {
+	testutils.Run(t, "GenDocs", func(g *goblin.G) {
+		g.It("writes a markdown page and a man page for every available command", func() {
+			fs := afero.NewMemMapFs()
+			sut := &executor{Fs: fs, Streams: defaultExecutor().Streams}
+			Ω(sut.GenDocs(NewCmd(), "/docs", []string{docsMarkdown, docsMan})).Should(Succeed())
+
+			md, err := afero.ReadFile(fs, "/docs/markdown/dfctl-go_install.md")
+			Ω(err).Should(Succeed())
+			Ω(string(md)).Should(ContainSubstring("## dfctl-go install"))
+			Ω(string(md)).Should(ContainSubstring("--force"))
+			Ω(string(md)).Should(ContainSubstring("[dfctl-go](dfctl-go.md)"))
+			Ω(string(md)).ShouldNot(ContainSubstring("Auto generated"))
+
+			man, err := afero.ReadFile(fs, "/docs/man1/dfctl-go-install.1")
+			Ω(err).Should(Succeed())
+			Ω(string(man)).Should(ContainSubstring(`.TH "DFCTL-GO-INSTALL" "1"`))
+			Ω(string(man)).Should(ContainSubstring(`\fB--force\fP`))
+			Ω(string(man)).Should(ContainSubstring(`\fBdfctl-go(1)\fP`))
+			Ω(string(man)).ShouldNot(ContainSubstring("Auto generated"))
+		})
+
+		g.It("skips hidden commands", func() {
+			fs := afero.NewMemMapFs()
+			sut := &executor{Fs: fs, Streams: defaultExecutor().Streams}
+			Ω(sut.GenDocs(NewCmd(), "/docs", []string{docsMarkdown})).Should(Succeed())
+			Ω(afero.Exists(fs, filepath.Join("/docs", "markdown", "dfctl-go_docs.md"))).Should(BeFalse())
+			Ω(afero.Exists(fs, filepath.Join("/docs", "man1"))).Should(BeFalse())
+		})
+
+		g.It("rejects unknown formats", func() {
+			sut := &executor{Fs: afero.NewMemMapFs(), Streams: defaultExecutor().Streams}
+			Ω(sut.GenDocs(NewCmd(), "/docs", []string{"html"})).Should(MatchError(ContainSubstring(`unsupported docs format "html"`)))
+		})
+	})
+}
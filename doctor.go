@@ -0,0 +1,238 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/alex-held/dfctl-kit/pkg/env"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+// doctorNetworkTimeout limits how long the network check waits for the download host
+const doctorNetworkTimeout = 10 * time.Second
+
+var errUnhealthy = errors.New("doctor found problems")
+
+// shadowingHint tells the user how to remediate a go binary shadowing the managed one
+const shadowingHint = "remove the other go sdk or move the managed directory in front of it on PATH"
+
+// doctorCheck is a health check of the doctor command
+type doctorCheck struct {
+	Name string
+	// Run returns a detail describing the healthy state, or an error describing the problem
+	Run func(ctx context.Context) (detail string, err error)
+	// Hint tells the user how to remediate a failed check
+	Hint string
+	// Fix remediates a failed check automatically if it is safe to do so, if set;
+	// fixes changing files outside of dfctl-go ask for confirmation unless yes is set
+	Fix func(ctx context.Context, yes bool) (detail string, err error)
+}
+
+// doctorOptions are the options of the doctor command
+type doctorOptions struct {
+	// Fix applies the automatic fixes of failed checks
+	Fix bool
+	// Yes confirms fixes without asking
+	Yes bool
+}
+
+func newDoctorCmd() *cobra.Command {
+	var opts doctorOptions
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "diagnoses the health of the installation",
+		Long: "diagnoses the health of the installation; with --fix dangling current links are relinked, shims regenerated, " +
+			"leftovers of interrupted operations removed and the shell hook added to the shell rc after confirmation",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateArgsForSubcommand("doctor", args, 0); err != nil {
+				return err
+			}
+			return defaultExecutor().Doctor(c.Context(), opts)
+		},
+	}
+	cmd.Flags().BoolVar(&opts.Fix, "fix", false, "fix the failed checks automatically where it is safe")
+	cmd.Flags().BoolVarP(&opts.Yes, "yes", "y", false, "apply fixes without asking for confirmation")
+	return cmd
+}
+
+func (e *executor) doctorChecks() []doctorCheck {
+	return []doctorCheck{
+		{
+			Name: "install path",
+			Run:  e.checkInstallPath,
+			Hint: "create the directory or fix its permissions: mkdir -p " + e.InstallPath,
+		},
+		{
+			Name: "current version",
+			Run:  e.checkCurrent,
+			Hint: "link an installed version with 'dfctl-go use <version>' or install one with 'dfctl-go install stable'",
+			Fix:  e.fixCurrent,
+		},
+		{
+			Name: "PATH",
+			Run:  e.checkPath,
+			Hint: "add " + filepath.Join(e.InstallPath, "current", "bin") + " or the shims in " + e.ShimPath + " to PATH, e.g. eval \"$(dfctl-go hook bash)\"",
+			Fix:  e.fixPath,
+		},
+		{
+			Name: "shadowing",
+			Run:  e.checkShadowing,
+			Hint: shadowingHint,
+		},
+		e.fsckDoctorCheck("shims", "shims are up to date", "regenerate the shims with 'dfctl-go rehash'", e.fsckShims),
+		e.fsckDoctorCheck("leftovers", "no leftovers of interrupted operations", "remove the leftovers with 'dfctl-go fsck --repair'", e.fsckLeftovers),
+		{
+			Name: "foreign installs",
+			Run:  e.checkForeignInstalls,
+			Hint: "uninstall and reinstall the versions on this machine",
+		},
+		{
+			Name: "network",
+			Run:  e.checkNetwork,
+			Hint: "check your connection and proxy settings or configure a mirror with " + MirrorEnv,
+		},
+	}
+}
+
+// Doctor runs all health checks and prints their results with remediation hints, fixing failed checks if requested
+func (e *executor) Doctor(ctx context.Context, opts doctorOptions) error {
+	return e.runDoctorChecks(ctx, e.doctorChecks(), opts)
+}
+
+// runDoctorChecks runs the checks and prints their results with remediation hints
+func (e *executor) runDoctorChecks(ctx context.Context, checks []doctorCheck, opts doctorOptions) error {
+	failed := 0
+	for _, check := range checks {
+		detail, err := check.Run(ctx)
+		if err != nil && opts.Fix && check.Fix != nil {
+			fixed, ferr := check.Fix(ctx, opts.Yes)
+			if ferr == nil {
+				_, _ = fmt.Fprintf(e.Streams.Out, "[fix ] %s: %v; %s\n", check.Name, err, fixed)
+				continue
+			}
+			err = fmt.Errorf("%v; fix failed: %v", err, ferr)
+		}
+		if err != nil {
+			failed++
+			_, _ = fmt.Fprintf(e.Streams.Out, "[fail] %s: %v\n       hint: %s\n", check.Name, err, check.Hint)
+			continue
+		}
+		_, _ = fmt.Fprintf(e.Streams.Out, "[ ok ] %s: %s\n", check.Name, detail)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%w; failed=%d", errUnhealthy, failed)
+	}
+	return nil
+}
+
+func (e *executor) checkInstallPath(context.Context) (string, error) {
+	if exists, err := afero.DirExists(e.Fs, e.InstallPath); err != nil || !exists {
+		return "", fmt.Errorf("%s does not exist", e.InstallPath)
+	}
+	probe, err := afero.TempFile(e.Fs, e.InstallPath, ".doctor-*")
+	if err != nil {
+		return "", fmt.Errorf("%s is not writable", e.InstallPath)
+	}
+	_ = probe.Close()
+	_ = e.Fs.Remove(probe.Name())
+	return e.InstallPath + " is writable", nil
+}
+
+func (e *executor) checkCurrent(context.Context) (string, error) {
+	current, err := e.current()
+	if err != nil {
+		return "", err
+	}
+	if dangling, _ := e.fsckCurrent(); len(dangling) > 0 {
+		return "", errors.New(dangling[0].Description)
+	}
+	root, err := e.goroot(current)
+	if err != nil {
+		return "", fmt.Errorf("current version %s is not installed", current)
+	}
+	if _, err = e.Fs.Stat(filepath.Join(root, "bin", "go")); err != nil {
+		return "", fmt.Errorf("%s contains no go binary", root)
+	}
+	return fmt.Sprintf("go %s at %s", current, root), nil
+}
+
+// managedBinDir reports whether dir provides the managed go binaries
+func (e *executor) managedBinDir(dir string) bool {
+	dir = filepath.Clean(dir)
+	if dir == filepath.Clean(e.ShimPath) || dir == filepath.Join(e.InstallPath, "current", "bin") {
+		return true
+	}
+	rel, err := filepath.Rel(e.InstallPath, dir)
+	return err == nil && filepath.Base(rel) == "bin" && filepath.Dir(rel) != "." && filepath.Dir(filepath.Dir(rel)) == "."
+}
+
+func (e *executor) checkPath(context.Context) (string, error) {
+	for _, dir := range filepath.SplitList(env.GetVars().Get("PATH")) {
+		if e.managedBinDir(dir) {
+			return dir + " is on PATH", nil
+		}
+	}
+	return "", errors.New("no managed bin directory is on PATH")
+}
+
+func (e *executor) checkShadowing(context.Context) (string, error) {
+	for _, dir := range filepath.SplitList(env.GetVars().Get("PATH")) {
+		if fi, err := e.Fs.Stat(filepath.Join(dir, "go")); err != nil || fi.IsDir() {
+			continue
+		}
+		if !e.managedBinDir(dir) {
+			return "", fmt.Errorf("%s shadows the managed go", filepath.Join(dir, "go"))
+		}
+		return "go resolves to " + filepath.Join(dir, "go"), nil
+	}
+	return "no go binary on PATH", nil
+}
+
+// warnShadowing warns if another go binary like /usr/local/bin/go or the one of Homebrew precedes the managed one on PATH,
+// so the version just switched to would not run
+func (e *executor) warnShadowing() {
+	if _, err := e.checkShadowing(context.Background()); err != nil {
+		_, _ = fmt.Fprintf(e.Streams.Err, "warning: %v; %s\n", err, shadowingHint)
+	}
+}
+
+func (e *executor) checkForeignInstalls(context.Context) (string, error) {
+	installs, err := e.installations()
+	if err != nil {
+		return "", err
+	}
+	var foreign []string
+	for _, i := range installs {
+		if m, err := e.readManifest(filepath.Join(e.InstallPath, i.Dir)); err == nil && m.foreign() {
+			foreign = append(foreign, m.foreignWarning())
+		}
+	}
+	if len(foreign) > 0 {
+		return "", errors.New(strings.Join(foreign, "\n       "))
+	}
+	return fmt.Sprintf("%d versions are installed for this machine", len(installs)), nil
+}
+
+func (e *executor) checkNetwork(ctx context.Context) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, doctorNetworkTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, e.URL+"/dl/", http.NoBody)
+	if err != nil {
+		return "", err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%s is unreachable; err=%v", e.URL, err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return "", fmt.Errorf("%s responded with %s", e.URL, resp.Status)
+	}
+	return e.URL + " is reachable", nil
+}
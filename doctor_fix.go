@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/alex-held/dfctl-kit/pkg/env"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+var errFixDeclined = errors.New("fix was not confirmed")
+
+// confirm asks question and reports whether the answer was yes
+func (e *executor) confirm(question string) bool {
+	_, _ = fmt.Fprintf(e.Streams.Out, "%s [y/N] ", question)
+	answer, _ := bufio.NewReader(e.Streams.In).ReadString('\n')
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(answer)), "y")
+}
+
+// fsckDoctorCheck turns the fsck check run into a doctor check, which fails while run finds problems and is fixed by repairing them
+func (e *executor) fsckDoctorCheck(name, healthy, hint string, run func() ([]fsckProblem, error)) doctorCheck {
+	return doctorCheck{
+		Name: name,
+		Run: func(context.Context) (string, error) {
+			problems, err := run()
+			if err != nil {
+				return "", err
+			}
+			if len(problems) > 0 {
+				descriptions := make([]string, 0, len(problems))
+				for _, p := range problems {
+					descriptions = append(descriptions, p.Description)
+				}
+				return "", errors.New(strings.Join(descriptions, "; "))
+			}
+			return healthy, nil
+		},
+		Hint: hint,
+		Fix: func(_ context.Context, yes bool) (string, error) {
+			problems, err := run()
+			if err != nil {
+				return "", err
+			}
+			for _, p := range problems {
+				if err = e.repairProblem(p, yes); err != nil {
+					return "", err
+				}
+			}
+			return "repaired", nil
+		},
+	}
+}
+
+// fixCurrent points a dangling current link to the directory the version is installed in now, e.g. after it was reinstalled with another spelling
+func (e *executor) fixCurrent(context.Context, bool) (string, error) {
+	osFs, ok := e.Fs.(*afero.OsFs)
+	if !ok {
+		return "", errOnlyOsFsSupported
+	}
+	link := filepath.Join(e.InstallPath, "current")
+	target, err := osFs.ReadlinkIfPossible(link)
+	if err != nil {
+		return "", errors.New("there is no current link to recreate")
+	}
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(e.InstallPath, target)
+	}
+	if _, err = e.Fs.Stat(target); err == nil {
+		return "", fmt.Errorf("%s is not dangling", link)
+	}
+	version, err := ParseVersion(filepath.Base(target))
+	if err != nil {
+		return "", fmt.Errorf("current links to %s, which names no version", target)
+	}
+	dir, err := e.versionPath(version)
+	if err != nil {
+		return "", fmt.Errorf("go %s is not installed anymore; install it with 'dfctl-go install %s'", version, version)
+	}
+	if err = swapSymlink(osFs, dir, link); err != nil {
+		return "", err
+	}
+	return "relinked current to " + dir, nil
+}
+
+// fixPath appends the shell hook putting the managed go on PATH to the rc of the login shell after confirmation
+func (e *executor) fixPath(_ context.Context, yes bool) (string, error) {
+	setup := completionSetups[defaultShell()]
+	rc := filepath.Join(env.GetVars().Get("HOME"), setup.RC)
+	data, err := afero.ReadFile(e.Fs, rc)
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to read %s; err=%v", rc, err)
+	}
+	if bytes.Contains(data, []byte(setup.Hook)) {
+		return "", fmt.Errorf("%s already runs the hook; restart the shell", rc)
+	}
+	if !yes && !(isTerminal(e.Streams.In) && e.confirm(fmt.Sprintf("add '%s' to %s?", setup.Hook, rc))) {
+		return "", fmt.Errorf("%w; pass --yes to add the hook to %s", errFixDeclined, rc)
+	}
+
+	if len(data) > 0 && !bytes.HasSuffix(data, []byte("\n")) {
+		data = append(data, '\n')
+	}
+	data = append(data, setup.Hook+"\n"...)
+	if err = e.Fs.MkdirAll(filepath.Dir(rc), 0755); err != nil {
+		return "", err
+	}
+	if err = afero.WriteFile(e.Fs, rc, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s; err=%v", rc, err)
+	}
+	return fmt.Sprintf("added '%s' to %s; restart the shell", setup.Hook, rc), nil
+}
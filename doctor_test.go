@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/env"
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+)
+
+func TestDoctor(t *testing.T) {
+	testutils.Run(t, "Doctor", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		other := testutils.TempDir(t, "usr", "local", "bin")
+		server := newReleaseServer("1.17.1")
+		var out *Buffer
+
+		g.BeforeEach(func() {
+			createVersionDirs()
+			bin := filepath.Join(InstallPath, "v1.17.1", "bin")
+			_ = os.MkdirAll(bin, os.ModePerm)
+			_ = os.WriteFile(filepath.Join(bin, "go"), []byte{}, 0755)
+			symlink(afero.NewOsFs(), filepath.Join(InstallPath, "v1.17.1"), filepath.Join(InstallPath, "current"))
+			env.Overrides.Vars = env.Vars{"PATH": filepath.Join(InstallPath, "current", "bin") + ":/usr/bin"}
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+			_ = os.RemoveAll(other)
+			env.ClearOverrides()
+		})
+
+		g.After(func() {
+			server.Close()
+		})
+
+		newSut := func() *executor {
+			out = &Buffer{&bytes.Buffer{}}
+			sut := defaultExecutor()
+			sut.URL = server.URL
+			sut.Streams.Out = out
+			return sut
+		}
+
+		g.It("passes for a healthy installation", func() {
+			Ω(newSut().Doctor(context.Background(), doctorOptions{})).Should(Succeed())
+			Ω(out.String()).ShouldNot(ContainSubstring("[fail]"))
+		})
+
+		g.It("detects a broken current link", func() {
+			_ = os.RemoveAll(filepath.Join(InstallPath, "v1.17.1"))
+			err := newSut().Doctor(context.Background(), doctorOptions{})
+			Ω(errors.Is(err, errUnhealthy)).Should(BeTrue())
+			Ω(out.String()).Should(ContainSubstring("[fail] current version"))
+		})
+
+		g.It("detects missing PATH entries", func() {
+			env.Overrides.Vars = env.Vars{"PATH": "/usr/bin"}
+			Ω(newSut().Doctor(context.Background(), doctorOptions{})).ShouldNot(Succeed())
+			Ω(out.String()).Should(ContainSubstring("[fail] PATH"))
+		})
+
+		g.It("detects shadowing go binaries", func() {
+			_ = os.MkdirAll(other, os.ModePerm)
+			_ = os.WriteFile(filepath.Join(other, "go"), []byte{}, 0755)
+			env.Overrides.Vars = env.Vars{"PATH": other + ":" + filepath.Join(InstallPath, "current", "bin")}
+			Ω(newSut().Doctor(context.Background(), doctorOptions{})).ShouldNot(Succeed())
+			Ω(out.String()).Should(ContainSubstring("[fail] shadowing: " + filepath.Join(other, "go") + " shadows the managed go"))
+		})
+
+		g.It("detects versions installed for another platform", func() {
+			m := installManifest{Version: "1.17.1", OS: "plan9", Arch: "mips", InstalledBy: installFingerprint{OS: "plan9", Arch: "mips", Hostname: "intel-mac", Version: "0.9.0"}}
+			Ω(newSut().writeManifest(filepath.Join(InstallPath, "v1.17.1"), m)).Should(Succeed())
+			Ω(newSut().Doctor(context.Background(), doctorOptions{})).ShouldNot(Succeed())
+			Ω(out.String()).Should(ContainSubstring("[fail] foreign installs: go 1.17.1 is installed for plan9-mips"))
+			Ω(out.String()).Should(ContainSubstring("installed on intel-mac (plan9-mips) by dfctl-go 0.9.0"))
+		})
+
+		g.It("detects an unreachable download host", func() {
+			sut := newSut()
+			sut.URL = "http://127.0.0.1:1"
+			Ω(sut.Doctor(context.Background(), doctorOptions{})).ShouldNot(Succeed())
+			Ω(out.String()).Should(ContainSubstring("[fail] network"))
+		})
+
+		g.Describe("after use", func() {
+			var errOut *Buffer
+
+			newUseSut := func() *executor {
+				sut := newSut()
+				errOut = &Buffer{&bytes.Buffer{}}
+				sut.Streams.Err = errOut
+				return sut
+			}
+
+			g.It("warns about go binaries shadowing the managed one", func() {
+				_ = os.MkdirAll(other, os.ModePerm)
+				_ = os.WriteFile(filepath.Join(other, "go"), []byte{}, 0755)
+				env.Overrides.Vars = env.Vars{"PATH": other + ":" + filepath.Join(InstallPath, "current", "bin")}
+				Ω(newUseSut().Use("1.17.1")).Should(Succeed())
+				Ω(errOut.String()).Should(ContainSubstring("warning: " + filepath.Join(other, "go") + " shadows the managed go"))
+			})
+
+			g.It("does not warn if the managed go comes first", func() {
+				_ = os.MkdirAll(other, os.ModePerm)
+				_ = os.WriteFile(filepath.Join(other, "go"), []byte{}, 0755)
+				env.Overrides.Vars = env.Vars{"PATH": filepath.Join(InstallPath, "current", "bin") + ":" + other}
+				Ω(newUseSut().Use("1.17.1")).Should(Succeed())
+				Ω(errOut.String()).ShouldNot(ContainSubstring("shadows"))
+			})
+		})
+
+		g.Describe("with --fix", func() {
+			home := testutils.TempDir(t, "home")
+
+			g.AfterEach(func() {
+				_ = os.RemoveAll(home)
+			})
+
+			g.It("relinks a dangling current link to the reinstalled version", func() {
+				_ = os.Rename(filepath.Join(InstallPath, "v1.17.1"), filepath.Join(InstallPath, "1.17.1"))
+				Ω(newSut().Doctor(context.Background(), doctorOptions{Fix: true})).Should(Succeed())
+				Ω(out.String()).Should(ContainSubstring("[fix ] current version"))
+				Ω(os.Readlink(filepath.Join(InstallPath, "current"))).Should(Equal(filepath.Join(InstallPath, "1.17.1")))
+			})
+
+			g.It("fails to relink the current link if the version is not installed", func() {
+				_ = os.RemoveAll(filepath.Join(InstallPath, "v1.17.1"))
+				Ω(newSut().Doctor(context.Background(), doctorOptions{Fix: true})).ShouldNot(Succeed())
+				Ω(out.String()).Should(ContainSubstring("fix failed: go 1.17.1 is not installed anymore"))
+			})
+
+			g.It("removes leftovers of interrupted operations", func() {
+				staging := filepath.Join(InstallPath, "1.18.0"+stagingSuffix)
+				_ = os.MkdirAll(staging, os.ModePerm)
+				Ω(newSut().Doctor(context.Background(), doctorOptions{})).ShouldNot(Succeed())
+				Ω(out.String()).Should(ContainSubstring("[fail] leftovers: " + staging + " is a leftover"))
+
+				Ω(newSut().Doctor(context.Background(), doctorOptions{Fix: true})).Should(Succeed())
+				Ω(out.String()).Should(ContainSubstring("[fix ] leftovers"))
+				Ω(staging).ShouldNot(BeADirectory())
+			})
+
+			g.It("adds the shell hook to the shell rc", func() {
+				env.Overrides.Vars = env.Vars{"PATH": "/usr/bin", "HOME": home, "SHELL": "/bin/zsh"}
+				Ω(newSut().Doctor(context.Background(), doctorOptions{Fix: true, Yes: true})).Should(Succeed())
+				Ω(out.String()).Should(ContainSubstring("[fix ] PATH"))
+				rc, _ := os.ReadFile(filepath.Join(home, ".zshrc"))
+				Ω(string(rc)).Should(Equal(completionSetups["zsh"].Hook + "\n"))
+			})
+
+			g.It("does not change the shell rc without confirmation", func() {
+				env.Overrides.Vars = env.Vars{"PATH": "/usr/bin", "HOME": home, "SHELL": "/bin/zsh"}
+				err := newSut().Doctor(context.Background(), doctorOptions{Fix: true})
+				Ω(errors.Is(err, errUnhealthy)).Should(BeTrue())
+				Ω(out.String()).Should(ContainSubstring("pass --yes"))
+				Ω(filepath.Join(home, ".zshrc")).ShouldNot(BeAnExistingFile())
+			})
+		})
+	})
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+
+	"github.com/alex-held/dfctl-kit/pkg/system"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+// addDryRunFlag adds the --dry-run flag to cmd, which prints the planned changes instead of performing them
+func addDryRunFlag(cmd *cobra.Command, dryRun *bool) {
+	cmd.Flags().BoolVar(dryRun, "dry-run", false, "print what would be downloaded, extracted, linked or deleted without changing anything")
+}
+
+// isDryRun reports whether --dry-run was passed to c, which needs neither the lock nor a summary
+func isDryRun(c *cobra.Command) bool {
+	f := c.Flags().Lookup("dry-run")
+	return f != nil && f.Value.String() == "true"
+}
+
+// planf prints a planned change of a dry run, which is printed even with --quiet
+func (e *executor) planf(format string, args ...interface{}) {
+	_, _ = fmt.Fprintf(e.Streams.Out, format, args...)
+}
+
+// PlanInstall prints the archive Install would download with its size, or the cached archive it would use,
+// and the directory it would extract it into
+func (e *executor) PlanInstall(version Version) error {
+	if dir, err := e.versionPath(version); err == nil && !e.Force {
+		e.planf("go %s is already installed in %s\n", version, dir)
+		return nil
+	}
+	ri := system.OSRuntimeInfoGetter{}.Get()
+	dlUri := e.artifactURL(ri, version)
+	switch f, err := e.releaseFile(e.Ctx, path.Base(dlUri)); {
+	case e.Offline:
+		if _, err = e.cachedArchiveOf(version, ri); err != nil {
+			return err
+		}
+		e.planf("would use the cached archive of go %s\n", version)
+	case err != nil:
+		e.planf("would download %s (size unknown; %v)\n", dlUri, err)
+	default:
+		if exists, _ := afero.Exists(e.Fs, e.cacheFile(f)); exists {
+			e.planf("would use the cached archive %s (%s)\n", e.cacheFile(f), formatBytes(f.Size))
+		} else {
+			e.planf("would download %s (%s)\n", dlUri, formatBytes(f.Size))
+		}
+	}
+	e.planf("would extract go %s into %s\n", version, filepath.Join(e.InstallPath, version.String()))
+	return nil
+}
+
+// PlanInstallVersions prints the plan of install like PlanInstall for every version of args
+func (e *executor) PlanInstallVersions(ctx context.Context, args []string, includeUnstable bool) error {
+	resolved, failures := e.resolveVersionArgs(ctx, args, includeUnstable)
+	for _, r := range resolved {
+		if err := e.PlanInstall(r.Version); err != nil {
+			failures = append(failures, bulkFailure{Item: r.Arg, Err: err})
+		}
+	}
+	return newBulkError("install", "versions", len(args), failures)
+}
+
+// PlanInstallAndUse prints the plan of install like PlanInstall followed by the link InstallAndUse would point to version
+func (e *executor) PlanInstallAndUse(version Version) error {
+	if err := e.PlanInstall(version); err != nil {
+		return err
+	}
+	if _, err := e.versionPath(version); err != nil {
+		e.planf("would link %s to %s\n", filepath.Join(e.InstallPath, "current"), filepath.Join(e.InstallPath, version.String()))
+		return nil
+	}
+	return e.PlanUse(version)
+}
+
+// PlanUse prints the link Use would point to version
+func (e *executor) PlanUse(version Version) error {
+	if version == SystemVersion {
+		e.planf("would remove %s and use the system go\n", filepath.Join(e.InstallPath, "current"))
+		return nil
+	}
+	dir, err := e.versionPath(version)
+	if err != nil {
+		ext, ok := e.external(version)
+		if !ok {
+			return err
+		}
+		dir = ext.Root
+	}
+	e.planf("would link %s to %s\n", filepath.Join(e.InstallPath, "current"), dir)
+	return nil
+}
+
+// PlanUninstall prints the directory Uninstall would delete with its size
+func (e *executor) PlanUninstall(version Version) error {
+	dir, err := e.versionPath(version)
+	if err != nil {
+		if ext, ok := e.external(version); ok {
+			return fmt.Errorf("%w; version=%s; root=%s", errExternalVersion, version, ext.Root)
+		}
+		return err
+	}
+	if current, err := e.current(); err == nil && current.Compare(version) == 0 {
+		return errVersionInUse
+	}
+	return e.planRemove(version, dir)
+}
+
+// PlanUninstallAllExceptCurrent prints the directories UninstallAllExceptCurrent would delete with their sizes
+func (e *executor) PlanUninstallAllExceptCurrent() error {
+	current, err := e.current()
+	if err != nil {
+		return err
+	}
+	installs, err := e.installations()
+	if err != nil {
+		return err
+	}
+	for _, i := range installs {
+		if i.Version.Compare(current) != 0 {
+			if err = e.planRemove(i.Version, filepath.Join(e.InstallPath, i.Dir)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (e *executor) planRemove(version Version, dir string) error {
+	size, err := dirSize(e.Fs, dir)
+	if err != nil {
+		return err
+	}
+	e.planf("would delete go %s in %s (%s)\n", version, dir, formatBytes(size))
+	return nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/system"
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+)
+
+func TestDryRun(t *testing.T) {
+	testutils.Run(t, "--dry-run", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		server := newReleaseServer("1.22.1")
+		var out *Buffer
+		var sut *executor
+		var externalRoots []string
+
+		g.BeforeEach(func() {
+			externalRoots, ExternalGoRoots = ExternalGoRoots, nil
+			createVersionDirs()
+			_ = afero.WriteFile(afero.NewOsFs(), filepath.Join(InstallPath, "v1.16.8", "VERSION"), []byte("go1.16.8"), 0644)
+			symlink(afero.NewOsFs(), filepath.Join(InstallPath, "v1.17.1"), filepath.Join(InstallPath, "current"))
+			out = &Buffer{&bytes.Buffer{}}
+			sut = defaultExecutor()
+			sut.URL = server.URL
+			sut.CachePath = filepath.Join(InstallPath, ".cache")
+			sut.Streams.Out = out
+		})
+
+		g.AfterEach(func() {
+			ExternalGoRoots = externalRoots
+			_ = os.RemoveAll(InstallPath)
+		})
+
+		g.After(func() {
+			server.Close()
+		})
+
+		g.It("prints the url, size and install directory of install", func() {
+			Ω(sut.PlanInstall("1.22.1")).Should(Succeed())
+			url := sut.artifactURL(system.OSRuntimeInfoGetter{}.Get(), "1.22.1")
+			Ω(out.String()).Should(Equal(fmt.Sprintf("would download %s (%s)\nwould extract go 1.22.1 into %s\n",
+				url, formatBytes(int64(len(archiveData))), filepath.Join(InstallPath, "1.22.1"))))
+			Ω(filepath.Join(InstallPath, "1.22.1")).ShouldNot(BeADirectory())
+		})
+
+		g.It("reports installed versions", func() {
+			Ω(sut.PlanInstall("1.16.8")).Should(Succeed())
+			Ω(out.String()).Should(Equal(fmt.Sprintf("go 1.16.8 is already installed in %s\n", filepath.Join(InstallPath, "v1.16.8"))))
+		})
+
+		g.It("prints the link of use", func() {
+			Ω(sut.PlanUse("1.16.8")).Should(Succeed())
+			Ω(out.String()).Should(Equal(fmt.Sprintf("would link %s to %s\n", filepath.Join(InstallPath, "current"), filepath.Join(InstallPath, "v1.16.8"))))
+			current, _ := sut.current()
+			Ω(current).Should(Equal(Version("1.17.1")))
+		})
+
+		g.It("prints the directory and size deleted by uninstall", func() {
+			Ω(sut.PlanUninstall("1.16.8")).Should(Succeed())
+			Ω(out.String()).Should(Equal(fmt.Sprintf("would delete go 1.16.8 in %s (8 B)\n", filepath.Join(InstallPath, "v1.16.8"))))
+			Ω(filepath.Join(InstallPath, "v1.16.8")).Should(BeADirectory())
+		})
+
+		g.It("refuses to plan uninstalling the current version", func() {
+			Ω(sut.PlanUninstall("1.17.1")).Should(MatchError(errVersionInUse))
+		})
+
+		g.It("prints every version deleted by uninstall --all-except-current", func() {
+			Ω(sut.PlanUninstallAllExceptCurrent()).Should(Succeed())
+			Ω(out.String()).Should(ContainSubstring("would delete go 1.13.5 in " + filepath.Join(InstallPath, "v1.13.5")))
+			Ω(out.String()).ShouldNot(ContainSubstring("go 1.17.1 "))
+			Ω(filepath.Join(InstallPath, "v1.13.5")).Should(BeADirectory())
+		})
+
+		g.It("prints the plan even with --quiet", func() {
+			sut.Quiet = true
+			Ω(sut.PlanUse("1.16.8")).Should(Succeed())
+			Ω(out.String()).ShouldNot(BeEmpty())
+		})
+	})
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/alex-held/dfctl-kit/pkg/env"
+	"github.com/spf13/cobra"
+)
+
+// envShells are the shells env prints exports for
+var envShells = []string{"bash", "zsh", "fish", "powershell"}
+
+func newEnvCmd() *cobra.Command {
+	var shell string
+	cmd := &cobra.Command{
+		Use:   "env",
+		Short: "prints shell exports of GOROOT and PATH for the go version in effect",
+		Long:  "prints shell exports of GOROOT and PATH for the go version in effect for the working directory; run eval \"$(dfctl-go env)\" in your shell rc or ci scripts",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateArgsForSubcommand("env", args, 0); err != nil {
+				return err
+			}
+			wd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			return defaultExecutor().Env(shell, wd)
+		},
+	}
+	cmd.Flags().StringVar(&shell, "shell", defaultShell(), "shell syntax of the exports; one of "+strings.Join(envShells, ", "))
+	return cmd
+}
+
+// defaultShell returns the login shell if it is supported, falling back to bash
+func defaultShell() string {
+	shell := filepath.Base(env.GetVars().Get("SHELL"))
+	for _, s := range envShells {
+		if s == shell {
+			return s
+		}
+	}
+	return "bash"
+}
+
+// Env prints the exports of GOROOT and PATH of the version in effect for dir
+func (e *executor) Env(shell, dir string) error {
+	supported := false
+	for _, s := range envShells {
+		supported = supported || s == shell
+	}
+	if !supported {
+		return fmt.Errorf("%w; shell=%s", errUnsupportedShell, shell)
+	}
+	goroot, path, err := e.goEnv(dir)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprint(e.Streams.Out, exportStatement(shell, "GOROOT", goroot))
+	_, _ = fmt.Fprint(e.Streams.Out, exportStatement(shell, "PATH", path))
+	return nil
+}
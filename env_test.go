@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/env"
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+)
+
+func TestEnv(t *testing.T) {
+	testutils.Run(t, "Env", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		project := filepath.Join(testutils.TempDir(t), "project")
+		var out *Buffer
+
+		g.BeforeEach(func() {
+			createVersionDirs()
+			_ = os.MkdirAll(project, os.ModePerm)
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+			_ = os.RemoveAll(project)
+			env.ClearOverrides()
+		})
+
+		newSut := func() *executor {
+			out = &Buffer{&bytes.Buffer{}}
+			sut := defaultExecutor()
+			sut.Streams.Out = out
+			return sut
+		}
+
+		g.It("exports the global version", func() {
+			symlink(afero.NewOsFs(), filepath.Join(InstallPath, "v1.16.8"), filepath.Join(InstallPath, "current"))
+			Ω(newSut().Env("bash", project)).Should(Succeed())
+			Ω(out.String()).Should(HavePrefix("export GOROOT=" + filepath.Join(InstallPath, "v1.16.8") + ";\n"))
+			Ω(out.String()).Should(ContainSubstring("export PATH=" + filepath.Join(InstallPath, "v1.16.8", "bin")))
+		})
+
+		g.It("supports powershell", func() {
+			symlink(afero.NewOsFs(), filepath.Join(InstallPath, "v1.16.8"), filepath.Join(InstallPath, "current"))
+			Ω(newSut().Env("powershell", project)).Should(Succeed())
+			Ω(out.String()).Should(HavePrefix("$env:GOROOT = '" + filepath.Join(InstallPath, "v1.16.8") + "'\n"))
+		})
+
+		g.It("fails without version in effect", func() {
+			Ω(newSut().Env("bash", project)).ShouldNot(Succeed())
+		})
+
+		g.It("rejects unsupported shells", func() {
+			Ω(errors.Is(newSut().Env("tcsh", project), errUnsupportedShell)).Should(BeTrue())
+		})
+
+		g.It("defaults to the login shell", func() {
+			env.Overrides.Vars = env.Vars{"SHELL": "/usr/bin/fish"}
+			Ω(defaultShell()).Should(Equal("fish"))
+		})
+	})
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func newExecCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "exec [version] -- <command> [args...]",
+		Short: "runs a command with a specific go version",
+		Long: "runs a command with GOROOT set to the sdk of the version and its bin directory prepended to PATH, without changing the global version; " +
+			"without version the version in effect for the working directory is used",
+		Example: "  dfctl-go exec 1.21.5 -- go test ./...",
+		RunE: func(c *cobra.Command, args []string) error {
+			arg, command := "", args
+			switch dash := c.ArgsLenAtDash(); {
+			case dash == 1:
+				arg, command = args[0], args[1:]
+			case dash > 1:
+				return fmt.Errorf("provided too many arguments for subcommand 'exec'; max=1; provided=%d", dash)
+			case dash < 0 && len(args) > 0:
+				arg, command = args[0], args[1:]
+			}
+			if len(command) == 0 {
+				return fmt.Errorf("no command to execute; usage: %s", c.UseLine())
+			}
+			return defaultExecutor().Exec(c.Context(), arg, command)
+		},
+	}
+	cmd.Flags().SetInterspersed(false)
+	return cmd
+}
+
+// Exec runs command with the sdk of the version resolved from arg, or the version in effect for the working directory if arg is empty
+func (e *executor) Exec(ctx context.Context, arg string, command []string) error {
+	version, err := e.execVersion(ctx, arg)
+	if err != nil {
+		return err
+	}
+	goroot, err := e.goroot(version)
+	if err != nil {
+		return fmt.Errorf("%w; version=%s", err, version)
+	}
+	// without the shims on PATH the command and its children run the binaries of the version directly
+	path := e.managedPath(withoutPathEntry(os.Getenv("PATH"), e.ShimPath), filepath.Join(goroot, "bin"))
+
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Env = withEnv(os.Environ(), "GOROOT="+goroot, "PATH="+path)
+	// resolve the executable with the PATH of the version, so go runs the sdk of the version
+	if lp, err := lookPath(command[0], path); err == nil {
+		cmd.Path = lp
+	}
+	cmd.Stdin = e.Streams.In
+	cmd.Stdout = e.Streams.Out
+	cmd.Stderr = e.Streams.Err
+	return cmd.Run()
+}
+
+func (e *executor) execVersion(ctx context.Context, arg string) (Version, error) {
+	if arg != "" {
+		return e.resolveVersion(ctx, arg, installedScope, false)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	version, _, err := e.resolved(wd)
+	return version, err
+}
+
+// withEnv returns environ with the key=value pairs of vars replacing existing entries
+func withEnv(environ []string, vars ...string) []string {
+	result := make([]string, 0, len(environ)+len(vars))
+	for _, kv := range environ {
+		replaced := false
+		for _, v := range vars {
+			if strings.SplitN(kv, "=", 2)[0] == strings.SplitN(v, "=", 2)[0] {
+				replaced = true
+			}
+		}
+		if !replaced {
+			result = append(result, kv)
+		}
+	}
+	return append(result, vars...)
+}
+
+// lookPath searches the executable name in the directories of path
+func lookPath(name, path string) (string, error) {
+	if strings.ContainsRune(name, filepath.Separator) {
+		return exec.LookPath(name)
+	}
+	for _, dir := range filepath.SplitList(path) {
+		if lp, err := exec.LookPath(filepath.Join(dir, name)); err == nil {
+			return lp, nil
+		}
+	}
+	return "", exec.ErrNotFound
+}
+
+// withoutPathEntry returns path without the directory dir
+func withoutPathEntry(path, dir string) string {
+	var entries []string
+	for _, entry := range filepath.SplitList(path) {
+		if filepath.Clean(entry) != filepath.Clean(dir) {
+			entries = append(entries, entry)
+		}
+	}
+	return strings.Join(entries, string(filepath.ListSeparator))
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestExec(t *testing.T) {
+	testutils.Run(t, "Exec", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		var out *Buffer
+
+		g.BeforeEach(func() {
+			createVersionDirs()
+			bin := filepath.Join(InstallPath, "v1.17.1", "bin")
+			_ = os.MkdirAll(bin, os.ModePerm)
+			_ = os.WriteFile(filepath.Join(bin, "go"), []byte("#!/bin/sh\necho \"go $GOROOT $@\"\n"), 0755)
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+		})
+
+		newSut := func() *executor {
+			out = &Buffer{&bytes.Buffer{}}
+			sut := defaultExecutor()
+			sut.Streams.Out = out
+			return sut
+		}
+
+		g.It("runs the go binary of the version", func() {
+			Ω(newSut().Exec(context.Background(), "1.17", []string{"go", "test", "./..."})).Should(Succeed())
+			Ω(out.String()).Should(Equal("go " + filepath.Join(InstallPath, "v1.17.1") + " test ./...\n"))
+		})
+
+		g.It("sets GOROOT for other commands", func() {
+			Ω(newSut().Exec(context.Background(), "1.17.1", []string{"sh", "-c", "echo $GOROOT"})).Should(Succeed())
+			Ω(out.String()).Should(Equal(filepath.Join(InstallPath, "v1.17.1") + "\n"))
+		})
+
+		g.It("fails for versions which are not installed", func() {
+			Ω(newSut().Exec(context.Background(), "1.18.0", []string{"go", "version"})).ShouldNot(Succeed())
+		})
+	})
+}
+
+func TestWithEnv(t *testing.T) {
+	testutils.Run(t, "withEnv", func(g *goblin.G) {
+
+		g.It("replaces existing variables", func() {
+			Ω(withEnv([]string{"HOME=/root", "GOROOT=/usr/local/go"}, "GOROOT=/sdk")).Should(Equal([]string{"HOME=/root", "GOROOT=/sdk"}))
+		})
+	})
+}
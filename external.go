@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/afero"
+)
+
+// ExternalGoRoots are the glob patterns of go sdks managed by system package managers
+var ExternalGoRoots = []string{
+	// Homebrew on Apple Silicon and Intel
+	"/opt/homebrew/opt/go/libexec",
+	"/usr/local/opt/go/libexec",
+	// apt, e.g. golang-1.21-go
+	"/usr/lib/go-*",
+}
+
+// externalMarker is the file in the install path holding the root of the external sdk used as current version
+const externalMarker = "current-external"
+
+var errExternalVersion = errors.New("go version is managed externally")
+
+// external is a go sdk managed by a system package manager, which dfctl-go only activates but never modifies
+type external struct {
+	Version Version
+	Root    string
+}
+
+// externals returns the go sdks found at ExternalGoRoots
+func (e *executor) externals() (externals []external) {
+	for _, pattern := range ExternalGoRoots {
+		roots, err := afero.Glob(e.Fs, pattern)
+		if err != nil {
+			continue
+		}
+		for _, root := range roots {
+			version, err := sdkVersion(e.Fs, root)
+			if err != nil {
+				log.Debug().Err(err).Msgf("skipping external go sdk at %s", root)
+				continue
+			}
+			externals = append(externals, external{Version: version, Root: root})
+		}
+	}
+	return externals
+}
+
+// external returns the external sdk of version
+func (e *executor) external(version Version) (external, bool) {
+	for _, ext := range e.externals() {
+		if ext.Version.Compare(version) == 0 {
+			return ext, true
+		}
+	}
+	return external{}, false
+}
+
+// sdkVersion reads the version of the go sdk at root from its VERSION file
+func sdkVersion(fs afero.Fs, root string) (Version, error) {
+	f, err := fs.Open(filepath.Join(root, "VERSION"))
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() {
+		return "", fmt.Errorf("empty VERSION file in %s", root)
+	}
+	return ParseVersion(strings.TrimSpace(scanner.Text()))
+}
+
+// goroot returns the root of the sdk of version, which is either installed, managed externally or the system go
+func (e *executor) goroot(version Version) (string, error) {
+	if version == SystemVersion {
+		return e.systemGoroot()
+	}
+	if path, err := e.versionPath(version); err == nil {
+		return path, nil
+	}
+	if ext, ok := e.external(version); ok {
+		return ext.Root, nil
+	}
+	return "", ErrVersionNotInstalled
+}
+
+// useExternal activates the external sdk as current version without linking it;
+// the sdk is put on the PATH by the env command and the shell hook
+func (e *executor) useExternal(ext external) error {
+	_ = e.Fs.Remove(filepath.Join(e.InstallPath, "current"))
+	_ = e.Fs.Remove(filepath.Join(e.InstallPath, systemMarker))
+	if err := afero.WriteFile(e.Fs, filepath.Join(e.InstallPath, externalMarker), []byte(ext.Root+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to activate external go sdk %s; err=%v", ext.Root, err)
+	}
+	_, _ = fmt.Fprintf(e.Streams.Out, "using external go %s at %s; run eval \"$(dfctl-go env)\" to update GOROOT and PATH\n", ext.Version, ext.Root)
+	return nil
+}
+
+// currentExternal returns the external sdk used as current version
+func (e *executor) currentExternal() (external, error) {
+	data, err := afero.ReadFile(e.Fs, filepath.Join(e.InstallPath, externalMarker))
+	if err != nil {
+		return external{}, errNoCurrentVersion
+	}
+	root := strings.TrimSpace(string(data))
+	version, err := sdkVersion(e.Fs, root)
+	if err != nil {
+		return external{}, fmt.Errorf("external go sdk %s used as current version is gone; err=%v", root, err)
+	}
+	return external{Version: version, Root: root}, nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+)
+
+func TestExternals(t *testing.T) {
+	testutils.Run(t, "externals", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		system := testutils.TempDir(t, "usr", "lib")
+		root := filepath.Join(system, "go-1.21")
+		defaultRoots := ExternalGoRoots
+		var out *Buffer
+
+		g.BeforeEach(func() {
+			ExternalGoRoots = []string{filepath.Join(system, "go-*")}
+			createVersionDirs()
+			_ = os.MkdirAll(filepath.Join(root, "bin"), os.ModePerm)
+			_ = os.WriteFile(filepath.Join(root, "VERSION"), []byte("go1.21.5\ntime 2023-11-29T21:21:48Z\n"), os.ModePerm)
+		})
+
+		g.AfterEach(func() {
+			ExternalGoRoots = defaultRoots
+			_ = os.RemoveAll(InstallPath)
+			_ = os.RemoveAll(system)
+		})
+
+		newSut := func() *executor {
+			out = &Buffer{&bytes.Buffer{}}
+			sut := defaultExecutor()
+			sut.Streams.Out = out
+			return sut
+		}
+
+		g.It("detects the version from the VERSION file", func() {
+			Ω(newSut().externals()).Should(Equal([]external{{Version: "1.21.5", Root: root}}))
+		})
+
+		g.It("lists external versions", func() {
+			Ω(newSut().List()).Should(Succeed())
+			Ω(out.String()).Should(HaveSuffix("1.21.5 (external " + root + ")\n"))
+		})
+
+		g.It("uses external versions without linking them", func() {
+			sut := newSut()
+			Ω(sut.Use("1.21.5")).Should(Succeed())
+			Ω(filepath.Join(InstallPath, "current")).ShouldNot(BeAnExistingFile())
+			Ω(sut.current()).Should(Equal(Version("1.21.5")))
+			Ω(sut.goroot("1.21.5")).Should(Equal(root))
+		})
+
+		g.It("prefers installed versions", func() {
+			sut := newSut()
+			Ω(sut.Use("1.21.5")).Should(Succeed())
+			Ω(sut.Use("1.16.8")).Should(Succeed())
+			Ω(filepath.Join(InstallPath, externalMarker)).ShouldNot(BeAnExistingFile())
+			Ω(sut.current()).Should(Equal(Version("1.16.8")))
+		})
+
+		g.It("resolves partial versions to external versions", func() {
+			Ω(newSut().installedVersions()).Should(ContainElement(Version("1.21.5")))
+		})
+
+		g.It("refuses to uninstall external versions", func() {
+			Ω(errors.Is(newSut().Uninstall("1.21.5"), errExternalVersion)).Should(BeTrue())
+			Ω(root).Should(BeADirectory())
+		})
+
+		g.It("reports the current external version gone", func() {
+			sut := newSut()
+			Ω(sut.Use("1.21.5")).Should(Succeed())
+			_ = afero.NewOsFs().RemoveAll(root)
+			_, err := sut.current()
+			Ω(err).ShouldNot(Succeed())
+		})
+	})
+}
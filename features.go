@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// features are optional subsystems packagers can leave out of minimal builds with the no<feature> build tag,
+// e.g. go build -tags notui,nooci
+const (
+	featureDaemon = "daemon"
+	featureTUI    = "tui"
+	featureOCI    = "oci"
+)
+
+// compiledFeatures are the features compiled into the binary.
+// The code of a feature is behind the !no<feature> build tag; the stubs built with the no<feature> build tag remove it in init.
+var compiledFeatures = map[string]bool{
+	featureDaemon: true,
+	featureTUI:    true,
+	featureOCI:    true,
+}
+
+var errNotCompiledIn = errors.New("feature is not compiled in")
+
+// notCompiledInCmd returns the hidden stand-in for a command of a feature left out of the binary,
+// which accepts any flags and arguments of the command and fails with errNotCompiledIn
+func notCompiledInCmd(feature, use, short string) *cobra.Command {
+	return &cobra.Command{
+		Use:                use,
+		Short:              short,
+		Hidden:             true,
+		SilenceUsage:       true,
+		DisableFlagParsing: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			return notCompiledIn(feature)
+		},
+	}
+}
+
+// notCompiledIn returns the errNotCompiledIn of the feature
+func notCompiledIn(feature string) error {
+	return fmt.Errorf("%w; feature=%s; the binary was built with the no%s build tag", errNotCompiledIn, feature, feature)
+}
+
+// enabledFeatures returns the sorted names of the features compiled into the binary
+func enabledFeatures() (features []string) {
+	for feature, enabled := range compiledFeatures {
+		if enabled {
+			features = append(features, feature)
+		}
+	}
+	sort.Strings(features)
+	return features
+}
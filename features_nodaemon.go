@@ -0,0 +1,14 @@
+//go:build nodaemon
+// +build nodaemon
+
+package main
+
+import "github.com/spf13/cobra"
+
+func init() {
+	compiledFeatures[featureDaemon] = false
+}
+
+func newMirrorServeCmd() *cobra.Command {
+	return notCompiledInCmd(featureDaemon, "serve", "serves a mirror directory and optionally replicates it from another mirror")
+}
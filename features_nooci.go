@@ -0,0 +1,14 @@
+//go:build nooci
+// +build nooci
+
+package main
+
+import "github.com/spf13/cobra"
+
+func init() {
+	compiledFeatures[featureOCI] = false
+}
+
+func newGenerateContainerfileCmd() *cobra.Command {
+	return notCompiledInCmd(featureOCI, "containerfile", "generates a Containerfile using an installed go sdk instead of a golang image")
+}
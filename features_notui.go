@@ -0,0 +1,19 @@
+//go:build notui
+// +build notui
+
+package main
+
+import "context"
+
+func init() {
+	compiledFeatures[featureTUI] = false
+}
+
+// interactive reports false, versions are never picked without the tui feature
+func (e *executor) interactive() bool {
+	return false
+}
+
+func (e *executor) pickVersion(ctx context.Context, remote bool) (string, error) {
+	return "", notCompiledIn(featureTUI)
+}
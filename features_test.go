@@ -0,0 +1,40 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+)
+
+func TestNotCompiledInCmd(t *testing.T) {
+	testutils.Run(t, "notCompiledInCmd", func(g *goblin.G) {
+		compiled := compiledFeatures
+		g.AfterEach(func() {
+			compiledFeatures = compiled
+		})
+
+		g.It("fails with errNotCompiledIn", func() {
+			cmd := notCompiledInCmd(featureTUI, "tui", "picks versions")
+			Ω(cmd.Hidden).Should(BeTrue())
+			err := cmd.RunE(cmd, nil)
+			Ω(errors.Is(err, errNotCompiledIn)).Should(BeTrue())
+			Ω(err).Should(MatchError(ContainSubstring("notui")))
+		})
+
+		g.It("accepts the flags of the command it stands in for", func() {
+			root := &cobra.Command{Use: "dfctl-go", SilenceUsage: true, SilenceErrors: true}
+			root.AddCommand(notCompiledInCmd(featureTUI, "tui", "picks versions"))
+			root.SetArgs([]string{"tui", "--remote", "-x", "1.22"})
+			Ω(errors.Is(root.Execute(), errNotCompiledIn)).Should(BeTrue())
+		})
+
+		g.It("lists the compiled features", func() {
+			compiledFeatures = map[string]bool{featureDaemon: true, featureTUI: false, featureOCI: true}
+			Ω(enabledFeatures()).Should(Equal([]string{featureDaemon, featureOCI}))
+		})
+	})
+}
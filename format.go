@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// versionEntryFields are the fields of versionEntry available to --format templates
+const versionEntryFields = ".Version, .Path, .Current, .Size, .External and .Remote"
+
+// templateFuncs are the functions available to --format templates besides the builtins of text/template
+var templateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"bytes": formatBytes,
+	"upper": func(v interface{}) string {
+		return strings.ToUpper(fmt.Sprint(v))
+	},
+	"lower": func(v interface{}) string {
+		return strings.ToLower(fmt.Sprint(v))
+	},
+}
+
+// addFormatFlag adds the --format flag to cmd; fields lists the fields available to the template
+func addFormatFlag(cmd *cobra.Command, format *string, fields string) {
+	cmd.Flags().StringVar(format, "format", "", fmt.Sprintf("print each version using a go template like '{{.Version}} {{.Path}}'; "+
+		"the fields are %s, the functions json, bytes, upper and lower", fields))
+}
+
+// parseFormat parses the go template passed with --format
+func parseFormat(format string) (*template.Template, error) {
+	tmpl, err := template.New("format").Funcs(templateFuncs).Option("missingkey=error").Parse(format)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --format template; err=%v", err)
+	}
+	return tmpl, nil
+}
+
+// writeFormatted executes the --format template for every item, each followed by a newline like docker --format
+func (e *executor) writeFormatted(items ...interface{}) error {
+	tmpl, err := parseFormat(e.Format)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if err = tmpl.Execute(e.Streams.Out, item); err != nil {
+			return fmt.Errorf("failed to execute the --format template; err=%v", err)
+		}
+		_, _ = fmt.Fprintln(e.Streams.Out)
+	}
+	return nil
+}
+
+// writeFormattedVersions executes the --format template for every entry; errs are logged as warnings
+func (e *executor) writeFormattedVersions(entries []versionEntry, errs []error) error {
+	for _, err := range errs {
+		log.Warn().Err(err).Send()
+	}
+	items := make([]interface{}, 0, len(entries))
+	for _, entry := range entries {
+		items = append(items, entry)
+	}
+	return e.writeFormatted(items...)
+}
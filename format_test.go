@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestFormat(t *testing.T) {
+	testutils.Run(t, "--format", func(g *goblin.G) {
+		var out *Buffer
+		var sut *executor
+		entries := []versionEntry{
+			{Version: "1.16.8", Path: "/sdk/go/v1.16.8", Current: true, Size: 2048},
+			{Version: "1.17.1", Path: "/sdk/go/v1.17.1", Size: 512},
+		}
+
+		g.BeforeEach(func() {
+			out = &Buffer{&bytes.Buffer{}}
+			sut = defaultExecutor()
+			sut.Streams.Out = out
+		})
+
+		g.It("prints each version with the template", func() {
+			sut.Format = "{{.Version}} {{.Path}}"
+			Ω(sut.writeVersions(entries, nil)).Should(Succeed())
+			Ω(out.String()).Should(Equal("1.16.8 /sdk/go/v1.16.8\n1.17.1 /sdk/go/v1.17.1\n"))
+		})
+
+		g.It("provides template functions", func() {
+			sut.Format = `{{if .Current}}{{upper "current"}} {{end}}{{.Version}} {{bytes .Size}} {{json .Path}}`
+			Ω(sut.writeVersions(entries, nil)).Should(Succeed())
+			Ω(out.String()).Should(Equal("CURRENT 1.16.8 2.0 KiB \"/sdk/go/v1.16.8\"\n1.17.1 512 B \"/sdk/go/v1.17.1\"\n"))
+		})
+
+		g.It("formats the details of info", func() {
+			sut.Format = "{{.Version}} released={{.Released}}"
+			Ω(sut.writeFormatted(&versionInfo{Version: "1.22.1", Released: true})).Should(Succeed())
+			Ω(out.String()).Should(Equal("1.22.1 released=true\n"))
+		})
+
+		g.It("fails for invalid templates", func() {
+			sut.Format = "{{.Version"
+			Ω(sut.writeVersions(entries, nil)).Should(MatchError(ContainSubstring("invalid --format template")))
+		})
+
+		g.It("fails for unknown fields", func() {
+			sut.Format = "{{.Unknown}}"
+			Ω(sut.writeVersions(entries, nil)).Should(MatchError(ContainSubstring("failed to execute the --format template")))
+		})
+	})
+}
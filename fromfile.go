@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/alex-held/dfctl-kit/pkg/system"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/afero"
+)
+
+// archiveNamePattern matches the names of go release archives, e.g. go1.22.1.linux-amd64.tar.gz
+var archiveNamePattern = regexp.MustCompile(`^go(.+)\.([a-z0-9]+)-([a-z0-9]+)\.tar(?:\.gz)?$`)
+
+var errUnknownArchiveVersion = errors.New("cannot determine the go version of the archive")
+
+// parseArchiveName returns the version and platform of a go release archive name
+func parseArchiveName(name string) (Version, system.RuntimeInfo, error) {
+	m := archiveNamePattern.FindStringSubmatch(name)
+	if m == nil {
+		return "", system.RuntimeInfo{}, fmt.Errorf("%w; file=%s; pass the version as argument", errUnknownArchiveVersion, name)
+	}
+	version, err := ParseVersion(m[1])
+	if err != nil {
+		return "", system.RuntimeInfo{}, fmt.Errorf("%w; file=%s; err=%v", errUnknownArchiveVersion, name, err)
+	}
+	return version, system.RuntimeInfo{OS: m[2], Arch: m[3]}, nil
+}
+
+// archiveVersion returns the version in args, or the version of the archive name
+func archiveVersion(name string, args []string) (Version, error) {
+	if len(args) == 1 {
+		return ParseVersion(args[0])
+	}
+	version, ri, err := parseArchiveName(name)
+	if err != nil {
+		return "", err
+	}
+	if host := (system.OSRuntimeInfoGetter{}).Get(); ri != host {
+		log.Warn().Msgf("%s is built for %s-%s, but this machine is %s-%s", name, ri.OS, ri.Arch, host.OS, host.Arch)
+	}
+	return version, nil
+}
+
+// InstallFromFile installs the local archive file as the version in args, or the version of its file name
+func (e *executor) InstallFromFile(file string, args []string) error {
+	file, err := filepath.Abs(file)
+	if err != nil {
+		return err
+	}
+	version, err := archiveVersion(filepath.Base(file), args)
+	if err != nil {
+		return err
+	}
+	data, err := afero.ReadFile(e.Fs, file)
+	if err != nil {
+		return fmt.Errorf("failed to read the archive %s; err=%v", file, err)
+	}
+	e.Summary.addVersion(version)
+	return e.installArchive(version, bytes.NewBuffer(data), "file://"+filepath.ToSlash(file))
+}
+
+// InstallFromURL installs the archive at url as the version in args, or the version of its file name;
+// the archive is verified against expected unless it is empty
+func (e *executor) InstallFromURL(ctx context.Context, url, expected string, args []string) error {
+	version, err := archiveVersion(path.Base(strings.SplitN(url, "?", 2)[0]), args)
+	if err != nil {
+		return err
+	}
+	e.Summary.addVersion(version)
+	archive := &bytes.Buffer{}
+	h := sha256.New()
+	if err = e.downloadWithProgress(ctx, url, io.MultiWriter(archive, h), path.Base(url)); err != nil {
+		return fmt.Errorf("failed downloading go sdk %v from %s; err=%v", version, url, err)
+	}
+	if sum := hex.EncodeToString(h.Sum(nil)); expected != "" && !strings.EqualFold(sum, expected) {
+		return fmt.Errorf("%w; url=%s; expected=%s; actual=%s", errChecksumMismatch, url, expected, sum)
+	}
+	return e.installArchive(version, archive, url)
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/system"
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestParseArchiveName(t *testing.T) {
+	testutils.Run(t, "parseArchiveName", func(g *goblin.G) {
+		g.It("parses the version and platform", func() {
+			version, ri, err := parseArchiveName("go1.22.1.linux-amd64.tar.gz")
+			Ω(err).Should(Succeed())
+			Ω(version).Should(Equal(Version("1.22.1")))
+			Ω(ri).Should(Equal(system.RuntimeInfo{OS: "linux", Arch: "amd64"}))
+		})
+
+		g.It("parses pre-releases", func() {
+			version, _, err := parseArchiveName("go1.23rc1.darwin-arm64.tar.gz")
+			Ω(err).Should(Succeed())
+			Ω(version.GoName()).Should(Equal("1.23rc1"))
+		})
+
+		g.It("fails for unknown names", func() {
+			_, _, err := parseArchiveName("sdk.tar.gz")
+			Ω(errors.Is(err, errUnknownArchiveVersion)).Should(BeTrue())
+		})
+	})
+}
+
+func TestInstallFromFile(t *testing.T) {
+	testutils.Run(t, "InstallFromFile", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		dir := testutils.TempDir(t, "archives")
+		ri := system.OSRuntimeInfoGetter{}.Get()
+		file := filepath.Join(dir, formatGoArchiveArtifactName(ri, "1.22.1"))
+
+		g.BeforeEach(func() {
+			_ = os.MkdirAll(dir, os.ModePerm)
+			_ = os.WriteFile(file, archiveData, 0644)
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+			_ = os.RemoveAll(dir)
+		})
+
+		g.It("installs the version of the file name", func() {
+			sut := defaultExecutor()
+			Ω(sut.InstallFromFile(file, nil)).Should(Succeed())
+			Ω(filepath.Join(InstallPath, "1.22.1", "bin", "go")).Should(BeARegularFile())
+		})
+
+		g.It("records the file as source", func() {
+			sut := defaultExecutor()
+			Ω(sut.InstallFromFile(file, []string{"1.22.2"})).Should(Succeed())
+			m, err := sut.readManifest(filepath.Join(InstallPath, "1.22.2"))
+			Ω(err).Should(Succeed())
+			Ω(m.Source).Should(Equal("file://" + filepath.ToSlash(file)))
+		})
+
+		g.It("fails for missing files", func() {
+			Ω(defaultExecutor().InstallFromFile(filepath.Join(dir, "go1.22.1.linux-amd64.tar.gz.missing"), []string{"1.22.1"})).ShouldNot(Succeed())
+		})
+	})
+}
+
+func TestInstallFromURL(t *testing.T) {
+	testutils.Run(t, "InstallFromURL", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		server := newReleaseServer()
+		url := server.URL + "/dl/" + formatGoArchiveArtifactName(system.OSRuntimeInfoGetter{}.Get(), "1.22.1")
+		sum := sha256.Sum256(archiveData)
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+		})
+
+		g.After(func() {
+			server.Close()
+		})
+
+		g.It("installs the version of the url", func() {
+			sut := defaultExecutor()
+			Ω(sut.InstallFromURL(context.Background(), url, hex.EncodeToString(sum[:]), nil)).Should(Succeed())
+			m, err := sut.readManifest(filepath.Join(InstallPath, "1.22.1"))
+			Ω(err).Should(Succeed())
+			Ω(m.Source).Should(Equal(url))
+		})
+
+		g.It("enforces the checksum", func() {
+			err := defaultExecutor().InstallFromURL(context.Background(), url, "0000", nil)
+			Ω(errors.Is(err, errChecksumMismatch)).Should(BeTrue())
+			Ω(filepath.Join(InstallPath, "1.22.1")).ShouldNot(BeADirectory())
+		})
+	})
+}
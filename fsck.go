@@ -0,0 +1,255 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+var errInconsistentState = errors.New("state is inconsistent")
+var errRepairUnconfirmed = errors.New("destructive repair was not confirmed")
+
+// fsckProblem is an inconsistency between the state of dfctl-go and the file system
+type fsckProblem struct {
+	Description string
+	// Repair resolves the problem
+	Repair func() error
+	// Destructive is set if Repair deletes data like version directories, which needs a confirmation
+	Destructive bool
+}
+
+// fsckOptions configure Fsck
+type fsckOptions struct {
+	// Repair repairs the problems instead of only reporting them
+	Repair bool
+	// Yes confirms destructive repairs without asking
+	Yes bool
+}
+
+// fsckCheck finds inconsistencies of one part of the state
+type fsckCheck struct {
+	Name string
+	Run  func() ([]fsckProblem, error)
+}
+
+func newFsckCmd() *cobra.Command {
+	var check bool
+	var opts fsckOptions
+	cmd := &cobra.Command{
+		Use:   "fsck",
+		Short: "checks and repairs the state of dfctl-go against the file system",
+		Long: "checks the state of dfctl-go, i.e. the current link, external sdks, installs and their manifests, aliases, the history, shims and leftover temporary files, " +
+			"against the file system and reports inconsistencies. With --repair they are repaired and the history is compacted; " +
+			"destructive repairs like removing incomplete version directories are confirmed in a terminal or need --yes",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateArgsForSubcommand("fsck", args, 0); err != nil {
+				return err
+			}
+			return defaultExecutor().Fsck(opts)
+		},
+	}
+	cmd.Flags().BoolVar(&opts.Repair, "repair", false, "repair the inconsistencies instead of only reporting them")
+	cmd.Flags().BoolVarP(&opts.Yes, "yes", "y", false, "repair destructively, e.g. remove incomplete version directories, without asking")
+	cmd.Flags().BoolVar(&check, "check", false, "only report inconsistencies without repairing them")
+	_ = cmd.Flags().MarkDeprecated("check", "fsck only reports inconsistencies unless --repair is passed")
+	return cmd
+}
+
+func (e *executor) fsckChecks() []fsckCheck {
+	return []fsckCheck{
+		{Name: "current link", Run: e.fsckCurrent},
+		{Name: "external sdk", Run: e.fsckExternal},
+		{Name: "installs", Run: e.fsckInstalls},
+		{Name: "manifests", Run: e.fsckManifests},
+		{Name: "aliases", Run: e.fsckAliases},
+		{Name: "history", Run: e.fsckHistory},
+		{Name: "shims", Run: e.fsckShims},
+		{Name: "leftovers", Run: e.fsckLeftovers},
+	}
+}
+
+// repairProblem repairs p; destructive repairs need yes or a confirmation in a terminal
+func (e *executor) repairProblem(p fsckProblem, yes bool) error {
+	if p.Destructive && !yes && !(isTerminal(e.Streams.In) && e.confirm(p.Description+"; repair it?")) {
+		return fmt.Errorf("%w; pass --yes to repair it", errRepairUnconfirmed)
+	}
+	return p.Repair()
+}
+
+// Fsck reports the inconsistencies of the state and repairs them if opts.Repair is set
+func (e *executor) Fsck(opts fsckOptions) error {
+	unresolved := 0
+	for _, check := range e.fsckChecks() {
+		problems, err := check.Run()
+		if err != nil {
+			return fmt.Errorf("failed to check %s; err=%v", check.Name, err)
+		}
+		for _, p := range problems {
+			if !opts.Repair {
+				unresolved++
+				_, _ = fmt.Fprintf(e.Streams.Out, "%s: %s\n", check.Name, p.Description)
+				continue
+			}
+			if err = e.repairProblem(p, opts.Yes); err != nil {
+				unresolved++
+				_, _ = fmt.Fprintf(e.Streams.Out, "%s: %s; repair failed: %v\n", check.Name, p.Description, err)
+				continue
+			}
+			_, _ = fmt.Fprintf(e.Streams.Out, "%s: %s; repaired\n", check.Name, p.Description)
+		}
+	}
+	if unresolved > 0 && !opts.Repair {
+		return fmt.Errorf("%w; problems=%d; run 'dfctl-go fsck --repair'", errInconsistentState, unresolved)
+	}
+	if unresolved > 0 {
+		return fmt.Errorf("%w; problems=%d", errInconsistentState, unresolved)
+	}
+	return nil
+}
+
+func (e *executor) fsckCurrent() ([]fsckProblem, error) {
+	link := filepath.Join(e.InstallPath, "current")
+	if _, err := e.Fs.Stat(link); err == nil || !os.IsNotExist(err) {
+		return nil, nil
+	}
+	osFs, ok := e.Fs.(*afero.OsFs)
+	if !ok {
+		return nil, nil
+	}
+	target, err := osFs.ReadlinkIfPossible(link)
+	if err != nil {
+		return nil, nil
+	}
+	return []fsckProblem{{
+		Description: fmt.Sprintf("current links to the missing directory %s", target),
+		Repair: func() error {
+			return e.Fs.Remove(link)
+		},
+	}}, nil
+}
+
+func (e *executor) fsckExternal() ([]fsckProblem, error) {
+	marker := filepath.Join(e.InstallPath, externalMarker)
+	if exists, _ := afero.Exists(e.Fs, marker); !exists {
+		return nil, nil
+	}
+	if _, err := e.currentExternal(); err != nil {
+		return []fsckProblem{{
+			Description: err.Error(),
+			Repair: func() error {
+				return e.Fs.Remove(marker)
+			},
+		}}, nil
+	}
+	return nil, nil
+}
+
+// goBinary returns the path of the go binary relative to a GOROOT of the operating system goos
+func goBinary(goos string) string {
+	if goos == "windows" {
+		return filepath.Join("bin", "go.exe")
+	}
+	return filepath.Join("bin", "go")
+}
+
+func (e *executor) fsckInstalls() (problems []fsckProblem, err error) {
+	installs, err := e.installations()
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	current, _ := e.current()
+	for _, i := range installs {
+		dir := filepath.Join(e.InstallPath, i.Dir)
+		if exists, _ := afero.Exists(e.Fs, filepath.Join(dir, goBinary(runtime.GOOS))); exists {
+			continue
+		}
+		if i.Version.Compare(current) == 0 {
+			continue
+		}
+		problems = append(problems, fsckProblem{
+			Description: fmt.Sprintf("go %s in %s is incomplete", i.Version, dir),
+			Repair: func() error {
+				return e.Fs.RemoveAll(dir)
+			},
+			Destructive: true,
+		})
+	}
+	return problems, nil
+}
+
+func (e *executor) fsckShims() ([]fsckProblem, error) {
+	fis, err := afero.ReadDir(e.Fs, e.ShimPath)
+	if err != nil {
+		return nil, nil
+	}
+	names, err := e.shimmedBinaries()
+	if err != nil {
+		return nil, err
+	}
+	expected := map[string]bool{}
+	for _, name := range names {
+		expected[name] = true
+	}
+	var stale []string
+	for _, fi := range fis {
+		if !expected[strings.TrimSuffix(fi.Name(), ".cmd")] {
+			stale = append(stale, fi.Name())
+		}
+	}
+	if len(stale) == 0 && len(fis) >= len(names) {
+		return nil, nil
+	}
+	return []fsckProblem{{
+		Description: fmt.Sprintf("shims are out of date; stale=%s; expected=%d; found=%d", strings.Join(stale, ","), len(names), len(fis)),
+		Repair: func() error {
+			_, err := e.Rehash()
+			return err
+		},
+	}}, nil
+}
+
+func (e *executor) fsckLeftovers() (problems []fsckProblem, err error) {
+	fis, err := afero.ReadDir(e.Fs, e.InstallPath)
+	if err != nil {
+		return nil, nil
+	}
+	for _, fi := range fis {
+		if !strings.HasPrefix(fi.Name(), ".doctor-") && !strings.HasSuffix(fi.Name(), ".partial") && fi.Name() != TrashDir {
+			continue
+		}
+		path := filepath.Join(e.InstallPath, fi.Name())
+		if fi.Name() == TrashDir {
+			problems = append(problems, fsckProblem{
+				Description: fmt.Sprintf("%s holds removed versions which were not deleted yet", path),
+				Repair:      e.EmptyTrash,
+			})
+			continue
+		}
+		problems = append(problems, fsckProblem{
+			Description: fmt.Sprintf("%s is a leftover of an interrupted operation", path),
+			Repair: func() error {
+				return e.Fs.RemoveAll(path)
+			},
+		})
+	}
+	partials, _ := afero.Glob(e.Fs, filepath.Join(e.CachePath, "*", "*", "*", "*.partial"))
+	for _, path := range partials {
+		path := path
+		problems = append(problems, fsckProblem{
+			Description: fmt.Sprintf("%s is a leftover of an interrupted download", path),
+			Repair: func() error {
+				return e.Fs.Remove(path)
+			},
+		})
+	}
+	return problems, nil
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+
+	"github.com/alex-held/dfctl-kit/pkg/system"
+	"github.com/spf13/afero"
+)
+
+// historyLimit is the number of entries the history keeps when it is compacted by fsck
+const historyLimit = 10000
+
+// fsckManifests finds complete installs without an install manifest and manifests recording another version than their directory
+func (e *executor) fsckManifests() (problems []fsckProblem, err error) {
+	installs, err := e.installations()
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, i := range installs {
+		i, dir := i, filepath.Join(e.InstallPath, i.Dir)
+		if exists, _ := afero.Exists(e.Fs, filepath.Join(dir, goBinary(runtime.GOOS))); !exists {
+			// incomplete installs are reported by fsckInstalls
+			continue
+		}
+		m, err := e.readManifest(dir)
+		switch {
+		case os.IsNotExist(err):
+			problems = append(problems, fsckProblem{
+				Description: fmt.Sprintf("go %s in %s has no install manifest", i.Version, dir),
+				Repair: func() error {
+					return e.rebuildManifest(i.Version, dir)
+				},
+			})
+		case err != nil:
+			problems = append(problems, fsckProblem{
+				Description: fmt.Sprintf("the install manifest of go %s in %s is unreadable; err=%v", i.Version, dir, err),
+				Repair: func() error {
+					return e.rebuildManifest(i.Version, dir)
+				},
+			})
+		case m.Version.Compare(i.Version) != 0:
+			problems = append(problems, fsckProblem{
+				Description: fmt.Sprintf("the install manifest in %s records go %s instead of go %s", dir, m.Version, i.Version),
+				Repair: func() error {
+					m.Version = i.Version
+					return e.writeManifest(dir, m)
+				},
+			})
+		}
+	}
+	return problems, nil
+}
+
+// rebuildManifest writes the install manifest of the go sdk in dir from its files.
+// The source and checksum of the archive are unknown, so verify only checks the files against the current state.
+func (e *executor) rebuildManifest(version Version, dir string) (err error) {
+	ri := system.OSRuntimeInfoGetter{}.Get()
+	m := installManifest{Version: version, InstalledAt: e.installedAt(dir).UTC(), OS: ri.OS, Arch: ri.Arch, InstalledBy: hostFingerprint()}
+	if m.Files, err = countFiles(e.Fs, dir); err != nil {
+		return err
+	}
+	if m.Checksums, err = criticalFiles(e.Fs, dir); err != nil {
+		return err
+	}
+	return e.writeManifest(dir, m)
+}
+
+// fsckAliases finds aliases of the effective config which are no valid aliases, e.g. after editing a config by hand.
+// Invalid aliases of the user config are removed, the ones of the system and project configs are only reported with their file.
+func (e *executor) fsckAliases() (problems []fsckProblem, err error) {
+	_, origins, err := e.mergedConfig()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := e.config()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(cfg.Aliases))
+	for name := range cfg.Aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		name, layer := name, origins["aliases."+name].Layer
+		err := validateAlias(name, cfg.Aliases[name])
+		if err == nil {
+			continue
+		}
+		p := fsckProblem{Description: fmt.Sprintf("%v; defined in the %s config %s", err, layer.Scope, layer.File)}
+		if layer.Scope == scopeUser {
+			p.Destructive = true
+			p.Repair = func() error {
+				return e.updateAliases(func(aliases map[string]string) error {
+					delete(aliases, name)
+					return nil
+				})
+			}
+		} else {
+			p.Repair = func() error {
+				return fmt.Errorf("only aliases of the user config are repaired; edit %s", layer.File)
+			}
+		}
+		problems = append(problems, p)
+	}
+	return problems, nil
+}
+
+// fsckHistory finds unreadable lines, entries out of order and entries beyond the historyLimit in the HistoryFile,
+// which are compacted away by rewriting it
+func (e *executor) fsckHistory() ([]fsckProblem, error) {
+	data, err := afero.ReadFile(e.Fs, filepath.Join(e.InstallPath, HistoryFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entries []historyEntry
+	unreadable, unordered := 0, false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var entry historyEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			unreadable++
+			continue
+		}
+		if len(entries) > 0 && entry.Time.Before(entries[len(entries)-1].Time) {
+			unordered = true
+		}
+		entries = append(entries, entry)
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	trimmed := 0
+	if len(entries) > historyLimit {
+		trimmed = len(entries) - historyLimit
+	}
+	if unreadable == 0 && !unordered && trimmed == 0 {
+		return nil, nil
+	}
+	return []fsckProblem{{
+		Description: fmt.Sprintf("%s needs compaction; unreadable=%d; unordered=%t; beyond_limit=%d",
+			filepath.Join(e.InstallPath, HistoryFile), unreadable, unordered, trimmed),
+		Repair: func() error {
+			return e.compactHistory(entries)
+		},
+		Destructive: trimmed > 0,
+	}}, nil
+}
+
+// compactHistory rewrites the HistoryFile with the entries in chronological order, keeping the newest historyLimit entries.
+// The file is replaced atomically and appends of parallel installs wait for it; other dfctl-go runs are excluded by the install root lock.
+func (e *executor) compactHistory(entries []historyEntry) error {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Time.Before(entries[j].Time)
+	})
+	if len(entries) > historyLimit {
+		entries = entries[len(entries)-historyLimit:]
+	}
+	buf := &bytes.Buffer{}
+	for _, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		buf.Write(append(data, '\n'))
+	}
+
+	historyMu.Lock()
+	defer historyMu.Unlock()
+	file := filepath.Join(e.InstallPath, HistoryFile)
+	staging := file + stagingSuffix
+	if err := afero.WriteFile(e.Fs, staging, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to compact %s; err=%v", file, err)
+	}
+	if err := e.Fs.Rename(staging, file); err != nil {
+		_ = e.Fs.Remove(staging)
+		return fmt.Errorf("failed to compact %s; err=%v", file, err)
+	}
+	return nil
+}
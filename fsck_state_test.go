@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+)
+
+func TestFsckState(t *testing.T) {
+	testutils.Run(t, "fsck state", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		var sut *executor
+
+		g.BeforeEach(func() {
+			bin := filepath.Join(InstallPath, "1.21.5", "bin")
+			_ = os.MkdirAll(bin, os.ModePerm)
+			_ = os.WriteFile(filepath.Join(bin, "go"), []byte{}, 0755)
+			sut = defaultExecutor()
+			sut.ConfigFile = filepath.Join(InstallPath, "go.yaml")
+			sut.SystemConfigFile = filepath.Join(InstallPath, "system.yaml")
+			sut.Streams.Out = &Buffer{&bytes.Buffer{}}
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+		})
+
+		g.It("rebuilds missing install manifests", func() {
+			problems, err := sut.fsckManifests()
+			Ω(err).Should(Succeed())
+			Ω(problems).Should(HaveLen(1))
+			Ω(problems[0].Description).Should(ContainSubstring("has no install manifest"))
+			Ω(problems[0].Destructive).Should(BeFalse())
+			Ω(problems[0].Repair()).Should(Succeed())
+			m, err := sut.readManifest(filepath.Join(InstallPath, "1.21.5"))
+			Ω(err).Should(Succeed())
+			Ω(m.Version).Should(Equal(Version("1.21.5")))
+			Ω(m.Files).Should(Equal(1))
+			Ω(sut.fsckManifests()).Should(BeEmpty())
+		})
+
+		g.It("corrects manifests recording another version", func() {
+			dir := filepath.Join(InstallPath, "1.21.5")
+			Ω(sut.writeManifest(dir, installManifest{Version: "1.20.1", Source: "https://go.dev/dl/go1.21.5.linux-amd64.tar.gz"})).Should(Succeed())
+			problems, err := sut.fsckManifests()
+			Ω(err).Should(Succeed())
+			Ω(problems).Should(HaveLen(1))
+			Ω(problems[0].Repair()).Should(Succeed())
+			m, _ := sut.readManifest(dir)
+			Ω(m.Version).Should(Equal(Version("1.21.5")))
+			Ω(m.Source).Should(Equal("https://go.dev/dl/go1.21.5.linux-amd64.tar.gz"))
+		})
+
+		g.It("removes invalid aliases after confirmation", func() {
+			_ = os.WriteFile(sut.ConfigFile, []byte("aliases:\n  lts: 1.21.5\n  latest: 1.22\n"), 0644)
+			problems, err := sut.fsckAliases()
+			Ω(err).Should(Succeed())
+			Ω(problems).Should(HaveLen(1))
+			Ω(problems[0].Destructive).Should(BeTrue())
+			Ω(problems[0].Repair()).Should(Succeed())
+			cfg, err := sut.config()
+			Ω(err).Should(Succeed())
+			Ω(cfg.Aliases).Should(Equal(map[string]string{"lts": "1.21.5"}))
+		})
+
+		g.It("reports invalid aliases of other config layers with their file", func() {
+			_ = os.WriteFile(sut.SystemConfigFile, []byte("aliases:\n  stable: 1.22\n"), 0644)
+			_ = os.WriteFile(sut.ConfigFile, []byte("aliases:\n  lts: 1.21.5\n"), 0644)
+			problems, err := sut.fsckAliases()
+			Ω(err).Should(Succeed())
+			Ω(problems).Should(HaveLen(1))
+			Ω(problems[0].Description).Should(ContainSubstring("defined in the system config " + sut.SystemConfigFile))
+			Ω(problems[0].Destructive).Should(BeFalse())
+			Ω(problems[0].Repair()).Should(MatchError(ContainSubstring("edit " + sut.SystemConfigFile)))
+			Ω(mustReadFile(sut.ConfigFile)).ShouldNot(ContainSubstring("stable"))
+			Ω(mustReadFile(sut.SystemConfigFile)).Should(ContainSubstring("stable"))
+		})
+
+		g.It("compacts unreadable and unordered history entries", func() {
+			now := time.Now().UTC().Truncate(time.Second)
+			history := fmt.Sprintf("{\"time\":%q,\"op\":\"use\",\"version\":\"1.21.5\"}\nnot json\n{\"time\":%q,\"op\":\"install\",\"version\":\"1.21.5\"}\n",
+				now.Format(time.RFC3339), now.Add(-time.Hour).Format(time.RFC3339))
+			_ = os.WriteFile(filepath.Join(InstallPath, HistoryFile), []byte(history), 0644)
+			problems, err := sut.fsckHistory()
+			Ω(err).Should(Succeed())
+			Ω(problems).Should(HaveLen(1))
+			Ω(problems[0].Description).Should(ContainSubstring("unreadable=1; unordered=true; beyond_limit=0"))
+			Ω(problems[0].Destructive).Should(BeFalse())
+			Ω(problems[0].Repair()).Should(Succeed())
+
+			entries, err := sut.history()
+			Ω(err).Should(Succeed())
+			Ω(entries).Should(HaveLen(2))
+			Ω(entries[0].Op).Should(Equal(historyInstall))
+			Ω(entries[1].Op).Should(Equal(historyUse))
+			Ω(sut.fsckHistory()).Should(BeEmpty())
+			Ω(filepath.Join(InstallPath, HistoryFile+stagingSuffix)).ShouldNot(BeAnExistingFile())
+		})
+
+		g.It("trims the history to its limit after confirmation", func() {
+			line := fmt.Sprintf("{\"time\":%q,\"op\":\"use\",\"version\":\"1.21.5\"}\n", time.Now().UTC().Format(time.RFC3339))
+			_ = afero.WriteFile(afero.NewOsFs(), filepath.Join(InstallPath, HistoryFile), []byte(strings.Repeat(line, historyLimit+5)), 0644)
+			problems, err := sut.fsckHistory()
+			Ω(err).Should(Succeed())
+			Ω(problems).Should(HaveLen(1))
+			Ω(problems[0].Destructive).Should(BeTrue())
+			Ω(problems[0].Repair()).Should(Succeed())
+			entries, _ := sut.history()
+			Ω(entries).Should(HaveLen(historyLimit))
+		})
+	})
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+)
+
+func TestFsck(t *testing.T) {
+	testutils.Run(t, "Fsck", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		ShimPath = testutils.TempDir(t, "shims")
+		var out *Buffer
+
+		g.BeforeEach(func() {
+			for _, dir := range []string{"1.21.5", "1.22.1"} {
+				bin := filepath.Join(InstallPath, dir, "bin")
+				_ = os.MkdirAll(bin, os.ModePerm)
+				_ = os.WriteFile(filepath.Join(bin, "go"), []byte{}, 0755)
+				_ = defaultExecutor().rebuildManifest(Version(dir), filepath.Join(InstallPath, dir))
+			}
+			symlink(afero.NewOsFs(), filepath.Join(InstallPath, "1.22.1"), filepath.Join(InstallPath, "current"))
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+			_ = os.RemoveAll(ShimPath)
+		})
+
+		newSut := func() *executor {
+			out = &Buffer{&bytes.Buffer{}}
+			sut := defaultExecutor()
+			sut.Streams.Out = out
+			return sut
+		}
+
+		g.It("passes consistent state", func() {
+			Ω(newSut().Fsck(fsckOptions{})).Should(Succeed())
+			Ω(newSut().Fsck(fsckOptions{Repair: true})).Should(Succeed())
+			Ω(out.String()).Should(BeEmpty())
+		})
+
+		g.It("removes dangling current links", func() {
+			_ = os.RemoveAll(filepath.Join(InstallPath, "1.22.1"))
+			Ω(newSut().Fsck(fsckOptions{Repair: true})).Should(Succeed())
+			_, err := os.Lstat(filepath.Join(InstallPath, "current"))
+			Ω(os.IsNotExist(err)).Should(BeTrue())
+		})
+
+		g.It("removes incomplete installs with --yes", func() {
+			_ = os.Remove(filepath.Join(InstallPath, "1.21.5", "bin", "go"))
+			Ω(newSut().Fsck(fsckOptions{Repair: true, Yes: true})).Should(Succeed())
+			Ω(filepath.Join(InstallPath, "1.21.5")).ShouldNot(BeADirectory())
+		})
+
+		g.It("keeps incomplete installs without confirmation", func() {
+			_ = os.Remove(filepath.Join(InstallPath, "1.21.5", "bin", "go"))
+			sut := newSut()
+			sut.Streams.In = io.NopCloser(&bytes.Buffer{})
+			err := sut.Fsck(fsckOptions{Repair: true})
+			Ω(errors.Is(err, errInconsistentState)).Should(BeTrue())
+			Ω(out.String()).Should(ContainSubstring("pass --yes to repair it"))
+			Ω(filepath.Join(InstallPath, "1.21.5")).Should(BeADirectory())
+		})
+
+		g.It("checks the go binary of the platform", func() {
+			Ω(goBinary("windows")).Should(Equal(filepath.Join("bin", "go.exe")))
+			Ω(goBinary("linux")).Should(Equal(filepath.Join("bin", "go")))
+		})
+
+		g.It("rehashes stale shims", func() {
+			_ = os.MkdirAll(ShimPath, os.ModePerm)
+			_ = os.WriteFile(filepath.Join(ShimPath, "godoc"), []byte{}, 0755)
+			Ω(newSut().Fsck(fsckOptions{Repair: true})).Should(Succeed())
+			Ω(filepath.Join(ShimPath, "godoc")).ShouldNot(BeAnExistingFile())
+			Ω(filepath.Join(ShimPath, "go")).Should(BeARegularFile())
+		})
+
+		g.It("removes leftovers", func() {
+			_ = os.WriteFile(filepath.Join(InstallPath, "go1.22.1.linux-amd64.tar.gz.partial"), []byte{}, 0644)
+			Ω(newSut().Fsck(fsckOptions{Repair: true})).Should(Succeed())
+			Ω(filepath.Join(InstallPath, "go1.22.1.linux-amd64.tar.gz.partial")).ShouldNot(BeAnExistingFile())
+		})
+
+		g.It("only reports without repair", func() {
+			_ = os.Remove(filepath.Join(InstallPath, "1.21.5", "bin", "go"))
+			err := newSut().Fsck(fsckOptions{})
+			Ω(errors.Is(err, errInconsistentState)).Should(BeTrue())
+			Ω(out.String()).Should(ContainSubstring("installs: go 1.21.5"))
+			Ω(filepath.Join(InstallPath, "1.21.5")).Should(BeADirectory())
+		})
+	})
+}
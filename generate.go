@@ -0,0 +1,15 @@
+package main
+
+import "github.com/spf13/cobra"
+
+func newGenerateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "generates files for working with installed go sdks",
+		RunE: func(c *cobra.Command, args []string) error {
+			return c.Help()
+		},
+	}
+	cmd.AddCommand(newGenerateContainerfileCmd())
+	return cmd
+}
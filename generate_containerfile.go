@@ -0,0 +1,104 @@
+//go:build !nooci
+// +build !nooci
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/alex-held/dfctl-kit/pkg/system"
+	"github.com/spf13/cobra"
+)
+
+// containerBases maps the short names accepted by --base to their images
+var containerBases = map[string]string{
+	"distroless": "gcr.io/distroless/static-debian12",
+	"debian":     "debian:bookworm-slim",
+	"alpine":     "alpine:3",
+	"scratch":    "scratch",
+}
+
+// cgoBases have a C toolchain available, every other base builds with CGO_ENABLED=0
+var cgoBases = map[string]bool{
+	"debian": true,
+}
+
+var containerfileTemplate = template.Must(template.New("containerfile").Parse(`# syntax=docker/dockerfile:1.4
+# Generated by dfctl-go for go {{ .Version }}.
+# The go sdk is copied from the local installation instead of being pulled from a registry:
+#
+#   docker build --build-context gosdk={{ .Path }} -f Containerfile .
+#
+FROM {{ .Image }}
+COPY --from=gosdk / /usr/local/go
+ENV GOROOT=/usr/local/go \
+    GOPATH=/go \
+    PATH=/go/bin:/usr/local/go/bin:$PATH{{ if not .CGO }} \
+    CGO_ENABLED=0{{ end }}
+WORKDIR /src
+`))
+
+type containerfile struct {
+	Version Version
+	Path    string
+	Image   string
+	CGO     bool
+}
+
+func newGenerateContainerfileCmd() *cobra.Command {
+	var base, file string
+
+	cmd := &cobra.Command{
+		Use:   "containerfile",
+		Short: "generates a Containerfile using an installed go sdk instead of a golang image",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateArgsForSubcommand("generate containerfile", args, 1); err != nil {
+				return err
+			}
+			e := defaultExecutor()
+			version, err := e.resolveVersion(c.Context(), args[0], installedScope, false)
+			if err != nil {
+				return err
+			}
+			if file == "" {
+				return e.GenerateContainerfile(e.Streams.Out, version, base)
+			}
+			f, err := e.Fs.Create(file)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			return e.GenerateContainerfile(f, version, base)
+		},
+	}
+	cmd.Flags().StringVar(&base, "base", "distroless", "base image; one of distroless, debian, alpine, scratch or an image reference")
+	cmd.Flags().StringVarP(&file, "file", "f", "", "write the Containerfile to the given path instead of stdout")
+
+	return cmd
+}
+
+// GenerateContainerfile writes a Containerfile to w, which copies the installed go sdk into the base image
+func (e *executor) GenerateContainerfile(w io.Writer, version Version, base string) error {
+	if ri := system.Get(); !ri.IsLinux() {
+		return fmt.Errorf("the installed go sdks target %s, but container images require a linux go sdk", ri.OS)
+	}
+
+	versionPath, err := e.versionPath(version)
+	if err != nil {
+		return err
+	}
+
+	image, ok := containerBases[strings.ToLower(base)]
+	if !ok {
+		image = base
+	}
+	return containerfileTemplate.Execute(w, containerfile{
+		Version: version,
+		Path:    versionPath,
+		Image:   image,
+		CGO:     cgoBases[strings.ToLower(base)],
+	})
+}
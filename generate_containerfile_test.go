@@ -0,0 +1,63 @@
+//go:build !nooci
+// +build !nooci
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestGenerateContainerfile(t *testing.T) {
+	testutils.Run(t, "GenerateContainerfile", func(g *goblin.G) {
+		if runtime.GOOS != "linux" {
+			return
+		}
+		InstallPath = installPath(t)
+
+		g.BeforeEach(func() {
+			createVersionDirs()
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+		})
+
+		g.Describe("with installed version", func() {
+			const version = Version("v1.17.1")
+
+			g.It("copies the sdk from the gosdk build context", func() {
+				out := &bytes.Buffer{}
+				Ω(defaultExecutor().GenerateContainerfile(out, version, "distroless")).Should(Succeed())
+				Ω(out.String()).Should(ContainSubstring("--build-context gosdk=" + filepath.Join(InstallPath, version.String())))
+				Ω(out.String()).Should(ContainSubstring("COPY --from=gosdk / /usr/local/go"))
+			})
+
+			g.It("uses the image of the base", func() {
+				out := &bytes.Buffer{}
+				Ω(defaultExecutor().GenerateContainerfile(out, version, "distroless")).Should(Succeed())
+				Ω(out.String()).Should(ContainSubstring("FROM gcr.io/distroless/static-debian12"))
+				Ω(out.String()).Should(ContainSubstring("CGO_ENABLED=0"))
+			})
+
+			g.It("uses custom images", func() {
+				out := &bytes.Buffer{}
+				Ω(defaultExecutor().GenerateContainerfile(out, version, "registry.corp/base:1")).Should(Succeed())
+				Ω(out.String()).Should(ContainSubstring("FROM registry.corp/base:1"))
+			})
+		})
+
+		g.Describe("with not installed version", func() {
+			g.It("return ErrVersionNotInstalled", func() {
+				Ω(defaultExecutor().GenerateContainerfile(&bytes.Buffer{}, "v99.99.99", "debian")).Should(Equal(ErrVersionNotInstalled))
+			})
+		})
+	})
+}
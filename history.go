@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+// HistoryFile is the audit log of installs, uninstalls and switches, relative to the install root
+const HistoryFile = "history.jsonl"
+
+const (
+	historyInstall   = "install"
+	historyUninstall = "uninstall"
+	historyUse       = "use"
+)
+
+// historyMu serializes appends of parallel installs
+var historyMu sync.Mutex
+
+// historyEntry is a line of the HistoryFile
+type historyEntry struct {
+	Time    time.Time `json:"time"`
+	Op      string    `json:"op"`
+	Version Version   `json:"version"`
+	// Previous is the version which was current before a use
+	Previous Version `json:"previous,omitempty"`
+	User     string  `json:"user"`
+	// Source is the url the version was installed from
+	Source string `json:"source,omitempty"`
+}
+
+func newHistoryCmd() *cobra.Command {
+	var limit int
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "prints the installs, uninstalls and switches of go versions",
+		Long:  "prints the audit log of installs, uninstalls and switches of go versions with their time, user and source, oldest first",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateArgsForSubcommand("history", args, 0); err != nil {
+				return err
+			}
+			return defaultExecutor().History(limit, outputFormat == outputJSON)
+		},
+	}
+	addJSONAlias(cmd)
+	cmd.Flags().IntVarP(&limit, "limit", "n", 0, "print only the newest entries; 0 prints all")
+	return cmd
+}
+
+// historyUser returns the name of the user running dfctl-go
+func historyUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
+
+// record appends the operation to the HistoryFile; failures never fail the operation
+func (e *executor) record(entry historyEntry) {
+	entry.Time = time.Now().UTC()
+	entry.User = historyUser()
+	if v, err := ParseVersion(entry.Version.String()); err == nil {
+		entry.Version = v
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	historyMu.Lock()
+	defer historyMu.Unlock()
+	f, err := e.Fs.OpenFile(filepath.Join(e.InstallPath, HistoryFile), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		log.Debug().Err(err).Msg("failed to record the history")
+		return
+	}
+	defer f.Close()
+	if _, err = f.Write(append(data, '\n')); err != nil {
+		log.Debug().Err(err).Msg("failed to record the history")
+	}
+}
+
+// history returns the entries of the HistoryFile, oldest first; unreadable lines are skipped
+func (e *executor) history() ([]historyEntry, error) {
+	data, err := afero.ReadFile(e.Fs, filepath.Join(e.InstallPath, HistoryFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entries []historyEntry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var entry historyEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			log.Debug().Err(err).Msg("skipping unreadable history entry")
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// History prints the newest limit entries of the history, or all entries if limit is 0
+func (e *executor) History(limit int, asJSON bool) error {
+	entries, err := e.history()
+	if err != nil {
+		return err
+	}
+	if limit > 0 && len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+	if asJSON {
+		if entries == nil {
+			entries = []historyEntry{}
+		}
+		enc := json.NewEncoder(e.Streams.Out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+
+	w := tabwriter.NewWriter(e.Streams.Out, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "TIME\tOP\tVERSION\tUSER\tDETAILS")
+	for _, entry := range entries {
+		details := entry.Source
+		if entry.Previous != "" {
+			details = fmt.Sprintf("from %s", entry.Previous)
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", entry.Time.Local().Format(time.RFC3339), entry.Op, entry.Version, entry.User, details)
+	}
+	return w.Flush()
+}
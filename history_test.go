@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestHistory(t *testing.T) {
+	testutils.Run(t, "History", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		var out *Buffer
+
+		g.BeforeEach(func() {
+			createVersionDirs()
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+		})
+
+		newSut := func() *executor {
+			out = &Buffer{&bytes.Buffer{}}
+			sut := defaultExecutor()
+			sut.Streams.Out = out
+			return sut
+		}
+
+		g.It("records installs, switches and uninstalls", func() {
+			sut := newSut()
+			Ω(sut.installArchive("1.22.1", bytes.NewBuffer(archiveData), "https://go.dev/dl/go1.22.1.linux-amd64.tar.gz")).Should(Succeed())
+			Ω(sut.Use("v1.16.8")).Should(Succeed())
+			Ω(sut.Use("1.22.1")).Should(Succeed())
+			Ω(sut.Uninstall("v1.16.8")).Should(Succeed())
+
+			entries, err := sut.history()
+			Ω(err).Should(Succeed())
+			Ω(entries).Should(HaveLen(4))
+			Ω(entries[0].Op).Should(Equal(historyInstall))
+			Ω(entries[0].Source).Should(Equal("https://go.dev/dl/go1.22.1.linux-amd64.tar.gz"))
+			Ω(entries[0].User).Should(Equal(historyUser()))
+			Ω(entries[0].Time).ShouldNot(BeZero())
+			Ω(entries[2].Op).Should(Equal(historyUse))
+			Ω(entries[2].Version).Should(Equal(Version("1.22.1")))
+			Ω(entries[2].Previous).Should(Equal(Version("1.16.8")))
+			Ω(entries[3].Op).Should(Equal(historyUninstall))
+		})
+
+		g.It("does not record failed operations", func() {
+			sut := newSut()
+			Ω(sut.Use("v99.99.99")).ShouldNot(Succeed())
+			Ω(filepath.Join(InstallPath, HistoryFile)).ShouldNot(BeAnExistingFile())
+		})
+
+		g.It("prints the newest entries as json", func() {
+			sut := newSut()
+			Ω(sut.Use("v1.16.8")).Should(Succeed())
+			Ω(sut.Use("v1.17.1")).Should(Succeed())
+			Ω(sut.History(1, true)).Should(Succeed())
+			var entries []historyEntry
+			Ω(json.Unmarshal(out.Bytes(), &entries)).Should(Succeed())
+			Ω(entries).Should(HaveLen(1))
+			Ω(entries[0].Version).Should(Equal(Version("1.17.1")))
+		})
+
+		g.It("prints a table", func() {
+			sut := newSut()
+			Ω(sut.Use("v1.16.8")).Should(Succeed())
+			Ω(sut.Use("v1.17.1")).Should(Succeed())
+			Ω(sut.History(0, false)).Should(Succeed())
+			Ω(out.String()).Should(MatchRegexp(`TIME\s+OP\s+VERSION\s+USER\s+DETAILS\n`))
+			Ω(out.String()).Should(MatchRegexp(`use\s+1\.17\.1\s+\S+\s+from 1\.16\.8\n`))
+		})
+
+		g.It("prints an empty json list without history", func() {
+			Ω(newSut().History(0, true)).Should(Succeed())
+			Ω(out.String()).Should(Equal("[]\n"))
+		})
+	})
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var errUnsupportedShell = errors.New("unsupported shell")
+
+const bashHook = `_dfctl_go_hook() {
+  local previous_exit_status=$?
+  eval "$(command dfctl-go hook bash --export)"
+  return $previous_exit_status
+}
+if [[ ";${PROMPT_COMMAND[*]:-};" != *";_dfctl_go_hook;"* ]]; then
+  PROMPT_COMMAND="_dfctl_go_hook${PROMPT_COMMAND:+;$PROMPT_COMMAND}"
+fi
+`
+
+const zshHook = `_dfctl_go_hook() {
+  eval "$(command dfctl-go hook zsh --export)"
+}
+typeset -ag chpwd_functions
+if (( ! ${chpwd_functions[(I)_dfctl_go_hook]} )); then
+  chpwd_functions=(_dfctl_go_hook $chpwd_functions)
+fi
+_dfctl_go_hook
+`
+
+const fishHook = `function _dfctl_go_hook --on-variable PWD
+    command dfctl-go hook fish --export | source
+end
+_dfctl_go_hook
+`
+
+var hooks = map[string]string{
+	"bash": bashHook,
+	"zsh":  zshHook,
+	"fish": fishHook,
+}
+
+func newHookCmd() *cobra.Command {
+	var export bool
+	cmd := &cobra.Command{
+		Use:       "hook bash|zsh|fish",
+		Short:     "prints a shell hook switching the go version when changing directories",
+		Long:      "prints a shell hook which re-resolves the go version in effect and updates GOROOT and PATH whenever the directory changes; add eval \"$(dfctl-go hook bash)\" to your shell rc",
+		ValidArgs: []string{"bash", "zsh", "fish"},
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateArgsForSubcommand("hook", args, 1); err != nil {
+				return err
+			}
+			e := defaultExecutor()
+			if export {
+				wd, err := os.Getwd()
+				if err != nil {
+					return err
+				}
+				return e.Exports(args[0], wd)
+			}
+			return e.Hook(args[0])
+		},
+	}
+	cmd.Flags().BoolVar(&export, "export", false, "print the exports of the working directory, called by the hook")
+	_ = cmd.Flags().MarkHidden("export")
+	return cmd
+}
+
+// Hook prints the hook of shell
+func (e *executor) Hook(shell string) error {
+	hook, ok := hooks[shell]
+	if !ok {
+		return fmt.Errorf("%w; shell=%s", errUnsupportedShell, shell)
+	}
+	_, _ = fmt.Fprint(e.Streams.Out, hook)
+	return nil
+}
+
+// Exports prints the shell statements exporting GOROOT and PATH of the version in effect for dir.
+// Nothing is printed if no version is in effect, so the hook keeps the environment as is.
+func (e *executor) Exports(shell, dir string) error {
+	if _, ok := hooks[shell]; !ok {
+		return fmt.Errorf("%w; shell=%s", errUnsupportedShell, shell)
+	}
+	goroot, path, err := e.goEnv(dir)
+	if err != nil {
+		log.Debug().Err(err).Msg("no go version in effect")
+		return nil
+	}
+	_, _ = fmt.Fprint(e.Streams.Out, exportStatement(shell, "GOROOT", goroot))
+	_, _ = fmt.Fprint(e.Streams.Out, exportStatement(shell, "PATH", path))
+	return nil
+}
+
+// goEnv returns GOROOT and PATH of the installed version in effect for dir
+func (e *executor) goEnv(dir string) (goroot, path string, err error) {
+	version, _, err := e.resolved(dir)
+	if err != nil {
+		return "", "", err
+	}
+	goroot, err = e.goroot(version)
+	if err != nil {
+		return "", "", fmt.Errorf("%w; version=%s", err, version)
+	}
+	return goroot, e.managedPath(os.Getenv("PATH"), filepath.Join(goroot, "bin")), nil
+}
+
+// managedPath returns path with bin prepended and the bin directories of other installed versions removed
+func (e *executor) managedPath(path, bin string) string {
+	entries := []string{bin}
+	for _, entry := range filepath.SplitList(path) {
+		if entry == bin || (strings.HasPrefix(entry, e.InstallPath+string(filepath.Separator)) && filepath.Base(entry) == "bin") {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return strings.Join(entries, string(filepath.ListSeparator))
+}
+
+// exportStatement returns the statement exporting the variable in shell
+func exportStatement(shell, name, value string) string {
+	switch shell {
+	case "powershell":
+		return fmt.Sprintf("$env:%s = %s\n", name, powershellString(value))
+	case "fish":
+		if name == "PATH" {
+			var quoted []string
+			for _, entry := range filepath.SplitList(value) {
+				quoted = append(quoted, shellQuote(entry))
+			}
+			return fmt.Sprintf("set -gx %s %s;\n", name, strings.Join(quoted, " "))
+		}
+		return fmt.Sprintf("set -gx %s %s;\n", name, shellQuote(value))
+	default:
+		return fmt.Sprintf("export %s=%s;\n", name, shellQuote(value))
+	}
+}
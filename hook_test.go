@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+)
+
+func TestHook(t *testing.T) {
+	testutils.Run(t, "Hook", func(g *goblin.G) {
+		var out *Buffer
+
+		newSut := func() *executor {
+			out = &Buffer{&bytes.Buffer{}}
+			sut := defaultExecutor()
+			sut.Streams.Out = out
+			return sut
+		}
+
+		g.It("hooks into PROMPT_COMMAND for bash", func() {
+			Ω(newSut().Hook("bash")).Should(Succeed())
+			Ω(out.String()).Should(ContainSubstring("PROMPT_COMMAND="))
+		})
+
+		g.It("hooks into chpwd for zsh", func() {
+			Ω(newSut().Hook("zsh")).Should(Succeed())
+			Ω(out.String()).Should(ContainSubstring("chpwd_functions"))
+		})
+
+		g.It("hooks into PWD changes for fish", func() {
+			Ω(newSut().Hook("fish")).Should(Succeed())
+			Ω(out.String()).Should(ContainSubstring("--on-variable PWD"))
+		})
+
+		g.It("rejects unsupported shells", func() {
+			Ω(errors.Is(newSut().Hook("tcsh"), errUnsupportedShell)).Should(BeTrue())
+		})
+	})
+}
+
+func TestExports(t *testing.T) {
+	testutils.Run(t, "Exports", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		project := filepath.Join(testutils.TempDir(t), "project")
+		var out *Buffer
+
+		g.BeforeEach(func() {
+			createVersionDirs()
+			_ = os.MkdirAll(project, os.ModePerm)
+			_ = os.WriteFile(filepath.Join(project, ProjectVersionFile), []byte("1.17.1\n"), os.ModePerm)
+			symlink(afero.NewOsFs(), filepath.Join(InstallPath, "v1.16.8"), filepath.Join(InstallPath, "current"))
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+			_ = os.RemoveAll(project)
+		})
+
+		newSut := func() *executor {
+			out = &Buffer{&bytes.Buffer{}}
+			sut := defaultExecutor()
+			sut.Streams.Out = out
+			return sut
+		}
+
+		g.It("exports GOROOT of the resolved version", func() {
+			Ω(newSut().Exports("bash", project)).Should(Succeed())
+			Ω(out.String()).Should(ContainSubstring("export GOROOT=" + filepath.Join(InstallPath, "v1.17.1") + ";\n"))
+		})
+
+		g.It("uses set -gx for fish", func() {
+			Ω(newSut().Exports("fish", project)).Should(Succeed())
+			Ω(out.String()).Should(ContainSubstring("set -gx GOROOT " + filepath.Join(InstallPath, "v1.17.1") + ";\n"))
+		})
+
+		g.It("replaces the bin directories of other versions in PATH", func() {
+			sut := newSut()
+			path := sut.managedPath(filepath.Join(InstallPath, "v1.16.8", "bin")+":/usr/bin", filepath.Join(InstallPath, "v1.17.1", "bin"))
+			Ω(path).Should(Equal(filepath.Join(InstallPath, "v1.17.1", "bin") + ":/usr/bin"))
+		})
+	})
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// hostFlag is the flag of install and use selecting the machine they are executed on
+const hostFlag = "host"
+
+// remoteBinary is the dfctl-go executable invoked on remote hosts
+const remoteBinary = "dfctl-go"
+
+var errUnsupportedHost = errors.New("unsupported host; expected ssh://[user@]host[:port]")
+
+// sshHost is a remote machine reachable over ssh
+type sshHost struct {
+	User string
+	Host string
+	Port string
+}
+
+// parseHost parses a host of the form ssh://[user@]host[:port]
+func parseHost(s string) (sshHost, error) {
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme != "ssh" || u.Hostname() == "" || (u.Path != "" && u.Path != "/") {
+		return sshHost{}, fmt.Errorf("%w; host=%s", errUnsupportedHost, s)
+	}
+	return sshHost{User: u.User.Username(), Host: u.Hostname(), Port: u.Port()}, nil
+}
+
+func (h sshHost) String() string {
+	if h.User != "" {
+		return h.User + "@" + h.Host
+	}
+	return h.Host
+}
+
+// command returns the ssh command line running dfctl-go with args on the host.
+// The remote shell joins the arguments, so each of them is quoted.
+func (h sshHost) command(args []string) []string {
+	argv := []string{"ssh"}
+	if h.Port != "" {
+		argv = append(argv, "-p", h.Port)
+	}
+	remote := []string{remoteBinary}
+	for _, arg := range args {
+		remote = append(remote, shellQuote(arg))
+	}
+	return append(argv, h.String(), "--", strings.Join(remote, " "))
+}
+
+// shellQuote quotes s for posix shells
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_.,:/=@^~+", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// addHostFlag adds the --host flag to cmd, which has to forward its invocation with onHost if it is set
+func addHostFlag(cmd *cobra.Command, host *string) {
+	cmd.Flags().StringVar(host, hostFlag, "", "execute the command on a remote machine with dfctl-go installed, e.g. ssh://build02")
+}
+
+// onRemoteHost reports whether c is forwarded to a remote host with --host
+func onRemoteHost(c *cobra.Command) bool {
+	f := c.Flags().Lookup(hostFlag)
+	return f != nil && f.Value.String() != ""
+}
+
+// remoteArgs returns the arguments reproducing the invocation of c on a remote host, without the host flag
+func remoteArgs(c *cobra.Command, args []string) []string {
+	remote := strings.Fields(c.CommandPath())[1:]
+	c.Flags().Visit(func(f *pflag.Flag) {
+		if f.Name == hostFlag {
+			return
+		}
+		remote = append(remote, fmt.Sprintf("--%s=%s", f.Name, f.Value.String()))
+	})
+	return append(remote, args...)
+}
+
+// onHost executes the invocation of c with dfctl-go on host, which has to be installed there
+func (e *executor) onHost(ctx context.Context, host string, c *cobra.Command, args []string) error {
+	h, err := parseHost(host)
+	if err != nil {
+		return err
+	}
+	argv := h.command(remoteArgs(c, args))
+	log.Debug().Msgf("executing on %s: %s", h, strings.Join(argv, " "))
+
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Stdin = e.Streams.In
+	cmd.Stdout = e.Streams.Out
+	cmd.Stderr = e.Streams.Err
+	if err = cmd.Run(); err != nil {
+		return fmt.Errorf("failed to execute on %s; err=%v", h, err)
+	}
+	return nil
+}
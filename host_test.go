@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestParseHost(t *testing.T) {
+	testutils.Run(t, "parseHost", func(g *goblin.G) {
+
+		g.It("parses user, host and port", func() {
+			Ω(parseHost("ssh://admin@build02:2222")).Should(Equal(sshHost{User: "admin", Host: "build02", Port: "2222"}))
+		})
+
+		g.It("parses bare hosts", func() {
+			Ω(parseHost("ssh://build02")).Should(Equal(sshHost{Host: "build02"}))
+		})
+
+		g.It("rejects other schemes", func() {
+			_, err := parseHost("https://build02")
+			Ω(errors.Is(err, errUnsupportedHost)).Should(BeTrue())
+		})
+	})
+}
+
+func TestSSHHostCommand(t *testing.T) {
+	testutils.Run(t, "sshHost.command", func(g *goblin.G) {
+
+		g.It("runs dfctl-go on the host", func() {
+			h := sshHost{User: "admin", Host: "build02", Port: "2222"}
+			Ω(h.command([]string{"install", "1.22.2"})).Should(Equal([]string{"ssh", "-p", "2222", "admin@build02", "--", "dfctl-go install 1.22.2"}))
+		})
+
+		g.It("quotes arguments for the remote shell", func() {
+			h := sshHost{Host: "build02"}
+			Ω(h.command([]string{"install", ">=1.21 <1.22", "it's"})).Should(Equal([]string{"ssh", "build02", "--", `dfctl-go install '>=1.21 <1.22' 'it'\''s'`}))
+		})
+	})
+}
+
+func TestRemoteArgs(t *testing.T) {
+	testutils.Run(t, "remoteArgs", func(g *goblin.G) {
+
+		g.It("forwards the subcommand, changed flags and args without the host flag", func() {
+			cmd := NewCmd()
+			installCmd, _, err := cmd.Find([]string{"install"})
+			Ω(err).Should(Succeed())
+			Ω(installCmd.ParseFlags([]string{"--host", "ssh://build02", "--include-unstable", "latest"})).Should(Succeed())
+			Ω(remoteArgs(installCmd, installCmd.Flags().Args())).Should(Equal([]string{"install", "--include-unstable=true", "latest"}))
+		})
+	})
+}
+
+func TestHostFlag(t *testing.T) {
+	testutils.Run(t, "--host", func(g *goblin.G) {
+		InstallPath = installPath(t)
+
+		g.BeforeEach(func() {
+			createVersionDirs()
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+		})
+
+		g.It("is rejected by commands which are not forwarded to the host", func() {
+			cmd := NewCmd()
+			cmd.SetArgs([]string{"--host", "ssh://nowhere.invalid", "uninstall", "1.17.1"})
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetErr(&bytes.Buffer{})
+			Ω(cmd.Execute()).Should(MatchError(ContainSubstring("unknown flag: --host")))
+			Ω(filepath.Join(InstallPath, "v1.17.1")).Should(BeADirectory())
+			Ω(filepath.Join(InstallPath, LockFile)).ShouldNot(BeAnExistingFile())
+		})
+
+		g.It("is a flag of install and use", func() {
+			cmd := NewCmd()
+			for _, name := range []string{"install", "use"} {
+				sub, _, err := cmd.Find([]string{name})
+				Ω(err).Should(Succeed())
+				Ω(sub.Flags().Lookup(hostFlag)).ShouldNot(BeNil())
+			}
+			Ω(cmd.PersistentFlags().Lookup(hostFlag)).Should(BeNil())
+		})
+	})
+}
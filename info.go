@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/alex-held/dfctl-kit/pkg/system"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// versionInfo describes an installed or released version
+type versionInfo struct {
+	Version   Version `json:"version"`
+	Installed bool    `json:"installed"`
+	Current   bool    `json:"current"`
+	Path      string  `json:"path,omitempty"`
+	// Size is the size of the install directory in bytes
+	Size        int64      `json:"size,omitempty"`
+	InstalledAt *time.Time `json:"installed_at,omitempty"`
+	// Source is the url or file the version was installed from
+	Source string `json:"source,omitempty"`
+	// SHA256 is the checksum of the installed archive, or of the archive for this platform
+	SHA256 string `json:"sha256,omitempty"`
+	// Released reports whether the version is listed in the release feed
+	Released bool `json:"released"`
+	Stable   bool `json:"stable"`
+}
+
+func newInfoCmd() *cobra.Command {
+	var format string
+	cmd := &cobra.Command{
+		Use:   "info <version>",
+		Short: "shows details about a version",
+		Long:  "shows whether a version is installed, where, its size on disk, install date and source, its archive checksum and whether it is a stable release of the release feed",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateArgsForSubcommand("info", args, 1); err != nil {
+				return err
+			}
+			v, err := ParseVersion(args[0])
+			if err != nil {
+				return err
+			}
+			e := defaultExecutor()
+			e.Format = format
+			return e.Info(c.Context(), v, outputFormat == outputJSON)
+		},
+	}
+	addJSONAlias(cmd)
+	addFormatFlag(cmd, &format, ".Version, .Installed, .Current, .Path, .Size, .InstalledAt, .Source, .SHA256, .Released and .Stable")
+	return cmd
+}
+
+// info collects the details of the version from the install directory and the release feed.
+// The release feed is optional for installed versions.
+func (e *executor) info(ctx context.Context, version Version) (*versionInfo, error) {
+	info := &versionInfo{Version: version, Stable: version.IsStable()}
+	if current, err := e.current(); err == nil {
+		info.Current = current.Compare(version) == 0
+	}
+
+	if dir, err := e.versionPath(version); err == nil {
+		info.Installed, info.Path = true, dir
+		if info.Size, err = dirSize(e.Fs, dir); err != nil {
+			return nil, err
+		}
+		if m, err := e.readManifest(dir); err == nil {
+			info.Source, info.SHA256 = m.Source, m.SHA256
+			if !m.InstalledAt.IsZero() {
+				info.InstalledAt = &m.InstalledAt
+			}
+		}
+		if fi, err := e.Fs.Stat(dir); err == nil && info.InstalledAt == nil {
+			t := fi.ModTime()
+			info.InstalledAt = &t
+		}
+	} else if ext, ok := e.external(version); ok {
+		info.Installed, info.Path = true, ext.Root
+		if info.Size, err = dirSize(e.Fs, ext.Root); err != nil {
+			return nil, err
+		}
+	}
+
+	releases, err := e.releases(ctx)
+	if err != nil {
+		if !info.Installed {
+			return nil, err
+		}
+		log.Warn().Err(err).Msgf("release feed of %s is unavailable", e.URL)
+		return info, nil
+	}
+	filename := formatGoArchiveArtifactName(system.OSRuntimeInfoGetter{}.Get(), version.GoName())
+	for _, r := range releases {
+		if v, err := ParseVersion(r.Version); err != nil || v.Compare(version) != 0 {
+			continue
+		}
+		info.Released, info.Stable = true, r.Stable
+		for _, f := range r.Files {
+			if f.Filename == filename && info.SHA256 == "" {
+				info.SHA256 = f.SHA256
+			}
+		}
+	}
+	if !info.Installed && !info.Released {
+		return nil, fmt.Errorf("%w; version=%s", errNoMatchingRelease, version)
+	}
+	return info, nil
+}
+
+// Info prints the details of the version
+func (e *executor) Info(ctx context.Context, version Version, asJSON bool) error {
+	info, err := e.info(ctx, version)
+	if err != nil {
+		return err
+	}
+	if e.Format != "" {
+		return e.writeFormatted(info)
+	}
+	if asJSON {
+		enc := json.NewEncoder(e.Streams.Out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(info)
+	}
+
+	w := tabwriter.NewWriter(e.Streams.Out, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintf(w, "version:\t%s\n", info.Version)
+	_, _ = fmt.Fprintf(w, "installed:\t%t\n", info.Installed)
+	if info.Installed {
+		_, _ = fmt.Fprintf(w, "current:\t%t\n", info.Current)
+		_, _ = fmt.Fprintf(w, "path:\t%s\n", info.Path)
+		_, _ = fmt.Fprintf(w, "size:\t%s\n", formatBytes(info.Size))
+	}
+	if info.InstalledAt != nil {
+		_, _ = fmt.Fprintf(w, "installed at:\t%s\n", info.InstalledAt.Format(time.RFC3339))
+	}
+	if info.Source != "" {
+		_, _ = fmt.Fprintf(w, "source:\t%s\n", info.Source)
+	}
+	if info.SHA256 != "" {
+		_, _ = fmt.Fprintf(w, "sha256:\t%s\n", info.SHA256)
+	}
+	_, _ = fmt.Fprintf(w, "released:\t%t\n", info.Released)
+	_, _ = fmt.Fprintf(w, "stable:\t%t\n", info.Stable)
+	return w.Flush()
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+)
+
+func TestInfo(t *testing.T) {
+	testutils.Run(t, "Info", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		server := newReleaseServer("1.22.0-rc.1", "1.21.3", "1.20.5")
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+		})
+
+		g.After(func() {
+			server.Close()
+		})
+
+		newSut := func() *executor {
+			sut := defaultExecutor()
+			sut.URL = server.URL
+			sut.Streams.Out = &Buffer{&bytes.Buffer{}}
+			return sut
+		}
+
+		g.It("describes installed versions", func() {
+			sut := newSut()
+			Ω(sut.Install("1.21.3")).Should(Succeed())
+			symlink(afero.NewOsFs(), filepath.Join(InstallPath, "1.21.3"), filepath.Join(InstallPath, "current"))
+
+			info, err := sut.info(context.Background(), "1.21.3")
+			Ω(err).Should(Succeed())
+			Ω(info.Installed).Should(BeTrue())
+			Ω(info.Current).Should(BeTrue())
+			Ω(info.Path).Should(Equal(filepath.Join(InstallPath, "1.21.3")))
+			Ω(info.Size).Should(BeNumerically(">", 0))
+			Ω(info.InstalledAt).ShouldNot(BeNil())
+			Ω(info.Source).Should(HavePrefix(server.URL))
+			Ω(info.SHA256).ShouldNot(BeEmpty())
+			Ω(info.Released).Should(BeTrue())
+			Ω(info.Stable).Should(BeTrue())
+		})
+
+		g.It("describes released versions which are not installed", func() {
+			info, err := newSut().info(context.Background(), "1.22.0-rc.1")
+			Ω(err).Should(Succeed())
+			Ω(info.Installed).Should(BeFalse())
+			Ω(info.Released).Should(BeTrue())
+			Ω(info.Stable).Should(BeFalse())
+		})
+
+		g.It("rejects unknown versions", func() {
+			_, err := newSut().info(context.Background(), "1.19.1")
+			Ω(err).Should(MatchError(ContainSubstring(errNoMatchingRelease.Error())))
+		})
+
+		g.It("prints json", func() {
+			sut := newSut()
+			Ω(sut.Info(context.Background(), "1.20.5", true)).Should(Succeed())
+			info := map[string]interface{}{}
+			Ω(json.Unmarshal(sut.Streams.Out.(*Buffer).Bytes(), &info)).Should(Succeed())
+			Ω(info).Should(HaveKeyWithValue("version", "1.20.5"))
+			Ω(info).Should(HaveKeyWithValue("installed", false))
+			Ω(info).Should(HaveKeyWithValue("released", true))
+		})
+	})
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/alex-held/dfctl-go/pkg/godist"
+)
+
+// installWorkers bounds the number of versions downloaded and extracted concurrently
+const installWorkers = 3
+
+// versionArg is a version argument and the version it resolved to
+type versionArg struct {
+	Arg     string
+	Version Version
+}
+
+// firstOccurrences returns the index of the first version arg resolving to the same version for every version arg,
+// so args like 1.22 and 1.22.1 install their version once
+func firstOccurrences(resolved []versionArg) []int {
+	first := make([]int, len(resolved))
+	for i, r := range resolved {
+		first[i] = i
+		for j := 0; j < i; j++ {
+			if resolved[j].Version.Compare(r.Version) == 0 {
+				first[i] = j
+				break
+			}
+		}
+	}
+	return first
+}
+
+// resolveVersionArgs resolves every version of args; the args which do not resolve are returned as failures
+func (e *executor) resolveVersionArgs(ctx context.Context, args []string, includeUnstable bool) (resolved []versionArg, failures []bulkFailure) {
+	for _, arg := range args {
+		version, err := e.resolveVersion(ctx, arg, remoteScope, includeUnstable)
+		if err != nil {
+			failures = append(failures, bulkFailure{Item: arg, Err: err})
+			continue
+		}
+		resolved = append(resolved, versionArg{Arg: arg, Version: version})
+	}
+	return resolved, failures
+}
+
+// InstallVersions resolves and installs every version of args. Up to installWorkers versions are downloaded
+// and extracted concurrently with their progress combined into one line; the results are reported in the order of args.
+// Args resolving to the same version install it once and share the result of the first of them.
+// Failures of single versions do not stop the others and are aggregated into a bulkError,
+// whose exit code tells partial from total failure.
+func (e *executor) InstallVersions(ctx context.Context, args []string, includeUnstable bool) error {
+	resolved, failures := e.resolveVersionArgs(ctx, args, includeUnstable)
+	var combined *multiProgress
+	if progress := e.Progress; progress != nil {
+		combined = newMultiProgress(e.Streams.Err)
+		e.Progress = combined.report
+		defer func() {
+			e.Progress = progress
+		}()
+	}
+
+	first := firstOccurrences(resolved)
+	errs := make([]error, len(resolved))
+	skipped := make([]string, len(resolved))
+	jobs := make(chan int)
+	wg := sync.WaitGroup{}
+	for w := 0; w < installWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				errs[i] = e.Install(resolved[i].Version)
+			}
+		}()
+	}
+	for i, r := range resolved {
+		if first[i] != i {
+			continue
+		}
+		if dir, err := e.versionPath(r.Version); err == nil && !e.Force {
+			skipped[i] = dir
+			continue
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	if combined != nil {
+		combined.finish()
+	}
+
+	var installed []Version
+	for i, r := range resolved {
+		if f := first[i]; f != i {
+			if errs[f] != nil {
+				failures = append(failures, bulkFailure{Item: r.Arg, Err: fmt.Errorf("%w; %s resolves to go %s like %s", errs[f], r.Arg, r.Version, resolved[f].Arg)})
+			}
+			continue
+		}
+		switch {
+		case errs[i] != nil:
+			failures = append(failures, bulkFailure{Item: r.Arg, Err: errs[i]})
+			continue
+		case skipped[i] != "":
+			e.infof("go %s is already installed in %s; pass --force to reinstall it\n", r.Version, skipped[i])
+		default:
+			e.infof("installed go %s\n", r.Version)
+		}
+		installed = append(installed, r.Version)
+	}
+	err := newBulkError("install", "versions", len(args), failures)
+	if e.Output == outputJSON {
+		if err != nil {
+			return err
+		}
+		return e.writeInstalled(installed...)
+	}
+	return err
+}
+
+// validateInstallVersions rejects the install flags which only apply to a single version
+func validateInstallVersions(args []string, use bool, fromFile, fromURL, kind string) error {
+	if len(args) > 1 && (use || fromFile != "" || fromURL != "" || kind != string(godist.KindArchive)) {
+		return fmt.Errorf("installing %d versions is not supported with --use, --from-file, --from-url and --kind", len(args))
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+func TestInstallVersions(t *testing.T) {
+	testutils.Run(t, "InstallVersions", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		server := newReleaseServer("1.22.1", "1.21.9", "1.20.14")
+		var out *Buffer
+		var sut *executor
+
+		g.BeforeEach(func() {
+			out = &Buffer{&bytes.Buffer{}}
+			sut = defaultExecutor()
+			sut.URL = server.URL
+			sut.Streams.Out = out
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+		})
+
+		g.After(func() {
+			server.Close()
+		})
+
+		g.It("installs every version and reports each", func() {
+			Ω(sut.InstallVersions(context.Background(), []string{"1.20.14", "1.21", "1.22.1"}, false)).Should(Succeed())
+			Ω(out.String()).Should(Equal("installed go 1.20.14\ninstalled go 1.21.9\ninstalled go 1.22.1\n"))
+			for _, v := range []string{"1.20.14", "1.21.9", "1.22.1"} {
+				Ω(filepath.Join(InstallPath, v)).Should(BeADirectory())
+			}
+		})
+
+		g.It("combines the progress of concurrent installs", func() {
+			errOut := &Buffer{&bytes.Buffer{}}
+			sut.Streams.Err = errOut
+			sut.Progress = func(progressEvent) {}
+			Ω(sut.InstallVersions(context.Background(), []string{"1.21.9", "1.22.1"}, false)).Should(Succeed())
+			Ω(errOut.String()).Should(ContainSubstring("extracting"))
+			Ω(errOut.String()).Should(HaveSuffix("\r\033[K"))
+			Ω(sut.Progress).ShouldNot(BeNil())
+		})
+
+		g.It("skips installed versions", func() {
+			Ω(sut.InstallVersions(context.Background(), []string{"1.22.1"}, false)).Should(Succeed())
+			out.Reset()
+			Ω(sut.InstallVersions(context.Background(), []string{"1.21.9", "1.22.1"}, false)).Should(Succeed())
+			Ω(out.String()).Should(Equal("installed go 1.21.9\ngo 1.22.1 is already installed in " + filepath.Join(InstallPath, "1.22.1") + "; pass --force to reinstall it\n"))
+		})
+
+		g.It("installs args resolving to the same version once", func() {
+			Ω(sut.InstallVersions(context.Background(), []string{"1.22", "1.22.1", "stable"}, false)).Should(Succeed())
+			Ω(out.String()).Should(Equal("installed go 1.22.1\n"))
+			Ω(filepath.Join(InstallPath, "1.22.1")).Should(BeADirectory())
+			Ω(filepath.Join(InstallPath, "1.22.1"+stagingSuffix)).ShouldNot(BeADirectory())
+		})
+
+		g.It("continues past failures and reports them as partial failure", func() {
+			err := sut.InstallVersions(context.Background(), []string{"1.19.x.y", "1.22.1"}, false)
+			var bulkErr *bulkError
+			Ω(errors.As(err, &bulkErr)).Should(BeTrue())
+			Ω(bulkErr.Failures).Should(HaveLen(1))
+			Ω(bulkErr.Failures[0].Item).Should(Equal("1.19.x.y"))
+			Ω(bulkErr.ExitCode()).Should(Equal(exitPartialFailure))
+			Ω(filepath.Join(InstallPath, "1.22.1")).Should(BeADirectory())
+		})
+	})
+}
+
+func TestValidateInstallVersions(t *testing.T) {
+	testutils.Run(t, "validateInstallVersions", func(g *goblin.G) {
+		g.It("accepts multiple versions", func() {
+			Ω(validateInstallVersions([]string{"1.21.9", "1.22.1"}, false, "", "", "archive")).Should(Succeed())
+		})
+
+		g.It("rejects flags of single versions", func() {
+			Ω(validateInstallVersions([]string{"1.21.9", "1.22.1"}, true, "", "", "archive")).ShouldNot(Succeed())
+			Ω(validateInstallVersions([]string{"1.21.9", "1.22.1"}, false, "", "", "pkg")).ShouldNot(Succeed())
+			Ω(validateInstallVersions([]string{"1.22.1"}, true, "", "", "archive")).Should(Succeed())
+		})
+	})
+}
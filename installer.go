@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/alex-held/dfctl-kit/pkg/system"
+	"github.com/pkg/errors"
+
+	"github.com/alex-held/dfctl-go/pkg/godist"
+)
+
+// installerKinds maps the --kind values of installer packages to the operating system they are published for
+var installerKinds = map[string]string{
+	"pkg": "darwin",
+	"msi": "windows",
+}
+
+var errUnsupportedInstallKind = errors.New("unsupported install kind")
+
+// DownloadInstaller downloads and verifies the official installer package of kind for version into dest without installing it
+func (e *executor) DownloadInstaller(ctx context.Context, version Version, kind, dest string) error {
+	goos, ok := installerKinds[kind]
+	if !ok {
+		return fmt.Errorf("%w; kind=%s; use archive, pkg or msi", errUnsupportedInstallKind, kind)
+	}
+	ri := system.RuntimeInfo{OS: goos, Arch: system.Get().Arch}
+	name, err := godist.ArtifactName(version.GoName(), ri.OS, ri.Arch, godist.KindInstaller)
+	if err != nil {
+		return err
+	}
+	f, err := e.releaseFile(ctx, name)
+	if err != nil {
+		return err
+	}
+	if err = e.Fs.MkdirAll(dest, os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create the directory %s; err=%v", dest, err)
+	}
+	e.Summary.addVersion(version)
+	status, err := e.mirrorFile(ctx, dest, f)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(e.Streams.Out, "%s %s\n", status, filepath.Join(dest, name))
+	return nil
+}
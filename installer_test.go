@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/system"
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestDownloadInstaller(t *testing.T) {
+	testutils.Run(t, "DownloadInstaller", func(g *goblin.G) {
+		dest := testutils.TempDir(t, "installers")
+		sum := sha256.Sum256(archiveData)
+		darwin := system.RuntimeInfo{OS: "darwin", Arch: system.Get().Arch}
+		name := "go1.22.1.darwin-" + darwin.Arch + ".pkg"
+		newServer := func(checksum string) *httptest.Server {
+			pkg := archiveReleaseFile(name, "installer", darwin, "1.22.1")
+			pkg.SHA256 = checksum
+			return newPlatformReleaseServer(darwin, nil, pkg)
+		}
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(dest)
+		})
+
+		newSut := func(server *httptest.Server) *executor {
+			sut := defaultExecutor()
+			sut.URL = server.URL
+			sut.Streams.Out = &Buffer{&bytes.Buffer{}}
+			return sut
+		}
+
+		g.It("downloads the verified installer package", func() {
+			server := newServer(hex.EncodeToString(sum[:]))
+			defer server.Close()
+			Ω(newSut(server).DownloadInstaller(context.Background(), "1.22.1", "pkg", dest)).Should(Succeed())
+			Ω(filepath.Join(dest, name)).Should(BeARegularFile())
+		})
+
+		g.It("rejects installer packages with a wrong checksum", func() {
+			server := newServer("0000")
+			defer server.Close()
+			err := newSut(server).DownloadInstaller(context.Background(), "1.22.1", "pkg", dest)
+			Ω(errors.Is(err, errChecksumMismatch)).Should(BeTrue())
+			Ω(filepath.Join(dest, name)).ShouldNot(BeAnExistingFile())
+		})
+
+		g.It("rejects unknown kinds", func() {
+			server := newServer("")
+			defer server.Close()
+			Ω(errors.Is(newSut(server).DownloadInstaller(context.Background(), "1.22.1", "deb", dest), errUnsupportedInstallKind)).Should(BeTrue())
+		})
+	})
+}
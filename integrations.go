@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/alex-held/dfctl-kit/pkg/system"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/afero"
+)
+
+const (
+	// integrationLaunchd exports the new GOROOT to apps launched by launchd on macOS, e.g. from the dock.
+	// Only apps launched afterwards see it; running apps keep their environment until they are restarted.
+	integrationLaunchd = "launchd"
+	// integrationGopls terminates the gopls instances running the previous toolchain, which VS Code and GoLand restart using the new one.
+	// Instances of other toolchains are left alone; it needs the proc filesystem of linux.
+	integrationGopls = "gopls"
+	// integrationHolders prints the running processes which still execute binaries of the previous GOROOT
+	integrationHolders = "holders"
+)
+
+// procPath is the proc filesystem used to find processes holding a GOROOT open on linux
+var procPath = "/proc"
+
+// runIntegration runs the command line of a post switch integration
+var runIntegration = func(argv []string) error {
+	return exec.Command(argv[0], argv[1:]...).Run()
+}
+
+// IntegrationsConfig configures platform integrations
+type IntegrationsConfig struct {
+	// PostSwitch lists the integrations run after use changed the current version, e.g. launchd, gopls and holders
+	PostSwitch []string `yaml:"post_switch,omitempty"`
+}
+
+// integrationCommand returns the command line of the post switch integration name after the current GOROOT changed
+// from previous to root on the given platform, or nil if there is nothing to do
+func (e *executor) integrationCommand(ri system.RuntimeInfo, name, previous, root string) ([]string, error) {
+	switch {
+	case name == integrationLaunchd && ri.OS == "darwin":
+		return []string{"launchctl", "setenv", "GOROOT", root}, nil
+	case name == integrationGopls && ri.OS == "linux":
+		if previous == "" || filepath.Clean(previous) == filepath.Clean(root) {
+			return nil, nil
+		}
+		pids := e.goplsRunning(procPath, previous)
+		if len(pids) == 0 {
+			return nil, nil
+		}
+		return append([]string{"kill", "-TERM"}, pids...), nil
+	}
+	return nil, fmt.Errorf("integration %s is not supported on %s", name, ri.OS)
+}
+
+// goplsRunning returns the ids of the gopls processes read from the linux proc filesystem at proc, which run the toolchain
+// of the previous GOROOT: their GOROOT or PATH point into previous, or into the current link and the shims, which resolved to it when they started
+func (e *executor) goplsRunning(proc, previous string) (pids []string) {
+	linker, ok := e.Fs.(afero.LinkReader)
+	if !ok || previous == "" {
+		return nil
+	}
+	fis, err := afero.ReadDir(e.Fs, proc)
+	if err != nil {
+		return nil
+	}
+	roots := []string{previous, filepath.Join(e.InstallPath, "current")}
+	if e.ShimPath != "" {
+		roots = append(roots, e.ShimPath)
+	}
+	within := func(dir string) bool {
+		for _, root := range roots {
+			if dir = filepath.Clean(dir); dir == filepath.Clean(root) || strings.HasPrefix(dir, filepath.Clean(root)+string(filepath.Separator)) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, fi := range fis {
+		if _, err := strconv.Atoi(fi.Name()); err != nil {
+			continue
+		}
+		exe, err := linker.ReadlinkIfPossible(filepath.Join(proc, fi.Name(), "exe"))
+		if err != nil || filepath.Base(strings.TrimSuffix(exe, " (deleted)")) != "gopls" {
+			continue
+		}
+		environ, err := afero.ReadFile(e.Fs, filepath.Join(proc, fi.Name(), "environ"))
+		if err != nil {
+			continue
+		}
+		matches := false
+		for _, kv := range strings.Split(string(environ), "\x00") {
+			switch key, value := splitEnv(kv); key {
+			case "GOROOT":
+				matches = matches || within(value)
+			case "PATH":
+				for _, dir := range filepath.SplitList(value) {
+					matches = matches || within(dir)
+				}
+			}
+		}
+		if matches {
+			pids = append(pids, fi.Name())
+		}
+	}
+	return pids
+}
+
+// splitEnv splits the environment variable kv like GOROOT=/usr/local/go into its key and value
+func splitEnv(kv string) (string, string) {
+	if i := strings.Index(kv, "="); i > 0 {
+		return kv[:i], kv[i+1:]
+	}
+	return kv, ""
+}
+
+// goRootHolders returns the processes whose executable lives in root, read from the linux proc filesystem at proc
+func (e *executor) goRootHolders(proc, root string) (holders []string) {
+	linker, ok := e.Fs.(afero.LinkReader)
+	if !ok {
+		return nil
+	}
+	fis, err := afero.ReadDir(e.Fs, proc)
+	if err != nil {
+		return nil
+	}
+	root = filepath.Clean(root) + string(filepath.Separator)
+	for _, fi := range fis {
+		if _, err := strconv.Atoi(fi.Name()); err != nil {
+			continue
+		}
+		exe, err := linker.ReadlinkIfPossible(filepath.Join(proc, fi.Name(), "exe"))
+		if err != nil || !strings.HasPrefix(exe, root) {
+			continue
+		}
+		holders = append(holders, fmt.Sprintf("%s (%s)", fi.Name(), exe))
+	}
+	return holders
+}
+
+// postSwitch runs the configured post switch integrations after the current GOROOT changed from previous to root.
+// Failing integrations never fail the switch.
+func (e *executor) postSwitch(previous, root string) {
+	cfg, err := e.config()
+	if err != nil || len(cfg.Integrations.PostSwitch) == 0 {
+		return
+	}
+	ri := system.Get()
+	for _, name := range cfg.Integrations.PostSwitch {
+		if name == integrationHolders {
+			if previous == "" || filepath.Clean(previous) == filepath.Clean(root) || ri.OS != "linux" {
+				continue
+			}
+			if holders := e.goRootHolders(procPath, previous); len(holders) > 0 {
+				_, _ = fmt.Fprintf(e.Streams.Err, "these processes still run go from %s and may need a restart:\n", previous)
+				for _, h := range holders {
+					_, _ = fmt.Fprintf(e.Streams.Err, "  %s\n", h)
+				}
+			}
+			continue
+		}
+		argv, err := e.integrationCommand(ri, name, previous, root)
+		if err != nil {
+			log.Warn().Err(err).Msg("skipping post switch integration")
+			continue
+		}
+		if argv == nil {
+			continue
+		}
+		if err = runIntegration(argv); err != nil {
+			log.Debug().Err(err).Msgf("post switch integration %s failed", name)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/system"
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+)
+
+// writeProc writes the process pid running exe with the environment env into the fake proc filesystem at proc
+func writeProc(proc, pid, exe string, env ...string) {
+	_ = os.MkdirAll(filepath.Join(proc, pid), os.ModePerm)
+	_ = os.Symlink(exe, filepath.Join(proc, pid, "exe"))
+	_ = os.WriteFile(filepath.Join(proc, pid, "environ"), []byte(strings.Join(env, "\x00")+"\x00"), 0644)
+}
+
+func TestIntegrationCommand(t *testing.T) {
+	testutils.Run(t, "integrationCommand", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		proc := testutils.TempDir(t, "proc")
+		previous, root := filepath.Join(InstallPath, "1.21.3"), filepath.Join(InstallPath, "1.22.1")
+		var sut *executor
+
+		g.BeforeEach(func() {
+			procPath = proc
+			sut = defaultExecutor()
+			sut.ShimPath = filepath.Join(InstallPath, "shims")
+			writeProc(proc, "50", "/home/gopher/go/bin/gopls", "HOME=/home/gopher", "PATH=/usr/bin:"+filepath.Join(InstallPath, "current", "bin"))
+			writeProc(proc, "51", "/home/gopher/go/bin/gopls", "GOROOT="+previous)
+			writeProc(proc, "52", "/home/gopher/go/bin/gopls", "PATH="+sut.ShimPath)
+			writeProc(proc, "53", "/home/gopher/go/bin/gopls", "PATH=/usr/local/go/bin:/usr/bin")
+			writeProc(proc, "54", "/usr/bin/bash", "GOROOT="+previous)
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(proc)
+		})
+
+		g.It("exports GOROOT to launchd on darwin only", func() {
+			Ω(sut.integrationCommand(system.RuntimeInfo{OS: "darwin"}, integrationLaunchd, previous, root)).Should(Equal([]string{"launchctl", "setenv", "GOROOT", root}))
+			for _, goos := range []string{"linux", "windows"} {
+				_, err := sut.integrationCommand(system.RuntimeInfo{OS: goos}, integrationLaunchd, previous, root)
+				Ω(err).Should(MatchError("integration launchd is not supported on "+goos), goos)
+			}
+		})
+
+		g.It("terminates the gopls instances of the previous toolchain on linux", func() {
+			Ω(sut.integrationCommand(system.RuntimeInfo{OS: "linux"}, integrationGopls, previous, root)).Should(Equal([]string{"kill", "-TERM", "50", "51", "52"}))
+		})
+
+		g.It("does nothing without gopls instances of the previous toolchain", func() {
+			Ω(sut.integrationCommand(system.RuntimeInfo{OS: "linux"}, integrationGopls, "", root)).Should(BeNil())
+			Ω(sut.integrationCommand(system.RuntimeInfo{OS: "linux"}, integrationGopls, root, root)).Should(BeNil())
+			_ = os.RemoveAll(proc)
+			Ω(sut.integrationCommand(system.RuntimeInfo{OS: "linux"}, integrationGopls, previous, root)).Should(BeNil())
+		})
+
+		g.It("does not support gopls without the proc filesystem", func() {
+			for _, goos := range []string{"darwin", "windows"} {
+				_, err := sut.integrationCommand(system.RuntimeInfo{OS: goos}, integrationGopls, previous, root)
+				Ω(err).Should(MatchError("integration gopls is not supported on "+goos), goos)
+			}
+		})
+	})
+}
+
+func TestPostSwitch(t *testing.T) {
+	testutils.Run(t, "PostSwitch", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		ConfigFile = filepath.Join(testutils.TempDir(t), "go.yaml")
+		proc := testutils.TempDir(t, "proc")
+		var ran [][]string
+		var errOut *Buffer
+
+		g.BeforeEach(func() {
+			ran = nil
+			runIntegration = func(argv []string) error {
+				ran = append(ran, argv)
+				return nil
+			}
+			procPath = proc
+			for _, v := range []string{"1.21.3", "1.22.1"} {
+				_ = os.MkdirAll(filepath.Join(InstallPath, v, "bin"), os.ModePerm)
+			}
+			symlink(afero.NewOsFs(), filepath.Join(InstallPath, "1.21.3"), filepath.Join(InstallPath, "current"))
+			_ = os.MkdirAll(filepath.Join(proc, "42"), os.ModePerm)
+			_ = os.Symlink(filepath.Join(InstallPath, "1.21.3", "bin", "go"), filepath.Join(proc, "42", "exe"))
+			_ = os.MkdirAll(filepath.Join(proc, "43"), os.ModePerm)
+			_ = os.Symlink("/usr/bin/bash", filepath.Join(proc, "43", "exe"))
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+			_ = os.RemoveAll(proc)
+			_ = os.Remove(ConfigFile)
+		})
+
+		writeConfig := func(content string) {
+			_ = os.MkdirAll(filepath.Dir(ConfigFile), os.ModePerm)
+			_ = os.WriteFile(ConfigFile, []byte(content), os.ModePerm)
+		}
+
+		newSut := func() *executor {
+			errOut = &Buffer{&bytes.Buffer{}}
+			sut := defaultExecutor()
+			sut.Streams.Out = &Buffer{&bytes.Buffer{}}
+			sut.Streams.Err = errOut
+			return sut
+		}
+
+		g.It("runs nothing unless configured", func() {
+			Ω(newSut().Use("1.22.1")).Should(Succeed())
+			Ω(ran).Should(BeEmpty())
+		})
+
+		g.It("runs the configured integrations after use", func() {
+			if system.Get().OS != "linux" {
+				return
+			}
+			writeProc(proc, "44", "/home/gopher/go/bin/gopls", "GOROOT="+filepath.Join(InstallPath, "1.21.3"))
+			writeConfig("integrations:\n  post_switch: [gopls]\n")
+			Ω(newSut().Use("1.22.1")).Should(Succeed())
+			Ω(ran).Should(Equal([][]string{{"kill", "-TERM", "44"}}))
+		})
+
+		g.It("finds processes running the previous GOROOT", func() {
+			holders := newSut().goRootHolders(proc, filepath.Join(InstallPath, "1.21.3"))
+			Ω(holders).Should(Equal([]string{"42 (" + filepath.Join(InstallPath, "1.21.3", "bin", "go") + ")"}))
+		})
+
+		g.It("prints processes holding the previous GOROOT", func() {
+			if system.Get().OS != "linux" {
+				return
+			}
+			writeConfig("integrations:\n  post_switch: [holders]\n")
+			Ω(newSut().Use("1.22.1")).Should(Succeed())
+			Ω(errOut.String()).Should(ContainSubstring("these processes still run go from " + filepath.Join(InstallPath, "1.21.3")))
+			Ω(errOut.String()).Should(ContainSubstring("  42 ("))
+			Ω(errOut.String()).ShouldNot(ContainSubstring("  43 ("))
+		})
+	})
+}
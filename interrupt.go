@@ -0,0 +1,27 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// exitInterrupted is the exit code of commands cancelled by SIGINT or SIGTERM, following the shell convention of 128+SIGINT
+const exitInterrupted = 130
+
+// commandContext is the context of the running command, which is cancelled on SIGINT or SIGTERM
+var commandContext = context.Background()
+
+// interruptible returns a context which is cancelled on the first SIGINT or SIGTERM.
+// Downloads and extractions return once it is cancelled and clean up their staging directories and partial files;
+// a second signal terminates immediately.
+func interruptible(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(parent, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		// restore the default behaviour, so a second signal terminates
+		stop()
+	}()
+	return ctx, stop
+}
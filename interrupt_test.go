@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestInterrupt(t *testing.T) {
+	testutils.Run(t, "Interrupt", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		server := newReleaseServer("1.22.1")
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+			_ = os.RemoveAll(CachePath)
+		})
+
+		g.After(func() {
+			server.Close()
+		})
+
+		cancelled := func() *executor {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			sut := defaultExecutor()
+			sut.URL = server.URL
+			sut.Ctx = ctx
+			sut.Streams.Out = &Buffer{&bytes.Buffer{}}
+			return sut
+		}
+
+		g.It("stops extracting and removes the staging directory", func() {
+			err := cancelled().installArchive("1.22.1", bytes.NewBuffer(archiveData), "test")
+			Ω(err).Should(MatchError(ContainSubstring(context.Canceled.Error())))
+			Ω(filepath.Join(InstallPath, "1.22.1")).ShouldNot(BeADirectory())
+			Ω(filepath.Join(InstallPath, "1.22.1"+stagingSuffix)).ShouldNot(BeADirectory())
+		})
+
+		g.It("stops downloading without leaving partial files", func() {
+			Ω(cancelled().Install("1.22.1")).ShouldNot(Succeed())
+			Ω(filepath.Join(InstallPath, "1.22.1")).ShouldNot(BeADirectory())
+			partials, _ := filepath.Glob(filepath.Join(CachePath, "*", "*", "*", "*.partial"))
+			Ω(partials).Should(BeEmpty())
+		})
+
+		g.It("cancels the context on SIGINT", func() {
+			ctx, stop := interruptible(context.Background())
+			defer stop()
+			p, err := os.FindProcess(os.Getpid())
+			Ω(err).Should(Succeed())
+			Ω(p.Signal(os.Interrupt)).Should(Succeed())
+			Eventually(ctx.Done(), time.Second).Should(BeClosed())
+		})
+	})
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// listRow is a row of the table printed by list in a terminal
+type listRow struct {
+	versionEntry
+	InstalledAt time.Time
+	// Update is the newest patch release of the release train if it is newer than Version
+	Update Version
+}
+
+// installedAt returns when the version at dir was installed according to its manifest, or the modification time of dir
+func (e *executor) installedAt(dir string) time.Time {
+	if m, err := e.readManifest(dir); err == nil && !m.InstalledAt.IsZero() {
+		return m.InstalledAt
+	}
+	if fi, err := e.Fs.Stat(dir); err == nil {
+		return fi.ModTime()
+	}
+	return time.Time{}
+}
+
+// listRows returns the installed versions with their install date and newer patch releases.
+// The release feed is optional; feedOK is false if it was unavailable and the updates are unknown.
+func (e *executor) listRows(ctx context.Context) (rows []listRow, feedOK bool, err error) {
+	entries, errs, err := e.listEntries()
+	if err != nil {
+		return nil, false, err
+	}
+	for _, err := range errs {
+		log.Warn().Err(err).Send()
+	}
+	cfg, err := e.config()
+	if err != nil {
+		return nil, false, err
+	}
+	var remote []Version
+	if !e.Offline {
+		if remote, err = e.remoteVersions(ctx, false); err != nil {
+			log.Debug().Err(err).Msg("listing the installed versions without updates")
+		} else {
+			feedOK = true
+		}
+	}
+
+	for _, entry := range entries {
+		row := listRow{versionEntry: entry, InstalledAt: e.installedAt(entry.Path)}
+		if latest, ok := latestPatch(remote, entry.Version.Minor(), cfg); ok && entry.Version.IsStable() && latest.Compare(entry.Version) > 0 {
+			row.Update = latest
+		}
+		rows = append(rows, row)
+	}
+	return rows, feedOK, nil
+}
+
+// ListTable prints the installed versions as a table with their install date, size on disk,
+// whether they are the current version and whether a newer patch release exists
+func (e *executor) ListTable(ctx context.Context) error {
+	rows, feedOK, err := e.listRows(ctx)
+	if err != nil {
+		return err
+	}
+	buf := &bytes.Buffer{}
+	w := tabwriter.NewWriter(buf, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "VERSION\tINSTALLED\tSIZE\tCURRENT\tUPDATE")
+	for _, row := range rows {
+		version := row.Version.String()
+		if row.External {
+			version += " (external)"
+		}
+		installed := "-"
+		if !row.InstalledAt.IsZero() {
+			installed = row.InstalledAt.Local().Format("2006-01-02")
+		}
+		current := ""
+		if row.Current {
+			current = "*"
+		}
+		update := string(row.Update)
+		if !feedOK {
+			update = "?"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", version, installed, formatBytes(row.Size), current, update)
+	}
+	if err = w.Flush(); err != nil {
+		return err
+	}
+	// the current version is highlighted after the alignment, which would count the escape sequences as text
+	for i, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		if i > 0 && rows[i-1].Current {
+			line = e.colorize(line, styleBold, styleGreen)
+		}
+		_, _ = fmt.Fprintln(e.Streams.Out, line)
+	}
+	return nil
+}
+
+// listAllEntries returns the installed versions merged with the stable releases of the release feed in descending order
+func (e *executor) listAllEntries(ctx context.Context) ([]versionEntry, []error, error) {
+	entries, errs, err := e.listEntries()
+	if err != nil {
+		return nil, nil, err
+	}
+	remote, err := e.remoteVersions(ctx, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	installed := map[Version]bool{}
+	for _, entry := range entries {
+		installed[entry.Version] = true
+	}
+	for _, v := range remote {
+		if ok, _ := e.matches(v); ok && !installed[v] {
+			entries = append(entries, versionEntry{Version: v, Remote: true})
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Version.Compare(entries[j].Version) > 0
+	})
+	return entries, errs, nil
+}
+
+// ListAll prints the installed versions merged with the releases of the release feed.
+// Unless plain output is requested, the current version is highlighted and releases which are not installed are greyed out.
+func (e *executor) ListAll(ctx context.Context, styled bool) error {
+	entries, errs, err := e.listAllEntries(ctx)
+	if err != nil {
+		return err
+	}
+	if e.Output == outputJSON || e.Format != "" {
+		return e.writeVersions(entries, errs)
+	}
+	for _, err := range errs {
+		log.Warn().Err(err).Send()
+	}
+
+	buf := &bytes.Buffer{}
+	w := tabwriter.NewWriter(buf, 0, 4, 2, ' ', 0)
+	for _, entry := range entries {
+		_, _ = fmt.Fprintf(w, "%s\t%s\n", entry.Version, entry.status())
+	}
+	if err = w.Flush(); err != nil || len(entries) == 0 {
+		return err
+	}
+	// the lines are styled after the alignment, which would count the escape sequences as text
+	for i, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		switch {
+		case styled && entries[i].Current:
+			line = e.colorize(line, styleBold, styleGreen)
+		case styled && entries[i].Remote:
+			line = e.colorize(line, styleDim)
+		}
+		_, _ = fmt.Fprintln(e.Streams.Out, line)
+	}
+	return nil
+}
+
+// status describes whether the version is current, installed, external or only available remotely
+func (v versionEntry) status() string {
+	switch {
+	case v.Current:
+		return "current"
+	case v.External:
+		return "external"
+	case v.Remote:
+		return "available"
+	}
+	return "installed"
+}
+
+// matches reports whether v satisfies the constraint passed to list with --constraint, if any
+func (e *executor) matches(v Version) (bool, error) {
+	if e.Constraint == "" {
+		return true, nil
+	}
+	return satisfies(v, e.Constraint)
+}
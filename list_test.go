@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+)
+
+func TestListTable(t *testing.T) {
+	testutils.Run(t, "ListTable", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		server := newReleaseServer("1.22.1", "1.21.9", "1.21.3", "1.20.5")
+		var out *Buffer
+		var externalRoots []string
+		installed := time.Date(2024, 3, 5, 12, 0, 0, 0, time.Local)
+
+		g.BeforeEach(func() {
+			externalRoots, ExternalGoRoots = ExternalGoRoots, nil
+			for _, v := range []string{"1.20.5", "1.21.3"} {
+				_ = os.MkdirAll(filepath.Join(InstallPath, v), os.ModePerm)
+			}
+			_ = afero.WriteFile(afero.NewOsFs(), filepath.Join(InstallPath, "1.21.3", "VERSION"), []byte("go1.21.3"), 0644)
+			for _, v := range []string{"1.20.5", "1.21.3"} {
+				_ = os.Chtimes(filepath.Join(InstallPath, v), installed, installed)
+			}
+			symlink(afero.NewOsFs(), filepath.Join(InstallPath, "1.21.3"), filepath.Join(InstallPath, "current"))
+		})
+
+		g.AfterEach(func() {
+			ExternalGoRoots = externalRoots
+			_ = os.RemoveAll(InstallPath)
+		})
+
+		g.After(func() {
+			server.Close()
+		})
+
+		newSut := func() *executor {
+			out = &Buffer{&bytes.Buffer{}}
+			sut := defaultExecutor()
+			sut.URL = server.URL
+			sut.Streams.Out = out
+			return sut
+		}
+
+		g.It("prints the install date, size, current version and newer patch releases", func() {
+			Ω(newSut().ListTable(context.Background())).Should(Succeed())
+			Ω(out.String()).Should(Equal("" +
+				"VERSION  INSTALLED   SIZE  CURRENT  UPDATE\n" +
+				"1.21.3   2024-03-05  8 B   *        1.21.9\n" +
+				"1.20.5   2024-03-05  0 B            \n"))
+		})
+
+		g.It("marks updates as unknown without release feed", func() {
+			sut := newSut()
+			sut.Offline = true
+			Ω(sut.ListTable(context.Background())).Should(Succeed())
+			Ω(out.String()).Should(ContainSubstring("1.21.3   2024-03-05  8 B   *        ?\n"))
+		})
+
+		g.It("highlights the current version with colors", func() {
+			sut := newSut()
+			sut.Color = true
+			Ω(sut.ListTable(context.Background())).Should(Succeed())
+			Ω(out.String()).Should(ContainSubstring("\033[1m\033[32m1.21.3   2024-03-05  8 B   *        1.21.9\033[0m\n"))
+			Ω(out.String()).Should(ContainSubstring("\n1.20.5   2024-03-05  0 B            \n"))
+		})
+	})
+}
+
+func TestListPlain(t *testing.T) {
+	testutils.Run(t, "List", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		var externalRoots []string
+
+		g.BeforeEach(func() {
+			externalRoots, ExternalGoRoots = ExternalGoRoots, nil
+			for _, dir := range []string{"v1.9.7", "1.21.3", "v1.16", "go1.20.5", "tmp", "1.21.3~", "#scratch#"} {
+				_ = os.MkdirAll(filepath.Join(InstallPath, dir), os.ModePerm)
+			}
+			symlink(afero.NewOsFs(), filepath.Join(InstallPath, "1.21.3"), filepath.Join(InstallPath, "current"))
+		})
+
+		g.AfterEach(func() {
+			ExternalGoRoots = externalRoots
+			_ = os.RemoveAll(InstallPath)
+		})
+
+		g.It("prints the versions in descending order and skips other directories", func() {
+			out := &Buffer{&bytes.Buffer{}}
+			sut := defaultExecutor()
+			sut.Streams.Out = out
+			Ω(sut.List()).Should(Succeed())
+			Ω(out.String()).Should(Equal("1.21.3\n1.20.5\n1.16.0\n1.9.7\n"))
+		})
+
+		g.It("only prints versions satisfying the constraint", func() {
+			out := &Buffer{&bytes.Buffer{}}
+			sut := defaultExecutor()
+			sut.Streams.Out = out
+			sut.Constraint = ">=1.16, <1.21"
+			Ω(sut.List()).Should(Succeed())
+			Ω(out.String()).Should(Equal("1.20.5\n1.16.0\n"))
+		})
+
+		g.It("fails for invalid constraints", func() {
+			sut := defaultExecutor()
+			sut.Constraint = ">=one"
+			Ω(sut.List()).Should(MatchError(ContainSubstring("invalid constraint >=one")))
+		})
+	})
+}
+
+func TestListAll(t *testing.T) {
+	testutils.Run(t, "ListAll", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		server := newReleaseServer("1.22.1", "1.22.0-rc.1", "1.21.9", "1.21.3")
+		var out *Buffer
+		var sut *executor
+		var externalRoots []string
+
+		g.BeforeEach(func() {
+			externalRoots, ExternalGoRoots = ExternalGoRoots, nil
+			for _, v := range []string{"1.20.5", "1.21.3"} {
+				_ = os.MkdirAll(filepath.Join(InstallPath, v), os.ModePerm)
+			}
+			symlink(afero.NewOsFs(), filepath.Join(InstallPath, "1.21.3"), filepath.Join(InstallPath, "current"))
+			out = &Buffer{&bytes.Buffer{}}
+			sut = defaultExecutor()
+			sut.URL = server.URL
+			sut.Streams.Out = out
+		})
+
+		g.AfterEach(func() {
+			ExternalGoRoots = externalRoots
+			_ = os.RemoveAll(InstallPath)
+		})
+
+		g.After(func() {
+			server.Close()
+		})
+
+		g.It("merges the installed versions and the stable releases", func() {
+			Ω(sut.ListAll(context.Background(), false)).Should(Succeed())
+			Ω(out.String()).Should(Equal("" +
+				"1.22.1  available\n" +
+				"1.21.9  available\n" +
+				"1.21.3  current\n" +
+				"1.20.5  installed\n"))
+		})
+
+		g.It("highlights the current version and greys out releases with colors", func() {
+			sut.Color = true
+			Ω(sut.ListAll(context.Background(), true)).Should(Succeed())
+			Ω(out.String()).Should(ContainSubstring("\033[2m1.22.1  available\033[0m\n"))
+			Ω(out.String()).Should(ContainSubstring("\033[1m\033[32m1.21.3  current\033[0m\n"))
+			Ω(out.String()).Should(ContainSubstring("\n1.20.5  installed\n"))
+		})
+
+		g.It("does not style the versions without colors", func() {
+			sut.Color = false
+			Ω(sut.ListAll(context.Background(), true)).Should(Succeed())
+			Ω(out.String()).ShouldNot(ContainSubstring("\033["))
+		})
+
+		g.It("flags releases which are not installed in json", func() {
+			sut.Output = outputJSON
+			Ω(sut.ListAll(context.Background(), false)).Should(Succeed())
+			var o versionsOutput
+			Ω(json.Unmarshal(out.Bytes(), &o)).Should(Succeed())
+			Ω(o.Versions).Should(HaveLen(4))
+			Ω(o.Versions[0]).Should(Equal(versionEntry{Version: "1.22.1", Remote: true}))
+			Ω(o.Versions[2].Current).Should(BeTrue())
+		})
+
+		g.It("only merges versions satisfying the constraint", func() {
+			sut.Constraint = ">=1.21"
+			Ω(sut.ListAll(context.Background(), false)).Should(Succeed())
+			Ω(out.String()).Should(Equal("" +
+				"1.22.1  available\n" +
+				"1.21.9  available\n" +
+				"1.21.3  current\n"))
+		})
+
+		g.It("fails without release feed", func() {
+			sut.URL = "http://127.0.0.1:1"
+			Ω(sut.ListAll(context.Background(), false)).ShouldNot(Succeed())
+		})
+	})
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func newLocalCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "local [version]",
+		Short: "pins the go version of the working directory in a .go-version file",
+		Long:  "pins the go version of the working directory in a .go-version file; without version the project pin in effect is printed",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateMaxArgsForSubcommand("local", args, 1); err != nil {
+				return err
+			}
+			wd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			e := defaultExecutor()
+			if len(args) == 0 {
+				return e.Local(wd)
+			}
+			return e.SetLocal(c.Context(), wd, args[0])
+		},
+	}
+}
+
+func newGlobalCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "global [version]",
+		Short: "sets the machine wide default go version",
+		Long:  "sets the machine wide default go version used outside of pinned projects; without version the global version is printed",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateMaxArgsForSubcommand("global", args, 1); err != nil {
+				return err
+			}
+			e := defaultExecutor()
+			if len(args) == 0 {
+				current, err := e.current()
+				if err != nil {
+					return err
+				}
+				_, _ = fmt.Fprintln(e.Streams.Out, current)
+				return nil
+			}
+			version, err := e.resolveVersion(c.Context(), args[0], installedScope, false)
+			if err != nil {
+				return err
+			}
+			return e.Use(version)
+		},
+	}
+}
+
+// Local prints the project pin in effect for dir
+func (e *executor) Local(dir string) error {
+	pin, file, err := findProjectPin(e.Fs, dir)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(e.Streams.Out, "%s (%s)\n", pin, file)
+	return nil
+}
+
+// SetLocal pins the version in the ProjectVersionFile of dir.
+// Keywords are resolved to a concrete version, partial versions and channel pins are kept to follow the release train.
+func (e *executor) SetLocal(ctx context.Context, dir, arg string) error {
+	pin := arg
+	if _, ok, err := parseChannel(arg); ok {
+		if err != nil {
+			return err
+		}
+	} else if !isPartialVersion(arg) {
+		version, err := e.resolveVersion(ctx, arg, remoteScope, false)
+		if err != nil {
+			return err
+		}
+		pin = version.String()
+	}
+	file := filepath.Join(dir, ProjectVersionFile)
+	if err := afero.WriteFile(e.Fs, file, []byte(pin+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to write project pin %s; err=%v", file, err)
+	}
+	e.infof("pinned go %s in %s\n", pin, file)
+	return nil
+}
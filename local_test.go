@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestSetLocal(t *testing.T) {
+	testutils.Run(t, "SetLocal", func(g *goblin.G) {
+		project := filepath.Join(testutils.TempDir(t), "project")
+		server := newReleaseServer("1.22.1", "1.21.9")
+
+		g.BeforeEach(func() {
+			_ = os.MkdirAll(project, os.ModePerm)
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(project)
+		})
+
+		g.After(func() {
+			server.Close()
+		})
+
+		newSut := func() *executor {
+			sut := defaultExecutor()
+			sut.URL = server.URL
+			sut.Streams.Out = &Buffer{&bytes.Buffer{}}
+			return sut
+		}
+
+		pinned := func() string {
+			data, _ := os.ReadFile(filepath.Join(project, ProjectVersionFile))
+			return string(data)
+		}
+
+		g.It("writes the canonical version", func() {
+			Ω(newSut().SetLocal(context.Background(), project, "go1.21.5")).Should(Succeed())
+			Ω(pinned()).Should(Equal("1.21.5\n"))
+		})
+
+		g.It("keeps partial versions", func() {
+			Ω(newSut().SetLocal(context.Background(), project, "1.21")).Should(Succeed())
+			Ω(pinned()).Should(Equal("1.21\n"))
+		})
+
+		g.It("resolves keywords", func() {
+			Ω(newSut().SetLocal(context.Background(), project, "stable")).Should(Succeed())
+			Ω(pinned()).Should(Equal("1.22.1\n"))
+		})
+	})
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// LockFile is the advisory lock in the install root held by mutating commands, relative to the install root
+const LockFile = ".dfctl-go.lock"
+
+// defaultLockTimeout is the time mutating commands wait for the lock held by another run
+const defaultLockTimeout = 5 * time.Minute
+
+// lockPollInterval is the time between two attempts to acquire a lock held by another run
+var lockPollInterval = 100 * time.Millisecond
+
+var errLocked = errors.New("another dfctl-go run holds the lock")
+
+var (
+	// noWait is set by the --no-wait flag
+	noWait bool
+	// lockTimeout is set by the --lock-timeout flag
+	lockTimeout time.Duration
+	// activeLock is the lock held by the running mutating command
+	activeLock *installLock
+)
+
+// installLock is an acquired advisory lock, which is released when the process exits at the latest
+type installLock struct {
+	f *os.File
+}
+
+// acquireLock acquires the lock file of the install root dir.
+// If another run holds it, acquireLock fails with errLocked unless wait is set,
+// in which case it retries until timeout elapses or ctx is done.
+func acquireLock(ctx context.Context, dir string, wait bool, timeout time.Duration) (*installLock, error) {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, err
+	}
+	file := filepath.Join(dir, LockFile)
+	deadline := time.Now().Add(timeout)
+	logged := false
+	for {
+		f, err := tryLock(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lock %s; err=%v", file, err)
+		}
+		if f != nil {
+			_ = f.Truncate(0)
+			_, _ = f.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+			return &installLock{f: f}, nil
+		}
+
+		holder := lockHolder(file)
+		if !wait || time.Now().After(deadline) {
+			hint := "run again without --no-wait to wait for it"
+			if wait {
+				hint = "increase --lock-timeout to wait longer"
+			}
+			return nil, fmt.Errorf("%w; file=%s; pid=%s; %s", errLocked, file, holder, hint)
+		}
+		if !logged {
+			log.Info().Msgf("waiting for dfctl-go run %s holding %s", holder, file)
+			logged = true
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
+
+// lockHolder returns the pid recorded in the lock file by the run holding it
+func lockHolder(file string) string {
+	data, err := os.ReadFile(file)
+	if pid := strings.TrimSpace(string(data)); err == nil && pid != "" {
+		return pid
+	}
+	return "unknown"
+}
+
+// release releases the lock
+func (l *installLock) release() {
+	if l == nil {
+		return
+	}
+	_ = l.f.Close()
+}
+
+// lockMutating acquires the install root lock for commands marked by mutating unless they are dry runs
+// or forwarded to a remote host, which locks its own install root
+func lockMutating(c *cobra.Command) error {
+	if c.Annotations[mutatingAnnotation] != "true" || isDryRun(c) || onRemoteHost(c) {
+		return nil
+	}
+	ctx := c.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	lock, err := acquireLock(ctx, InstallPath, !noWait, lockTimeout)
+	if err != nil {
+		return err
+	}
+	activeLock = lock
+	return nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func TestAcquireLock(t *testing.T) {
+	testutils.Run(t, "acquireLock", func(g *goblin.G) {
+		var dir string
+
+		g.BeforeEach(func() {
+			dir = installPath(t)
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(dir)
+		})
+
+		g.It("fails without waiting if another run holds the lock", func() {
+			lock, err := acquireLock(context.Background(), dir, false, 0)
+			Ω(err).Should(Succeed())
+			defer lock.release()
+
+			_, err = acquireLock(context.Background(), dir, false, time.Minute)
+			Ω(errors.Is(err, errLocked)).Should(BeTrue())
+			Ω(err.Error()).Should(ContainSubstring("pid=" + strconv.Itoa(os.Getpid())))
+			Ω(err.Error()).Should(ContainSubstring("--no-wait"))
+		})
+
+		g.It("fails once the timeout elapsed", func() {
+			lock, err := acquireLock(context.Background(), dir, false, 0)
+			Ω(err).Should(Succeed())
+			defer lock.release()
+
+			_, err = acquireLock(context.Background(), dir, true, 10*time.Millisecond)
+			Ω(errors.Is(err, errLocked)).Should(BeTrue())
+			Ω(err.Error()).Should(ContainSubstring("--lock-timeout"))
+		})
+
+		g.It("waits until the lock is released", func() {
+			lock, err := acquireLock(context.Background(), dir, false, 0)
+			Ω(err).Should(Succeed())
+			time.AfterFunc(20*time.Millisecond, lock.release)
+
+			lock, err = acquireLock(context.Background(), dir, true, time.Minute)
+			Ω(err).Should(Succeed())
+			lock.release()
+		})
+
+		g.It("stops waiting when cancelled", func() {
+			lock, err := acquireLock(context.Background(), dir, false, 0)
+			Ω(err).Should(Succeed())
+			defer lock.release()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			_, err = acquireLock(ctx, dir, true, time.Minute)
+			Ω(err).Should(MatchError(context.DeadlineExceeded))
+		})
+	})
+}
+
+func TestLockMutating(t *testing.T) {
+	testutils.Run(t, "lockMutating", func(g *goblin.G) {
+		InstallPath = installPath(t)
+
+		g.AfterEach(func() {
+			activeLock.release()
+			activeLock = nil
+			_ = os.RemoveAll(InstallPath)
+		})
+
+		g.It("locks mutating commands", func() {
+			c := mutating(&cobra.Command{})
+			Ω(lockMutating(c)).Should(Succeed())
+			Ω(activeLock).ShouldNot(BeNil())
+
+			_, err := acquireLock(context.Background(), InstallPath, false, 0)
+			Ω(errors.Is(err, errLocked)).Should(BeTrue())
+		})
+
+		g.It("does not lock read only commands", func() {
+			c := &cobra.Command{}
+			Ω(lockMutating(c)).Should(Succeed())
+			Ω(activeLock).Should(BeNil())
+		})
+	})
+}
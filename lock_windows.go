@@ -0,0 +1,29 @@
+//go:build windows
+// +build windows
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// errorSharingViolation is returned by CreateFile if another process opened the file without sharing it
+const errorSharingViolation syscall.Errno = 32
+
+// tryLock opens file without sharing write access, which locks it until it is closed.
+// It returns nil if another process holds the lock.
+func tryLock(file string) (*os.File, error) {
+	name, err := syscall.UTF16PtrFromString(file)
+	if err != nil {
+		return nil, err
+	}
+	h, err := syscall.CreateFile(name, syscall.GENERIC_READ|syscall.GENERIC_WRITE, syscall.FILE_SHARE_READ, nil, syscall.OPEN_ALWAYS, syscall.FILE_ATTRIBUTE_NORMAL, 0)
+	if err == errorSharingViolation {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return os.NewFile(uintptr(h), file), nil
+}
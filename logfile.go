@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/spf13/afero"
+)
+
+// defaults of the log file rotation
+const (
+	defaultLogMaxSize    = 10 // megabytes
+	defaultLogMaxBackups = 3
+)
+
+// LogConfig configures the log file, which keeps a persistent record of downloads and errors
+type LogConfig struct {
+	// File receives a copy of every log line as json with its time; --log-file takes precedence
+	File string `yaml:"file,omitempty"`
+	// MaxSize is the size in megabytes after which the log file is rotated
+	MaxSize int `yaml:"max_size,omitempty"`
+	// MaxBackups is the number of rotated log files kept next to the log file, like dfctl-go.log.1
+	MaxBackups int `yaml:"max_backups,omitempty"`
+}
+
+// rotatingFile appends to a log file and rotates it to path.1, path.2, ... once it would exceed maxSize bytes
+type rotatingFile struct {
+	fs         afero.Fs
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	mu   sync.Mutex
+	file afero.File
+	size int64
+}
+
+// openRotatingFile opens the log file at path for appending and creates its directory if needed
+func openRotatingFile(fs afero.Fs, path string, maxSize int64, maxBackups int) (*rotatingFile, error) {
+	f := &rotatingFile{fs: fs, path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := fs.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return nil, fmt.Errorf("failed to create the directory of log file %s; err=%v", path, err)
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *rotatingFile) open() error {
+	file, err := f.fs.OpenFile(f.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file %s; err=%v", f.path, err)
+	}
+	fi, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to open log file %s; err=%v", f.path, err)
+	}
+	f.file, f.size = file, fi.Size()
+	return nil
+}
+
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// rotate shifts the backups by one, drops the oldest and moves the log file to path.1
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	backup := func(i int) string {
+		return fmt.Sprintf("%s.%d", f.path, i)
+	}
+	if f.maxBackups == 0 {
+		_ = f.fs.Remove(f.path)
+	} else {
+		_ = f.fs.Remove(backup(f.maxBackups))
+		for i := f.maxBackups - 1; i > 0; i-- {
+			_ = f.fs.Rename(backup(i), backup(i+1))
+		}
+		if err := f.fs.Rename(f.path, backup(1)); err != nil {
+			return fmt.Errorf("failed to rotate log file %s; err=%v", f.path, err)
+		}
+	}
+	return f.open()
+}
+
+func (f *rotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.file.Close()
+}
+
+// openLogFile opens the log file passed with --log-file or configured in the config, if any
+func (e *executor) openLogFile(path string) (*rotatingFile, error) {
+	cfg := &Config{}
+	if c, err := e.config(); err == nil {
+		cfg = c
+	}
+	if path == "" {
+		path = cfg.Log.File
+	}
+	if path == "" {
+		return nil, nil
+	}
+	maxSize, maxBackups := cfg.Log.MaxSize, cfg.Log.MaxBackups
+	if maxSize <= 0 {
+		maxSize = defaultLogMaxSize
+	}
+	if maxBackups <= 0 {
+		maxBackups = defaultLogMaxBackups
+	}
+	return openRotatingFile(e.Fs, path, int64(maxSize)<<20, maxBackups)
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+)
+
+func TestRotatingFile(t *testing.T) {
+	testutils.Run(t, "rotatingFile", func(g *goblin.G) {
+		var fs afero.Fs
+
+		g.BeforeEach(func() {
+			fs = afero.NewMemMapFs()
+		})
+
+		g.It("appends to existing log files", func() {
+			_ = fs.MkdirAll("/logs", 0755)
+			_ = afero.WriteFile(fs, "/logs/dfctl-go.log", []byte("old\n"), 0644)
+			f, err := openRotatingFile(fs, "/logs/dfctl-go.log", 100, 2)
+			Ω(err).Should(Succeed())
+			_, _ = f.Write([]byte("new\n"))
+			Ω(f.Close()).Should(Succeed())
+			Ω(afero.ReadFile(fs, "/logs/dfctl-go.log")).Should(Equal([]byte("old\nnew\n")))
+		})
+
+		g.It("creates the directory, rotates the log file and keeps maxBackups backups", func() {
+			f, err := openRotatingFile(fs, "/logs/dfctl-go.log", 10, 2)
+			Ω(err).Should(Succeed())
+			for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+				_, err = f.Write([]byte(line))
+				Ω(err).Should(Succeed())
+			}
+			Ω(f.Close()).Should(Succeed())
+			Ω(afero.ReadFile(fs, "/logs/dfctl-go.log")).Should(Equal([]byte("fourth\n")))
+			Ω(afero.ReadFile(fs, "/logs/dfctl-go.log.1")).Should(Equal([]byte("third\n")))
+			Ω(afero.ReadFile(fs, "/logs/dfctl-go.log.2")).Should(Equal([]byte("second\n")))
+			Ω(afero.Exists(fs, "/logs/dfctl-go.log.3")).Should(BeFalse())
+		})
+
+		g.It("writes lines larger than maxSize into an empty file", func() {
+			f, err := openRotatingFile(fs, "/dfctl-go.log", 4, 1)
+			Ω(err).Should(Succeed())
+			_, _ = f.Write([]byte(strings.Repeat("x", 8)))
+			Ω(afero.Exists(fs, "/dfctl-go.log.1")).Should(BeFalse())
+		})
+	})
+}
+
+func TestOpenLogFile(t *testing.T) {
+	testutils.Run(t, "openLogFile", func(g *goblin.G) {
+		var fs afero.Fs
+		var sut *executor
+
+		g.BeforeEach(func() {
+			fs = afero.NewMemMapFs()
+			sut = &executor{Fs: fs, ConfigFile: "/config/go.yaml"}
+		})
+
+		g.It("does not log to a file by default", func() {
+			Ω(sut.openLogFile("")).Should(BeNil())
+		})
+
+		g.It("opens the configured log file with its rotation", func() {
+			_ = afero.WriteFile(fs, "/config/go.yaml", []byte("log:\n  file: /logs/dfctl-go.log\n  max_size: 2\n  max_backups: 5\n"), 0644)
+			f, err := sut.openLogFile("")
+			Ω(err).Should(Succeed())
+			Ω(f.path).Should(Equal("/logs/dfctl-go.log"))
+			Ω(f.maxSize).Should(Equal(int64(2 << 20)))
+			Ω(f.maxBackups).Should(Equal(5))
+		})
+
+		g.It("prefers --log-file", func() {
+			_ = afero.WriteFile(fs, "/config/go.yaml", []byte("log:\n  file: /logs/dfctl-go.log\n"), 0644)
+			f, err := sut.openLogFile("/tmp/run.log")
+			Ω(err).Should(Succeed())
+			Ω(f.path).Should(Equal("/tmp/run.log"))
+			Ω(f.maxSize).Should(Equal(int64(defaultLogMaxSize << 20)))
+			Ω(f.maxBackups).Should(Equal(defaultLogMaxBackups))
+		})
+	})
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/alex-held/dfctl-kit/pkg/dflog"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	// verbosity is the number of --verbose flags; -v logs debug, -vv trace output
+	verbosity int
+	// logLevel is set by --log-level
+	logLevel string
+	// logFormat is set by --log-format
+	logFormat = logFormatConsole
+	// logFile is set by --log-file
+	logFile string
+)
+
+// values of --log-format
+const (
+	logFormatConsole = "console"
+	logFormatJSON    = "json"
+)
+
+// logLevelFromFlags returns the level selected by --log-level, --verbose or --quiet, in this order, defaulting to info
+func logLevelFromFlags() (zerolog.Level, error) {
+	switch {
+	case logLevel != "":
+		level, err := zerolog.ParseLevel(strings.ToLower(logLevel))
+		if err != nil || level == zerolog.NoLevel {
+			return zerolog.NoLevel, fmt.Errorf("unsupported --log-level %q; supported are trace, debug, info, warn and error", logLevel)
+		}
+		return level, nil
+	case verbosity > 1:
+		return zerolog.TraceLevel, nil
+	case verbosity == 1:
+		return zerolog.DebugLevel, nil
+	case quiet:
+		return zerolog.ErrorLevel, nil
+	}
+	return zerolog.InfoLevel, nil
+}
+
+func validateLogFormat(format string) error {
+	switch format {
+	case logFormatConsole, logFormatJSON:
+		return nil
+	}
+	return fmt.Errorf("unsupported --log-format %q; supported are %s and %s", format, logFormatConsole, logFormatJSON)
+}
+
+// configureLogging configures the logger with the global flags.
+// --log-format json writes a json object with level, time and message per log line for log collectors.
+// --quiet and --porcelain move the log lines to stderr, so stdout only carries values, and --quiet drops every log line below errors.
+// The levels are colored like errors in red and warnings in yellow as long as colorEnabled allows it for the log output.
+// --log-file or the log.file config additionally writes every log line as json with its time to a rotated log file.
+func configureLogging() error {
+	if err := validateLogFormat(logFormat); err != nil {
+		return err
+	}
+	level, err := logLevelFromFlags()
+	if err != nil {
+		return err
+	}
+	var out io.Writer = os.Stdout
+	if quiet || porcelain {
+		out = os.Stderr
+	}
+	writers := []io.Writer{out}
+	if logFormat == logFormatConsole {
+		writers[0] = consoleWriter(out)
+	}
+	file, err := defaultExecutor().openLogFile(logFile)
+	if err != nil {
+		return err
+	}
+	if file != nil {
+		writers = append(writers, file)
+	}
+	log.Logger = zerolog.New(zerolog.MultiLevelWriter(writers...)).With().Timestamp().Logger()
+	zerolog.SetGlobalLevel(level)
+	return nil
+}
+
+// consoleWriter formats the log lines like dflog for humans, without the time and with colors if enabled for out
+func consoleWriter(out io.Writer) zerolog.ConsoleWriter {
+	formatLevel := dflog.DefaultFormatLevelFormatter()
+	if !colorEnabled(out) {
+		formatLevel = plainFormatLevel
+	}
+	opts := []dflog.LoggerOption{
+		dflog.Without(zerolog.CallerFieldName, zerolog.TimestampFieldName),
+		dflog.WithOrder(zerolog.LevelFieldName, zerolog.MessageFieldName),
+		dflog.WithOut(out),
+		dflog.WithColor(colorEnabled(out)),
+		dflog.WithFormatLevel(formatLevel),
+	}
+	return zerolog.NewConsoleWriter(func(w *zerolog.ConsoleWriter) {
+		for _, opt := range opts {
+			opt(w)
+		}
+	})
+}
+
+// plainLevels are the level labels of dflog.DefaultFormatLevelFormatter without colors
+var plainLevels = map[string]string{
+	zerolog.LevelTraceValue: "TRC",
+	zerolog.LevelDebugValue: "DBG",
+	zerolog.LevelInfoValue:  "INF",
+	zerolog.LevelWarnValue:  "WRN",
+	zerolog.LevelErrorValue: "ERR",
+	zerolog.LevelFatalValue: "FTL",
+	zerolog.LevelPanicValue: "PNC",
+}
+
+// plainFormatLevel formats levels like dflog.DefaultFormatLevelFormatter, but without colors
+func plainFormatLevel(i interface{}) string {
+	if l, ok := plainLevels[fmt.Sprint(i)]; ok {
+		return l
+	}
+	return "???"
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+)
+
+func TestPlainFormatLevel(t *testing.T) {
+	testutils.Run(t, "plainFormatLevel", func(g *goblin.G) {
+		g.It("abbreviates the levels without colors", func() {
+			Ω(plainFormatLevel(zerolog.LevelErrorValue)).Should(Equal("ERR"))
+			Ω(plainFormatLevel(zerolog.LevelWarnValue)).Should(Equal("WRN"))
+			Ω(plainFormatLevel(zerolog.LevelDebugValue)).Should(Equal("DBG"))
+		})
+
+		g.It("marks unknown levels", func() {
+			Ω(plainFormatLevel(nil)).Should(Equal("???"))
+		})
+	})
+}
+
+func TestLogLevelFromFlags(t *testing.T) {
+	testutils.Run(t, "logLevelFromFlags", func(g *goblin.G) {
+		g.AfterEach(func() {
+			verbosity, logLevel, quiet = 0, "", false
+		})
+
+		g.It("defaults to info", func() {
+			Ω(logLevelFromFlags()).Should(Equal(zerolog.InfoLevel))
+		})
+
+		g.It("logs debug output with -v and trace output with -vv", func() {
+			verbosity = 1
+			Ω(logLevelFromFlags()).Should(Equal(zerolog.DebugLevel))
+			verbosity = 2
+			Ω(logLevelFromFlags()).Should(Equal(zerolog.TraceLevel))
+		})
+
+		g.It("only logs errors with --quiet", func() {
+			quiet = true
+			Ω(logLevelFromFlags()).Should(Equal(zerolog.ErrorLevel))
+		})
+
+		g.It("prefers --log-level", func() {
+			verbosity, quiet, logLevel = 2, true, "WARN"
+			Ω(logLevelFromFlags()).Should(Equal(zerolog.WarnLevel))
+		})
+
+		g.It("rejects unknown levels", func() {
+			logLevel = "loud"
+			_, err := logLevelFromFlags()
+			Ω(err).Should(MatchError(ContainSubstring(`unsupported --log-level "loud"`)))
+		})
+	})
+}
+
+func TestValidateLogFormat(t *testing.T) {
+	testutils.Run(t, "validateLogFormat", func(g *goblin.G) {
+		g.It("accepts console and json", func() {
+			Ω(validateLogFormat(logFormatConsole)).Should(Succeed())
+			Ω(validateLogFormat(logFormatJSON)).Should(Succeed())
+		})
+
+		g.It("rejects other formats", func() {
+			Ω(validateLogFormat("xml")).Should(MatchError(ContainSubstring(`unsupported --log-format "xml"`)))
+		})
+	})
+}
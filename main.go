@@ -5,19 +5,24 @@ import (
 	"bytes"
 	"compress/gzip"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"os/exec"
 	"path"
 	"path/filepath"
 	"strings"
+	"time"
 
-	semver2 "github.com/Masterminds/semver"
 	"github.com/alex-held/dfctl-kit/pkg/dflog"
 	"github.com/alex-held/dfctl-kit/pkg/env"
 	"github.com/alex-held/dfctl-kit/pkg/iostreams"
 	"github.com/alex-held/dfctl-kit/pkg/system"
+
+	"github.com/alex-held/dfctl-go/pkg/godist"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/afero"
@@ -35,34 +40,127 @@ const DownloadURL = "https://golang.org"
 
 var InstallPath = filepath.Join(env.SDKs(), "go")
 
+// stagingSuffix is appended to the version directory an archive is extracted into before it is moved into place
+const stagingSuffix = ".partial"
+
+var errIncompleteExtraction = errors.New("go sdk archive was not extracted completely")
+var errUnsafeArchiveEntry = errors.New("archive entry escapes the target directory")
+
 var errOnlyOsFsSupported = errors.New("only afero.OsFs is supported")
 var errNoCurrentVersion = errors.New("current version is not linked")
 var ErrVersionNotInstalled = errors.New("go version is not installed locally")
+var errVersionInUse = errors.New("go version is linked as current version")
 
 type executor struct {
 	afero.Fs
 	Streams     *iostreams.IOStreams
 	URL         string
 	InstallPath string
+	ConfigFile  string
+	// SystemConfigFile is the machine-wide config merged beneath ConfigFile
+	SystemConfigFile string
+	ShimPath         string
+	CachePath        string
+	// Offline restricts installs to archives in the cache
+	Offline bool
+	// Output is the output format selected with --output
+	Output string
+	// Format is the go template of --format printing each version, if set
+	Format string
+	// Constraint filters the versions printed by list, see satisfies
+	Constraint string
+	// Quiet suppresses informational messages, so only essential values are printed
+	Quiet bool
+	// Color enables colors like highlighting the current version
+	Color bool
+	// Porcelain selects the plain output formats, which stay stable across releases
+	Porcelain bool
+	// Force reinstalls versions which are already installed
+	Force bool
+	// Bootstrap pins the toolchain building go from source like the bootstrap key of the config, see resolveBootstrap
+	Bootstrap string
+
+	NoDeprecationWarnings bool
+	// Progress receives the progress of downloads and extractions, if set
+	Progress progressFunc
+	// Summary collects the outcome of mutating commands for --ci-summary, if set
+	Summary *ciSummary
+	// Ctx is cancelled when the command gets interrupted
+	Ctx context.Context
 }
 
 func defaultExecutor() *executor {
-	return &executor{
-		Fs:          afero.NewOsFs(),
-		Streams:     iostreams.Default(),
-		URL:         DownloadURL,
-		InstallPath: InstallPath,
+	e := &executor{
+		Fs:               afero.NewOsFs(),
+		Streams:          iostreams.Default(),
+		URL:              downloadURL(),
+		InstallPath:      InstallPath,
+		ConfigFile:       ConfigFile,
+		SystemConfigFile: SystemConfigFile,
+		ShimPath:         ShimPath,
+		CachePath:        CachePath,
+
+		Output:                outputFormat,
+		Quiet:                 quiet,
+		Porcelain:             porcelain,
+		NoDeprecationWarnings: noDeprecationWarnings,
+		Summary:               activeSummary,
+		Ctx:                   commandContext,
+	}
+	e.Color = colorEnabled(e.Streams.Out)
+	if isTerminal(e.Streams.Err) && !quiet && !porcelain {
+		e.Progress = progressRenderer(e.Streams.Err)
 	}
+	return e
 }
 
 func main() {
 	dflog.Configure()
 
+	ctx, stop := interruptible(context.Background())
 	cmd := NewCmd()
-	err := cmd.Execute()
-	if err != nil {
-		log.Fatal().Err(err).Send()
+	err := cmd.ExecuteContext(ctx)
+	// stop cancels ctx, so check for an interruption before
+	interrupted := ctx.Err() != nil
+	stop()
+	activeLock.release()
+	printCISummary(os.Stderr, err)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		// commands run by exec determine the exit code
+		os.Exit(exitErr.ExitCode())
+	}
+	if err == nil && !interrupted {
+		return
 	}
+	if errors.Is(err, errUnsatisfied) && outputFormat != outputJSON {
+		os.Exit(1)
+	}
+	if err == nil {
+		err = context.Canceled
+	}
+	code := 1
+	var bulkErr *bulkError
+	switch {
+	case interrupted:
+		code = exitInterrupted
+	case errors.As(err, &bulkErr):
+		code = bulkErr.ExitCode()
+	}
+	if outputFormat == outputJSON {
+		writeJSONError(os.Stdout, err)
+		os.Exit(code)
+	}
+	switch {
+	case interrupted:
+		log.Error().Err(err).Msg("interrupted")
+	case bulkErr != nil:
+		bulkErr.render(os.Stderr)
+		log.Error().Err(err).Send()
+	default:
+		log.Error().Err(err).Send()
+	}
+	os.Exit(code)
 }
 
 func NewCmd() *cobra.Command {
@@ -72,109 +170,490 @@ func NewCmd() *cobra.Command {
 		RunE: func(c *cobra.Command, args []string) error {
 			return c.Help()
 		},
-		Version: fmt.Sprintf("devctl-go version %v", version),
+		Version: version,
+		PersistentPreRunE: func(c *cobra.Command, args []string) error {
+			if err := validateOutputFormat(outputFormat); err != nil {
+				return err
+			}
+			if err := validateColorMode(colorMode); err != nil {
+				return err
+			}
+			if err := configureLogging(); err != nil {
+				return err
+			}
+			if outputFormat == outputJSON {
+				// failures are reported as json by main
+				c.Root().SilenceErrors, c.Root().SilenceUsage = true, true
+			}
+			commandContext = c.Context()
+			startCISummary(c)
+			return lockMutating(c)
+		},
 	}
 
+	cmd.SetVersionTemplate(versionTemplate())
+
+	var host string
+	cmd.PersistentFlags().BoolVar(&noDeprecationWarnings, "no-deprecation-warnings", false, "silence warnings about deprecated behavior")
+	cmd.PersistentFlags().BoolVar(&ciSummaryEnabled, "ci-summary", false, "print a summary line like RESULT=ok ACTION=install VERSION=1.22.2 DURATION=52s BYTES=142MB to stderr after mutating commands")
+	cmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", outputText, "output format; json prints list, current, install and uninstall as {schema_version, versions: [{version, path, current, size}], errors} "+
+		"and reports failures as {schema_version, code, message, details, remediation} on stdout")
+	cmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "only print essential values like versions and paths, and no log lines except errors")
+	cmd.PersistentFlags().BoolVar(&porcelain, "porcelain", false, "print the plain formats, which stay stable across releases, without colors, progress and tables, and log to stderr")
+	cmd.PersistentFlags().StringVar(&colorMode, "color", colorAuto, "colorize the output: auto colors terminals unless "+NoColorEnv+" is set, always or never")
+	cmd.PersistentFlags().CountVarP(&verbosity, "verbose", "v", "log debug output like urls, paths and decisions; -vv logs trace output")
+	cmd.PersistentFlags().StringVar(&logLevel, "log-level", "", "log level: trace, debug, info, warn or error; overrides --verbose and --quiet")
+	cmd.PersistentFlags().StringVar(&logFormat, "log-format", logFormatConsole, "log format: console prints readable lines, json one json object per line for log collectors")
+	cmd.PersistentFlags().StringVar(&logFile, "log-file", "", "also write the log lines as json to a file, which is rotated after log.max_size megabytes of the config; overrides log.file")
+	cmd.PersistentFlags().BoolVar(&noWait, "no-wait", false, "fail instead of waiting if another dfctl-go run holds the lock of the install root")
+	cmd.PersistentFlags().DurationVar(&lockTimeout, "lock-timeout", defaultLockTimeout, "time to wait for another dfctl-go run holding the lock of the install root")
+
+	var includeUnstable, offline, dryRun, force, use, installMissing, updateTip, fromSource bool
+	var fromFile, fromURL, sha256, kind, dest, bootstrap, goos, goarch string
 	installCmd := &cobra.Command{
-		Use:   "install",
-		Short: "installs the provided version of the go sdk",
+		Use:               "install [version...]",
+		ValidArgsFunction: completeRemoteVersions,
+		Short:             "installs the provided versions of the go sdk",
+		Long: "installs the provided version of the go sdk; the keywords latest and stable resolve to the newest release, partial versions like 1.21 and semver constraints like ^1.21 to the newest matching release and tip builds the development toolchain from the master branch with a bootstrap toolchain. Without version the version required by the go.mod of the working directory is installed, or picked from the releases in a terminal without go.mod. " +
+			"Multiple versions are downloaded and extracted concurrently; the exit code is 1 if all of them and 2 if some of them failed",
 		RunE: func(c *cobra.Command, args []string) error {
-			if err := validateArgsForSubcommand("install", args, 1); err != nil {
+			if err := validateInstallVersions(args, use, fromFile, fromURL, kind); err != nil {
 				return err
 			}
 			e := defaultExecutor()
-
-			version, err := ParseVersion(args[0])
+			if host != "" {
+				return e.onHost(c.Context(), host, c, args)
+			}
+			e.Offline = offline
+			e.Force = force
+			e.Bootstrap = bootstrap
+			if len(args) == 1 && strings.ToLower(args[0]) == KeywordTip {
+				if dryRun || fromFile != "" || fromURL != "" || kind != string(godist.KindArchive) {
+					return fmt.Errorf("--dry-run, --from-file, --from-url and --kind are not supported with tip")
+				}
+				if err := e.InstallTip(c.Context(), updateTip); err != nil || !use {
+					return err
+				}
+				return e.Use(TipVersion)
+			}
+			if updateTip {
+				return fmt.Errorf("--update is only supported with tip")
+			}
+			if fromSource && (dryRun || fromFile != "" || fromURL != "" || kind != string(godist.KindArchive) || len(args) > 1) {
+				return fmt.Errorf("--from-source only builds a single version and is not supported with --dry-run, --from-file, --from-url and --kind")
+			}
+			if goos != "" || goarch != "" {
+				if dryRun || use || fromSource || fromFile != "" || fromURL != "" || kind != string(godist.KindArchive) || len(args) > 1 {
+					return fmt.Errorf("--os and --arch only extract a single version and are not supported with --dry-run, --use, --from-source, --from-file, --from-url and --kind")
+				}
+				if !c.Flags().Changed("dest") {
+					return fmt.Errorf("--os and --arch require --dest, the directory the go sdk is extracted into")
+				}
+			}
+			if dryRun && (fromFile != "" || fromURL != "" || kind != string(godist.KindArchive)) {
+				return fmt.Errorf("--dry-run is not supported with --from-file, --from-url and --kind")
+			}
+			if use && (fromFile != "" || fromURL != "" || kind != string(godist.KindArchive)) {
+				return fmt.Errorf("--use is not supported with --from-file, --from-url and --kind")
+			}
+			if fromFile != "" {
+				return e.InstallFromFile(fromFile, args)
+			}
+			if fromURL != "" {
+				return e.InstallFromURL(c.Context(), fromURL, sha256, args)
+			}
+			if len(args) > 1 && dryRun {
+				return e.PlanInstallVersions(c.Context(), args, includeUnstable)
+			}
+			if len(args) > 1 {
+				return e.InstallVersions(c.Context(), args, includeUnstable)
+			}
+			arg, err := e.versionArgOrPick(c.Context(), args, true)
 			if err != nil {
 				return err
 			}
-			return e.Install(version)
+			version, err := e.resolveVersion(c.Context(), arg, remoteScope, includeUnstable)
+			if err != nil {
+				return err
+			}
+			if dryRun && use {
+				return e.PlanInstallAndUse(version)
+			}
+			if dryRun {
+				return e.PlanInstall(version)
+			}
+			if kind != string(godist.KindArchive) {
+				return e.DownloadInstaller(c.Context(), version, kind, dest)
+			}
+			if goos != "" || goarch != "" {
+				return e.InstallPlatform(c.Context(), version, platformRuntimeInfo(goos, goarch), dest)
+			}
+			started := time.Now()
+			install := e.Install
+			if use {
+				install = e.InstallAndUse
+			}
+			if fromSource {
+				install = func(v Version) error {
+					if err := e.InstallFromSource(c.Context(), v); err != nil || !use {
+						return err
+					}
+					return e.Use(v)
+				}
+			}
+			if err = install(version); err != nil {
+				return err
+			}
+			e.notifyFinished(fmt.Sprintf("installed go %s", version), started)
+			if e.Output == outputJSON {
+				return e.writeInstalled(version)
+			}
+			return nil
 		},
 	}
+	addHostFlag(installCmd, &host)
+	installCmd.Flags().BoolVar(&includeUnstable, "include-unstable", false, "let the latest keyword resolve to beta and rc releases")
+	addDryRunFlag(installCmd, &dryRun)
+	installCmd.Flags().BoolVar(&force, "force", false, "reinstall the version from a fresh download if it is already installed")
+	installCmd.Flags().BoolVar(&updateTip, "update", false, "pull the newest commit into the installed tip and rebuild it")
+	installCmd.Flags().BoolVar(&fromSource, "from-source", false, "build the version from its source archive with a bootstrap toolchain, e.g. on platforms without official binary archives")
+	installCmd.Flags().StringVar(&bootstrap, "bootstrap", "", "version or GOROOT of the go sdk building tip and --from-source, overriding the bootstrap key of the config; by default a suitable installed sdk is used or the bootstrap toolchain is installed")
+	installCmd.Flags().BoolVar(&use, "use", false, "make the version the current version after installing it")
+	installCmd.Flags().BoolVar(&offline, "offline", false, "only install archives from the cache without accessing the network")
+	installCmd.Flags().StringVar(&fromFile, "from-file", "", "install a local archive, e.g. go1.22.1.linux-amd64.tar.gz; the version is taken from the file name unless passed as argument")
+	installCmd.Flags().StringVar(&fromURL, "from-url", "", "install the archive at the url, e.g. https://artifacts.corp/go/go1.22.1.linux-amd64.tar.gz; the version is taken from the file name unless passed as argument")
+	installCmd.Flags().StringVar(&kind, "kind", string(godist.KindArchive), "kind of artifact; archive installs the sdk, pkg and msi only download the official installer package into --dest")
+	installCmd.Flags().StringVar(&dest, "dest", ".", "directory the installer package of --kind is downloaded to, or the go sdk of --os and --arch is extracted into")
+	installCmd.Flags().StringVar(&goos, "os", "", "extract the go sdk of another operating system like linux into --dest instead of installing it for the host")
+	installCmd.Flags().StringVar(&goarch, "arch", "", "extract the go sdk of another architecture like arm64 into --dest instead of installing it for the host")
+	installCmd.Flags().StringVar(&sha256, "sha256", "", "expected hex encoded sha256 checksum of the archive passed with --from-url")
+
+	var previous bool
 	useCmd := &cobra.Command{
-		Use:   "use",
-		Short: "sets a go sdk version as the system default",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := validateArgsForSubcommand("use", args, 1); err != nil {
+		Use:               "use [version|-|system]",
+		ValidArgsFunction: completeUsableVersion,
+		Short:             "sets a go sdk version as the system default",
+		Long: "sets a go sdk version as the system default; the keywords latest and stable resolve to the newest release, partial versions like 1.21 to the newest installed patch release. Without version the version required by the go.mod of the working directory is used, or picked from the installed versions in a terminal without go.mod. " +
+			"Like cd -, use - switches back to the previously used version and use system removes the current version, so the shims run the go installed by the operating system. Missing versions are installed first with --install or auto_install: true in the config",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateMaxArgsForSubcommand("use", args, 1); err != nil {
 				return err
 			}
 			e := defaultExecutor()
-			version, err := ParseVersion(args[0])
+			if host != "" {
+				return e.onHost(c.Context(), host, c, args)
+			}
+			if dryRun && (previous || len(args) == 1 && args[0] == previousArg) {
+				return fmt.Errorf("--dry-run is not supported with --previous")
+			}
+			if previous || len(args) == 1 && args[0] == previousArg {
+				return e.UsePrevious()
+			}
+			arg, err := e.versionArgOrPick(c.Context(), args, false)
+			if err != nil {
+				return err
+			}
+			autoInstall := e.autoInstall(installMissing)
+			version, err := e.resolveUseVersion(c.Context(), arg, includeUnstable, autoInstall)
 			if err != nil {
 				return err
 			}
+			if dryRun && autoInstall && e.missing(version) {
+				return e.PlanInstallAndUse(version)
+			}
+			if dryRun {
+				return e.PlanUse(version)
+			}
+			if wd, err := os.Getwd(); err == nil {
+				if _, _, err = findProjectPin(e.Fs, wd); err == nil {
+					e.deprecated(deprecationUseGlobalScope)
+				}
+			}
+			if autoInstall {
+				return e.UseInstalling(version)
+			}
 			return e.Use(version)
 		},
 	}
+	addHostFlag(useCmd, &host)
+	useCmd.Flags().BoolVar(&includeUnstable, "include-unstable", false, "let the latest keyword resolve to beta and rc releases")
+	useCmd.Flags().BoolVar(&previous, "previous", false, "switch back to the previously used version, same as use -")
+	addDryRunFlag(useCmd, &dryRun)
+	useCmd.Flags().BoolVar(&installMissing, "install", false, "install the version first if it is not installed; auto_install: true in the config enables this by default")
 
+	var allExceptCurrent bool
+	uninstallCmd := &cobra.Command{
+		Use:               "uninstall <version>",
+		ValidArgsFunction: completeInstalledVersion,
+		Short:             "removes an installed go sdk",
+		Long:              "removes an installed go sdk; partial versions like 1.21 resolve to the newest installed patch release",
+		RunE: func(c *cobra.Command, args []string) error {
+			e := defaultExecutor()
+			if allExceptCurrent {
+				if err := validateArgsForSubcommand("uninstall --all-except-current", args, 0); err != nil {
+					return err
+				}
+				if dryRun {
+					return e.PlanUninstallAllExceptCurrent()
+				}
+				return e.UninstallAllExceptCurrent()
+			}
+			if err := validateArgsForSubcommand("uninstall", args, 1); err != nil {
+				return err
+			}
+			version, err := e.resolveVersion(c.Context(), args[0], installedScope, false)
+			if err != nil {
+				return err
+			}
+			if dryRun {
+				return e.PlanUninstall(version)
+			}
+			return e.Uninstall(version)
+		},
+	}
+	uninstallCmd.Flags().BoolVar(&allExceptCurrent, "all-except-current", false, "remove every installed version except the current version")
+	addDryRunFlag(uninstallCmd, &dryRun)
+
+	var format string
+	var plain, all bool
+	var constraint string
 	listCmd := &cobra.Command{
 		Use:   "list",
 		Short: "lists installed go sdks",
+		Long: "lists the installed go sdks as a table with their install date, size on disk, whether they are the current version and newer patch releases; " +
+			"the plain format prints one version per line and is used with --plain or if stdout is no terminal",
 		RunE: func(c *cobra.Command, args []string) error {
 			if err := validateArgsForSubcommand("list", args, 0); err != nil {
 				return err
 			}
 			e := defaultExecutor()
+			e.Format = format
+			e.Constraint = constraint
+			if _, err := e.matches("1.0.0"); err != nil {
+				return err
+			}
+			plain = plain || e.Quiet || e.Porcelain
+			if all {
+				return e.ListAll(c.Context(), !plain)
+			}
+			if !plain && e.Format == "" && e.Output != outputJSON && isTerminal(e.Streams.Out) {
+				return e.ListTable(c.Context())
+			}
 			return e.List()
 		},
 	}
+	listCmd.Flags().BoolVar(&plain, "plain", false, "print one version per line")
+	listCmd.Flags().BoolVar(&all, "all", false, "merge the stable releases of the release feed into the installed versions")
+	listCmd.Flags().StringVar(&constraint, "constraint", "", "only list versions satisfying a semver constraint like '>=1.21', a partial version or a channel")
 
+	var verifyPin bool
 	currentCmd := &cobra.Command{
 		Use:   "current",
-		Short: "prints the currently installed go version",
+		Short: "prints the go version in effect for the working directory",
 		RunE: func(c *cobra.Command, args []string) error {
 			if err := validateArgsForSubcommand("current", args, 0); err != nil {
 				return err
 			}
 			e := defaultExecutor()
+			e.Format = format
+			if verifyPin {
+				wd, err := os.Getwd()
+				if err != nil {
+					return err
+				}
+				return e.VerifyPin(wd)
+			}
 			return e.Current()
 		},
 	}
+	addFormatFlag(listCmd, &format, versionEntryFields)
+	addFormatFlag(currentCmd, &format, versionEntryFields)
+	currentCmd.Flags().BoolVar(&verifyPin, "verify-pin", false, "fail if the active version drifts from the project pin of the working directory")
 
 	cmd.AddCommand(currentCmd)
 	cmd.AddCommand(listCmd)
-	cmd.AddCommand(installCmd)
-	cmd.AddCommand(useCmd)
+	cmd.AddCommand(mutating(installCmd))
+	cmd.AddCommand(mutating(useCmd))
+	cmd.AddCommand(mutating(uninstallCmd))
+	cmd.AddCommand(mutating(newUpgradeCmd()))
+	cmd.AddCommand(newGenerateCmd())
+	cmd.AddCommand(mutating(newNormalizeCmd()))
+	cmd.AddCommand(mutating(newLocalCmd()))
+	cmd.AddCommand(mutating(newGlobalCmd()))
+	cmd.AddCommand(newDeprecationsCmd())
+	cmd.AddCommand(newStatusCmd())
+	cmd.AddCommand(newMirrorCmd())
+	cmd.AddCommand(newHookCmd())
+	cmd.AddCommand(newPlumbingCmd())
+	cmd.AddCommand(newEnvCmd())
+	cmd.AddCommand(newExecCmd())
+	cmd.AddCommand(mutating(newRehashCmd()))
+	cmd.AddCommand(newConfigCmd())
+	cmd.AddCommand(newDoctorCmd())
+	cmd.AddCommand(newSelfUpdateCmd())
+	cmd.AddCommand(mutating(newFsckCmd()))
+	cmd.AddCommand(mutating(newPruneCmd()))
+	cmd.AddCommand(newCacheCmd())
+	cmd.AddCommand(newCompletionCmd())
+	cmd.AddCommand(newNewProjectCmd())
+	cmd.AddCommand(newVerifyCmd())
+	cmd.AddCommand(newOutdatedCmd())
+	cmd.AddCommand(newInfoCmd())
+	cmd.AddCommand(mutating(newNukeCmd()))
+	cmd.AddCommand(newWatchCmd())
+	cmd.AddCommand(mutating(newRepairCmd()))
+	cmd.AddCommand(newSandboxExtractCmd())
+	cmd.AddCommand(newEmptyTrashCmd())
+	cmd.AddCommand(newAliasCmd())
+	cmd.AddCommand(newBootstrapCmd())
+	cmd.AddCommand(newSatisfiesCmd())
+	cmd.AddCommand(newHistoryCmd())
+	cmd.AddCommand(newDocsCmd())
+	cmd.AddCommand(newVersionCmd())
+	cmd.AddCommand(newPathCmd())
+	cmd.AddCommand(newWhichCmd())
+	cmd.AddCommand(mutating(newAdoptCmd()))
+	cmd.AddCommand(mutating(newMigrateCmd()))
 
 	return cmd
 }
 
-func MustParseVersion(s string) Version {
-	v, err := ParseVersion(s)
+// Install downloads and installs version; installed versions are skipped unless Force is set,
+// which reinstalls them from a fresh download
+func (e *executor) Install(version Version) error {
+	e.Summary.addVersion(version)
+	existing, err := e.versionPath(version)
+	if err == nil && !e.Force {
+		e.infof("go %s is already installed in %s; pass --force to reinstall it\n", version, existing)
+		return nil
+	}
+	archive, err := e.dlArchive(version)
 	if err != nil {
-		panic(err)
+		return err
 	}
-	return v
+	if err = e.installArchive(version, archive, e.artifactURL(system.OSRuntimeInfoGetter{}.Get(), version)); err != nil {
+		return err
+	}
+	// a differently spelled directory like v1.22.1 is replaced by the canonical one
+	if existing != "" && existing != filepath.Join(e.InstallPath, version.String()) {
+		return e.removeVersionDir(version.String(), existing)
+	}
+	return nil
 }
-func ParseVersion(s string) (Version, error) {
-	v, err := semver2.NewVersion(s)
-	if err != nil {
-		return "", err
+
+// InstallAndUse installs version like Install and makes it the current version, reporting both steps
+func (e *executor) InstallAndUse(version Version) error {
+	if err := e.installReported(version); err != nil {
+		return err
 	}
-	return Version(v.String()), nil
+	if err := e.Use(version); err != nil {
+		return fmt.Errorf("installed go %s but failed to link it as current; %w", version, err)
+	}
+	e.infof("using go %s\n", version)
+	return nil
 }
 
-func (e *executor) Install(version Version) error {
-	installPath := path.Join(e.InstallPath, version.String())
-	archive, err := e.dlArchive(version)
-	if err != nil {
+// installReported installs version like Install and reports its success, which is implicit when installing a single version
+func (e *executor) installReported(version Version) error {
+	_, err := e.versionPath(version)
+	installed := err == nil
+	if err = e.Install(version); err != nil {
 		return err
 	}
+	if !installed {
+		e.infof("installed go %s\n", version)
+	}
+	return nil
+}
+
+// installArchive extracts the archive of version into its version directory and records source in its manifest
+func (e *executor) installArchive(version Version, archive *bytes.Buffer, source string) error {
+	if err := e.installArchiveInto(version, archive, source, path.Join(e.InstallPath, version.String())); err != nil {
+		return err
+	}
+	e.record(historyEntry{Op: historyInstall, Version: version, Source: source})
+	return nil
+}
+
+// installArchiveInto extracts the archive of version into installPath and records source in its manifest.
+// The archive is extracted into a staging directory next to installPath, which replaces installPath only after
+// the extraction got verified, so an interrupted install never leaves a half-populated version directory behind.
+func (e *executor) installArchiveInto(version Version, archive *bytes.Buffer, source, installPath string) error {
+	log.Debug().Str("path", installPath).Str("source", source).Msgf("installing go %s", version)
+	m := installManifest{Version: version, Source: source, InstalledAt: time.Now().UTC(), InstalledBy: hostFingerprint()}
+	data := archive.Bytes()
+	sum := sha256.Sum256(data)
+	m.SHA256 = hex.EncodeToString(sum[:])
+	ri := system.OSRuntimeInfoGetter{}.Get()
+	if _, archived, err := parseArchiveName(path.Base(source)); err == nil {
+		ri = archived
+	}
+	m.OS, m.Arch = ri.OS, ri.Arch
 
-	log.Debug().Msgf("downloaded %v to path %v", version.String(), installPath)
-	err = e.Fs.MkdirAll(installPath, os.ModePerm)
+	staging := installPath + stagingSuffix
+	log.Debug().Msgf("extracting %v to staging path %v", version.String(), staging)
+	if err := e.Fs.RemoveAll(staging); err != nil {
+		return fmt.Errorf("failed to remove the leftover staging directory %s; %w", staging, err)
+	}
+	err := e.Fs.MkdirAll(staging, os.ModePerm)
 	if err != nil {
-		return fmt.Errorf("failed to create install directory at %s; %w", installPath, err)
+		return fmt.Errorf("failed to create install directory at %s; %w", staging, err)
 	}
-	err = unTarGzip(archive, installPath, unarchiveRenamer(), e.Fs)
+	defer func() {
+		_ = e.Fs.RemoveAll(staging)
+	}()
+	err = e.extractArchive(fmt.Sprintf("go %s", version), archive, staging)
 	if err != nil {
-		return fmt.Errorf("failed to Extract go sdk %s; dest=%s; archive=%s;err=%v\n", version, installPath, "*Bytes.Buffer", err)
+		return fmt.Errorf("failed to Extract go sdk %s; dest=%s; archive=%s;err=%v\n", version, staging, "*Bytes.Buffer", err)
+	}
+	if m.Checksums, err = criticalFiles(e.Fs, staging); err != nil {
+		return fmt.Errorf("failed to checksum go sdk %s; err=%v", version, err)
+	}
+	if m.Files, err = countFiles(e.Fs, staging); err != nil {
+		return fmt.Errorf("failed to count the files of go sdk %s; err=%v", version, err)
+	}
+	if expected, err := archiveFileCount(data); err != nil || expected != m.Files {
+		return fmt.Errorf("%w; version=%s; extracted %d of %d files; err=%v", errIncompleteExtraction, version, m.Files, expected, err)
+	}
+	if err = e.writeManifest(staging, m); err != nil {
+		return err
+	}
+
+	if err = e.Fs.RemoveAll(installPath); err != nil {
+		return fmt.Errorf("failed to replace %s; %w", installPath, err)
+	}
+	if err = e.Fs.Rename(staging, installPath); err != nil {
+		return fmt.Errorf("failed to move go sdk %s into place at %s; %w", version, installPath, err)
 	}
+	e.rehashIfEnabled()
 	return nil
 }
 
+// archiveFileCount returns the number of regular files in the sdk archive
+func archiveFileCount(data []byte) (files int, err error) {
+	r, err := tarStream(bytes.NewBuffer(data))
+	if err != nil {
+		return 0, err
+	}
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return files, nil
+		} else if err != nil {
+			return 0, err
+		}
+		if header.Typeflag == tar.TypeReg {
+			files++
+		}
+	}
+}
+
 func (e *executor) Use(version Version) error {
-	versionPath := filepath.Join(e.InstallPath, version.String())
+	if version == SystemVersion {
+		return e.UseSystem()
+	}
+	e.Summary.addVersion(version)
 	currentPath := filepath.Join(e.InstallPath, "current")
 
 	osFs, ok := e.Fs.(*afero.OsFs)
@@ -182,25 +661,85 @@ func (e *executor) Use(version Version) error {
 		return errOnlyOsFsSupported
 	}
 
-	if exists, err := afero.DirExists(osFs, versionPath); err != nil || !exists {
-		return ErrVersionNotInstalled
+	var previous string
+	current, _ := e.current()
+	if current != "" {
+		previous, _ = e.goroot(current)
+	}
+	versionPath, err := e.versionPath(version)
+	if err != nil {
+		if ext, ok := e.external(version); ok {
+			if err = e.useExternal(ext); err != nil {
+				return err
+			}
+			e.recordPrevious(current, version)
+			e.record(historyEntry{Op: historyUse, Version: version, Previous: current, Source: ext.Root})
+			e.postSwitch(previous, ext.Root)
+			e.warnShadowing()
+			return nil
+		}
+		return err
+	}
+	if m, err := e.readManifest(versionPath); err == nil && m.foreign() {
+		log.Warn().Msg(m.foreignWarning())
 	}
 
-	_ = osFs.Remove(currentPath)
-	if err := osFs.SymlinkIfPossible(versionPath, currentPath); err != nil {
+	log.Debug().Str("target", versionPath).Str("link", currentPath).Msg("switching the current version")
+	if err := swapSymlink(osFs, versionPath, currentPath); err != nil {
 		return err
 	}
+	_ = osFs.Remove(filepath.Join(e.InstallPath, externalMarker))
+	_ = osFs.Remove(filepath.Join(e.InstallPath, systemMarker))
+	e.recordPrevious(current, version)
+	e.record(historyEntry{Op: historyUse, Version: version, Previous: current})
+	e.postSwitch(previous, versionPath)
+	e.warnShadowing()
 	return nil
 }
 
-type Version string
-
-func (v Version) Number() string {
-	return strings.TrimPrefix(string(v), "v")
+// swapSymlink atomically points link to target by renaming a new link over it,
+// so link never is missing and keeps its old target if the new link cannot be created
+func swapSymlink(fs *afero.OsFs, target, link string) error {
+	staging := link + stagingSuffix
+	_ = fs.Remove(staging)
+	if err := fs.SymlinkIfPossible(target, staging); err != nil {
+		return err
+	}
+	if err := fs.Rename(staging, link); err != nil {
+		_ = fs.Remove(staging)
+		return fmt.Errorf("failed to link %s to %s; err=%v", link, target, err)
+	}
+	return nil
 }
 
-func (v Version) String() string {
-	return string(v)
+func (e *executor) Uninstall(version Version) error {
+	e.Summary.addVersion(version)
+	versionPath, err := e.versionPath(version)
+	if err != nil {
+		if ext, ok := e.external(version); ok {
+			return fmt.Errorf("%w; version=%s; root=%s", errExternalVersion, version, ext.Root)
+		}
+		return err
+	}
+	if current, err := e.current(); err == nil && current.Compare(version) == 0 {
+		return errVersionInUse
+	}
+	var info versionEntry
+	var errs []error
+	if e.Output == outputJSON {
+		// the size is gone after the removal
+		if info, err = e.describe(version, versionPath, false); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err = e.removeVersionDir(version.String(), versionPath); err != nil {
+		return err
+	}
+	e.record(historyEntry{Op: historyUninstall, Version: version})
+	if e.Output == outputJSON {
+		return e.writeVersions([]versionEntry{info}, errs)
+	}
+	return nil
 }
 
 func (e *executor) list() (versions []Version, err error) {
@@ -209,7 +748,8 @@ func (e *executor) list() (versions []Version, err error) {
 		return versions, err
 	}
 	for _, fi := range fis {
-		if fi.IsDir() {
+		// hidden directories like the trash hold no installs
+		if fi.IsDir() && !strings.HasPrefix(fi.Name(), ".") {
 			versions = append(versions, Version(fi.Name()))
 		}
 	}
@@ -217,14 +757,88 @@ func (e *executor) list() (versions []Version, err error) {
 	return versions, nil
 }
 
+// installation is an installed go sdk
+type installation struct {
+	// Version is the canonical version of the sdk
+	Version Version
+	// Dir is the name of the install directory, which may be spelled differently by other tools, e.g. v1.22.2
+	Dir string
+}
+
+// installations returns the installed go sdks normalized to their canonical version.
+// Directories which are no versions are skipped; multiple directories of the same release are reported.
+func (e *executor) installations() (installs []installation, err error) {
+	dirs, err := e.list()
+	if err != nil {
+		return nil, err
+	}
+	seen := map[Version]string{}
+	for _, dir := range dirs {
+		version, err := ParseVersion(dir.String())
+		if err != nil {
+			continue
+		}
+		if other, ok := seen[version]; ok {
+			log.Warn().Msgf("go %s is installed twice in %s and %s; run 'dfctl-go normalize' to clean up", version, other, dir)
+		}
+		seen[version] = dir.String()
+		installs = append(installs, installation{Version: version, Dir: dir.String()})
+	}
+	return installs, nil
+}
+
+// versionPath returns the install directory of version, preferring the canonical directory over differently spelled ones
+func (e *executor) versionPath(version Version) (string, error) {
+	canonical := filepath.Join(e.InstallPath, version.String())
+	if exists, err := afero.DirExists(e.Fs, canonical); err == nil && exists {
+		return canonical, nil
+	}
+	installs, err := e.installations()
+	if err != nil {
+		return "", ErrVersionNotInstalled
+	}
+	for _, i := range installs {
+		if i.Version.Compare(version) == 0 {
+			return filepath.Join(e.InstallPath, i.Dir), nil
+		}
+	}
+	return "", ErrVersionNotInstalled
+}
+
 func (e *executor) List() error {
-	versions, err := e.list()
+	if e.Output == outputJSON || e.Format != "" {
+		infos, errs, err := e.listEntries()
+		if err != nil {
+			return err
+		}
+		return e.writeVersions(infos, errs)
+	}
+	installs, err := e.installations()
 	if err != nil {
 		return err
 	}
+	// directories which are no versions are skipped by installations
+	var versions []Version
+	seen := map[Version]bool{}
+	for _, i := range installs {
+		ok, err := e.matches(i.Version)
+		if err != nil {
+			return err
+		}
+		if ok && !seen[i.Version] {
+			seen[i.Version] = true
+			versions = append(versions, i.Version)
+		}
+	}
+	sortVersions(versions)
 	for _, version := range versions {
 		_, _ = fmt.Fprintln(e.Streams.Out, version.String())
 	}
+	for _, ext := range e.externals() {
+		if ok, _ := e.matches(ext.Version); ok {
+			_, _ = fmt.Fprintf(e.Streams.Out, "%s (external %s)\n", ext.Version, ext.Root)
+		}
+	}
 	return nil
 }
 
@@ -237,10 +851,14 @@ func (e *executor) current() (Version, error) {
 
 	link, err := osFs.ReadlinkIfPossible(installPath)
 	if err != nil {
-		return Version(""), errNoCurrentVersion
+		ext, err := e.currentExternal()
+		return ext.Version, err
 	}
 
 	currentDir := path.Base(link)
+	if currentDir == KeywordTip {
+		return TipVersion, nil
+	}
 	currentVersion, err := ParseVersion(currentDir)
 	if err != nil {
 		return Version(""), err
@@ -248,11 +866,23 @@ func (e *executor) current() (Version, error) {
 	return currentVersion, nil
 }
 
+// Current prints the version in effect for the working directory, which is either pinned by the project or the global version
 func (e *executor) Current() error {
-	currentVersion, err := e.current()
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	currentVersion, _, err := e.resolved(wd)
 	if err != nil {
 		return err
 	}
+	if e.Output == outputJSON || e.Format != "" {
+		return e.writeInstalled(currentVersion)
+	}
+	if e.Quiet || e.Porcelain {
+		_, _ = fmt.Fprintln(e.Streams.Out, currentVersion.String())
+		return nil
+	}
 	_, _ = fmt.Fprintf(e.Streams.Out, currentVersion.String())
 	return nil
 }
@@ -264,25 +894,59 @@ func validateArgsForSubcommand(subcmd string, args []string, expected int) error
 	return nil
 }
 
+func validateMaxArgsForSubcommand(subcmd string, args []string, max int) error {
+	if len(args) > max {
+		return fmt.Errorf("provided too many arguments for subcommand '%s'; max=%d; provided=%d", subcmd, max, len(args))
+	}
+	return nil
+}
+
 func formatGoArchiveArtifactName(ri system.RuntimeInfo, version string) string {
-	return ri.Format("go%s.[os]-[arch].tar.gz", version)
+	// archives are published for every platform
+	name, _ := godist.ArtifactName(version, ri.OS, ri.Arch, godist.KindArchive)
+	return name
+}
+
+// artifactURL returns the download url of the archive of version for the platform
+func (e *executor) artifactURL(ri system.RuntimeInfo, version Version) string {
+	return fmt.Sprintf("%s/dl/%s", e.URL, formatGoArchiveArtifactName(ri, version.GoName()))
 }
 
 func (e *executor) dlArchive(version Version) (archive *bytes.Buffer, err error) {
-	ri := system.OSRuntimeInfoGetter{}
-	artifactName := formatGoArchiveArtifactName(ri.Get(), version.String())
-	dlUri := ri.Get().Format("%s/dl/%s", e.URL, artifactName)
+	ri := system.OSRuntimeInfoGetter{}.Get()
+	if e.Offline {
+		return e.cachedArchiveOf(version, ri)
+	}
+	dlUri := e.artifactURL(ri, version)
+	f, err := e.releaseFile(e.Ctx, path.Base(dlUri))
+	if err == nil && f.SHA256 != "" {
+		archive, err = e.dlCachedArchive(e.Ctx, f)
+		if err != nil {
+			return nil, fmt.Errorf("failed downloading go sdk %v from the remote server %s; err=%w", version, e.URL, err)
+		}
+		return archive, nil
+	}
+	if archive, cerr := e.cachedArchiveOf(version, ri); cerr == nil {
+		log.Warn().Err(err).Msgf("release feed of %s is unavailable; installing go %s from the cache", e.URL, version)
+		return archive, nil
+	}
 
 	buf := &bytes.Buffer{}
-	err = e.download(context.Background(), dlUri, buf)
+	err = e.downloadWithProgress(e.Ctx, dlUri, buf, path.Base(dlUri))
 	if err != nil {
-		return buf, fmt.Errorf("failed downloading go sdk %v from the remote server %s; err=%v", version, "https://golang.org", err)
+		return buf, fmt.Errorf("failed downloading go sdk %v from the remote server %s; err=%v", version, e.URL, err)
 	}
 
 	return buf, nil
 }
 
 func (e *executor) download(ctx context.Context, url string, outWriter io.Writer) (err error) {
+	return e.downloadWithProgress(ctx, url, outWriter, "")
+}
+
+// downloadWithProgress downloads url to outWriter and reports the progress of subject unless it is empty
+func (e *executor) downloadWithProgress(ctx context.Context, url string, outWriter io.Writer, subject string) (err error) {
+	log.Debug().Str("url", url).Msg("downloading")
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
 	if err != nil {
 		return err
@@ -293,13 +957,63 @@ func (e *executor) download(ctx context.Context, url string, outWriter io.Writer
 	}
 	defer resp.Body.Close()
 
-	_, err = io.Copy(outWriter, resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response status %s for %s", resp.Status, url)
+	}
+
+	if subject == "" {
+		_, err = io.Copy(outWriter, resp.Body)
+		return err
+	}
+	pw := &progressWriter{
+		Writer: outWriter,
+		ev:     progressEvent{Phase: phaseDownload, Subject: subject, TotalBytes: resp.ContentLength},
+		report: e.progress,
+	}
+	n, err := io.Copy(pw, resp.Body)
+	e.Summary.addBytes(n)
+	pw.ev.Done = true
+	e.progress(pw.ev)
+	return err
+}
+
+// extract extracts the .tar.gz or plain .tar archive into target and reports the progress of subject;
+// the extraction stops once e.Ctx is cancelled
+func (e *executor) extract(subject string, archive *bytes.Buffer, target string) error {
+	if e.Progress == nil {
+		return untar(archive, target, unarchiveRenamer(), e.Fs, func(*tar.Header) error {
+			return e.Ctx.Err()
+		})
+	}
+	ev := progressEvent{Phase: phaseExtract, Subject: subject, TotalBytes: -1}
+	if files, size, err := tarGzipStats(archive.Bytes()); err == nil {
+		ev.TotalFiles, ev.TotalBytes = files, size
+	}
+	err := untar(archive, target, unarchiveRenamer(), e.Fs, func(header *tar.Header) error {
+		ev.Files++
+		if header.Typeflag == tar.TypeReg {
+			ev.Bytes += header.Size
+		}
+		e.progress(ev)
+		return e.Ctx.Err()
+	})
+	ev.Done = true
+	e.progress(ev)
 	return err
 }
 
 func unTarGzip(buf *bytes.Buffer, target string, renamer Renamer, fs afero.Fs) error {
-	gr, _ := gzip.NewReader(buf)
-	tr := tar.NewReader(gr)
+	return untar(buf, target, renamer, fs, nil)
+}
+
+// untar extracts the .tar.gz or plain .tar archive into target, calling onEntry after every extracted entry if set;
+// an error returned by onEntry stops the extraction
+func untar(buf *bytes.Buffer, target string, renamer Renamer, fs afero.Fs, onEntry func(header *tar.Header) error) error {
+	r, err := tarStream(buf)
+	if err != nil {
+		return err
+	}
+	tr := tar.NewReader(r)
 
 	for {
 		header, err := tr.Next()
@@ -316,28 +1030,44 @@ func unTarGzip(buf *bytes.Buffer, target string, renamer Renamer, fs afero.Fs) e
 		}
 
 		p := filepath.Join(target, filename)
+		if rel, err := filepath.Rel(target, p); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("%w; entry=%s", errUnsafeArchiveEntry, header.Name)
+		}
 		fi := header.FileInfo()
 
 		if fi.IsDir() {
 			if e := fs.MkdirAll(p, fi.Mode()); e != nil {
 				return e
 			}
-			continue
-		}
-		file, err := fs.OpenFile(p, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, fi.Mode())
-		if err != nil {
-			return err
-		}
+		} else {
+			file, err := fs.OpenFile(p, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, fi.Mode())
+			if err != nil {
+				return err
+			}
 
-		_, err = io.Copy(file, tr)
-		file.Close()
-		if err != nil {
-			return err
+			_, err = io.Copy(file, tr)
+			file.Close()
+			if err != nil {
+				return err
+			}
+		}
+		if onEntry != nil {
+			if err = onEntry(header); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
 }
 
+// tarStream returns the tar stream of the archive, which is decompressed if it is gzipped
+func tarStream(r *bytes.Buffer) (io.Reader, error) {
+	if bytes.HasPrefix(r.Bytes(), []byte{0x1f, 0x8b}) {
+		return gzip.NewReader(r)
+	}
+	return r, nil
+}
+
 type Renamer func(p string) string
 
 func unarchiveRenamer() Renamer {
@@ -3,6 +3,7 @@ package main
 import (
 	"bytes"
 	_ "embed"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -12,6 +13,8 @@ import (
 	"github.com/franela/goblin"
 	. "github.com/onsi/gomega"
 	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 //go:embed testdata/go.tar.gz
@@ -19,6 +22,8 @@ var archiveData []byte
 
 func installPath(t *testing.T) string {
 	path := filepath.Join(testutils.TempDir(t), "dfctl", "sdk", "go")
+	// keep downloads of the tests out of the cache of the user
+	CachePath = filepath.Join(testutils.TempDir(t), "dfctl", "cache", "go")
 	return path
 }
 
@@ -101,13 +106,19 @@ func TestHandleCurrent(t *testing.T) {
 
 	testutils.Run(t, "List", func(g *goblin.G) {
 		InstallPath = installPath(t)
+		wd, _ := os.Getwd()
+		outside := testutils.TempDir(t, "outside")
 
 		g.BeforeEach(func() {
 			createVersionDirs()
+			// outside of modules and projects the global version is in effect
+			_ = os.MkdirAll(outside, os.ModePerm)
+			_ = os.Chdir(outside)
 		})
 
 		g.AfterEach(func() {
 			_ = afero.NewOsFs().RemoveAll(InstallPath)
+			_ = os.Chdir(wd)
 		})
 
 		g.Describe("with linked current version", func() {
@@ -187,6 +198,92 @@ func TestHandleInstall(t *testing.T) {
 	})
 }
 
+func TestInstallForce(t *testing.T) {
+	testutils.Run(t, "Install --force", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		server := newReleaseServer("1.22.1")
+		var out *Buffer
+		var sut *executor
+
+		g.BeforeEach(func() {
+			_ = os.MkdirAll(filepath.Join(InstallPath, "v1.22.1"), os.ModePerm)
+			_ = os.WriteFile(filepath.Join(InstallPath, "v1.22.1", "stale"), []byte{}, 0644)
+			out = &Buffer{&bytes.Buffer{}}
+			sut = defaultExecutor()
+			sut.URL = server.URL
+			sut.Streams.Out = out
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+		})
+
+		g.After(func() {
+			server.Close()
+		})
+
+		g.It("skips installed versions", func() {
+			Ω(sut.Install("1.22.1")).Should(Succeed())
+			Ω(out.String()).Should(ContainSubstring("go 1.22.1 is already installed in " + filepath.Join(InstallPath, "v1.22.1")))
+			Ω(filepath.Join(InstallPath, "v1.22.1", "stale")).Should(BeAnExistingFile())
+			Ω(filepath.Join(InstallPath, "1.22.1")).ShouldNot(BeADirectory())
+		})
+
+		g.It("reinstalls installed versions cleanly with --force", func() {
+			sut.Force = true
+			Ω(sut.Install("1.22.1")).Should(Succeed())
+			Ω(filepath.Join(InstallPath, "1.22.1")).Should(BeADirectory())
+			Ω(filepath.Join(InstallPath, "1.22.1", "stale")).ShouldNot(BeAnExistingFile())
+			Ω(filepath.Join(InstallPath, "v1.22.1")).ShouldNot(BeADirectory())
+		})
+	})
+}
+
+func TestInstallAndUse(t *testing.T) {
+	testutils.Run(t, "Install --use", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		server := newReleaseServer("1.22.1")
+		var out *Buffer
+		var sut *executor
+
+		g.BeforeEach(func() {
+			createVersionDirs()
+			out = &Buffer{&bytes.Buffer{}}
+			sut = defaultExecutor()
+			sut.URL = server.URL
+			sut.Streams.Out = out
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+		})
+
+		g.After(func() {
+			server.Close()
+		})
+
+		g.It("installs the version and makes it current", func() {
+			Ω(sut.InstallAndUse("1.22.1")).Should(Succeed())
+			Ω(out.String()).Should(Equal("installed go 1.22.1\nusing go 1.22.1\n"))
+			Ω(sut.current()).Should(Equal(Version("1.22.1")))
+		})
+
+		g.It("uses installed versions without reinstalling them", func() {
+			Ω(sut.InstallAndUse("1.16.8")).Should(Succeed())
+			Ω(out.String()).Should(ContainSubstring("go 1.16.8 is already installed"))
+			Ω(out.String()).Should(HaveSuffix("using go 1.16.8\n"))
+			Ω(sut.current()).Should(Equal(Version("1.16.8")))
+		})
+
+		g.It("plans both steps on dry runs", func() {
+			Ω(sut.PlanInstallAndUse("1.22.1")).Should(Succeed())
+			Ω(out.String()).Should(HaveSuffix(fmt.Sprintf("would link %s to %s\n", filepath.Join(InstallPath, "current"), filepath.Join(InstallPath, "1.22.1"))))
+			_, err := sut.current()
+			Ω(err).ShouldNot(Succeed())
+		})
+	})
+}
+
 func TestHandleUse(t *testing.T) {
 
 	testutils.Run(t, "Use", func(g *goblin.G) {
@@ -211,6 +308,23 @@ func TestHandleUse(t *testing.T) {
 				Ω(versionPath).Should(BeADirectory())
 				Ω(versionPath).Should(matchers.BeNamedFileOrDir(version.String()))
 			})
+
+			g.It("replaces the current link", func() {
+				sut := defaultExecutor()
+				Ω(sut.Use("v1.16.8")).Should(Succeed())
+				Ω(sut.Use(version)).Should(Succeed())
+				Ω(sut.current()).Should(Equal(Version("1.17.1")))
+				Ω(filepath.Join(InstallPath, "current"+stagingSuffix)).ShouldNot(BeAnExistingFile())
+			})
+
+			g.It("keeps the current link if the new link cannot be created", func() {
+				sut := defaultExecutor()
+				Ω(sut.Use("v1.16.8")).Should(Succeed())
+				// a non empty directory blocks the staging link
+				_ = os.MkdirAll(filepath.Join(InstallPath, "current"+stagingSuffix, "blocked"), os.ModePerm)
+				Ω(sut.Use(version)).ShouldNot(Succeed())
+				Ω(sut.current()).Should(Equal(Version("1.16.8")))
+			})
 		})
 
 		g.Describe("with not installed version", func() {
@@ -225,3 +339,115 @@ func TestHandleUse(t *testing.T) {
 		})
 	})
 }
+
+func TestHandleUninstall(t *testing.T) {
+
+	testutils.Run(t, "Uninstall", func(g *goblin.G) {
+		InstallPath = installPath(t)
+
+		g.BeforeEach(func() {
+			createVersionDirs()
+			symlink(afero.NewOsFs(), filepath.Join(InstallPath, "v1.16.8"), filepath.Join(InstallPath, "current"))
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+		})
+
+		g.Describe("with installed version", func() {
+			const version = Version("v1.17.1")
+
+			g.It("removes version", func() {
+				sut := defaultExecutor()
+				Ω(sut.Uninstall(version)).Should(Succeed())
+				Ω(filepath.Join(InstallPath, version.String())).ShouldNot(BeADirectory())
+			})
+		})
+
+		g.Describe("with current version", func() {
+			const version = Version("v1.16.8")
+
+			g.It("return errVersionInUse", func() {
+				sut := defaultExecutor()
+				Ω(sut.Uninstall(version)).Should(Equal(errVersionInUse))
+				Ω(filepath.Join(InstallPath, version.String())).Should(BeADirectory())
+			})
+		})
+
+		g.Describe("with not installed version", func() {
+			const version = Version("v99.99.99")
+
+			g.It("return ErrVersionNotInstalled", func() {
+				sut := defaultExecutor()
+				Ω(sut.Uninstall(version)).Should(Equal(ErrVersionNotInstalled))
+			})
+		})
+	})
+}
+
+func TestStagedInstall(t *testing.T) {
+	testutils.Run(t, "StagedInstall", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		dir := filepath.Join(InstallPath, "1.22.1")
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+		})
+
+		g.It("leaves nothing behind when the extraction fails", func() {
+			truncated := archiveData[:len(archiveData)/2]
+			Ω(defaultExecutor().installArchive("1.22.1", bytes.NewBuffer(truncated), "test")).ShouldNot(Succeed())
+			Ω(dir).ShouldNot(BeADirectory())
+			Ω(dir + stagingSuffix).ShouldNot(BeADirectory())
+		})
+
+		g.It("replaces an existing version directory", func() {
+			_ = os.MkdirAll(dir, os.ModePerm)
+			_ = os.WriteFile(filepath.Join(dir, "junk"), []byte("junk"), 0644)
+			Ω(defaultExecutor().installArchive("1.22.1", bytes.NewBuffer(archiveData), "test")).Should(Succeed())
+			Ω(filepath.Join(dir, "bin", "go")).Should(BeARegularFile())
+			Ω(filepath.Join(dir, "junk")).ShouldNot(BeAnExistingFile())
+		})
+
+		g.It("ignores leftover staging directories", func() {
+			_ = os.MkdirAll(dir+stagingSuffix, os.ModePerm)
+			Ω(defaultExecutor().installedVersions()).Should(BeEmpty())
+			Ω(defaultExecutor().installArchive("1.22.1", bytes.NewBuffer(archiveData), "test")).Should(Succeed())
+			Ω(dir + stagingSuffix).ShouldNot(BeADirectory())
+		})
+
+		g.It("counts the regular files of archives", func() {
+			Ω(archiveFileCount(archiveData)).Should(Equal(2))
+		})
+	})
+}
+
+// visitCommands calls fn for every subcommand below c
+func visitCommands(c *cobra.Command, fn func(*cobra.Command)) {
+	for _, sub := range c.Commands() {
+		fn(sub)
+		visitCommands(sub, fn)
+	}
+}
+
+func TestPersistentFlags(t *testing.T) {
+	testutils.Run(t, "persistent flags", func(g *goblin.G) {
+
+		g.It("are not redefined by any subcommand", func() {
+			root := NewCmd()
+			persistent := root.PersistentFlags()
+			var redefined []string
+			visitCommands(root, func(c *cobra.Command) {
+				c.Flags().VisitAll(func(f *pflag.Flag) {
+					if p := persistent.Lookup(f.Name); p != nil && p != f {
+						redefined = append(redefined, c.CommandPath()+" --"+f.Name)
+					}
+					if p := persistent.ShorthandLookup(f.Shorthand); f.Shorthand != "" && p != nil && p != f {
+						redefined = append(redefined, c.CommandPath()+" -"+f.Shorthand)
+					}
+				})
+			})
+			Ω(redefined).Should(BeEmpty())
+		})
+	})
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/alex-held/dfctl-kit/pkg/system"
+	"github.com/spf13/afero"
+)
+
+// ManifestFile is the install manifest in the directory of every installed version
+const ManifestFile = ".dfctl-go.json"
+
+// installManifest records how a version was installed.
+// It is written after the archive got extracted completely, so version directories without one may be partially extracted.
+type installManifest struct {
+	Version Version `json:"version"`
+	// Source is the url or file the archive was installed from
+	Source string `json:"source"`
+	// SHA256 is the checksum of the installed archive
+	SHA256      string    `json:"sha256"`
+	InstalledAt time.Time `json:"installed_at"`
+	OS          string    `json:"os"`
+	Arch        string    `json:"arch"`
+	// Files is the number of regular files extracted from the archive
+	Files int `json:"files"`
+	// InstalledBy identifies the environment which performed the install
+	InstalledBy installFingerprint `json:"installed_by"`
+	// Checksums are the sha256 checksums of the criticalFiles by slash separated path relative to the version directory
+	Checksums map[string]string `json:"checksums,omitempty"`
+}
+
+// installFingerprint identifies the environment which performed an install
+type installFingerprint struct {
+	OS       string `json:"os"`
+	Arch     string `json:"arch"`
+	Hostname string `json:"hostname"`
+	Version  string `json:"dfctl_go_version"`
+}
+
+// hostFingerprint returns the fingerprint of this machine and dfctl-go build
+func hostFingerprint() installFingerprint {
+	ri := system.OSRuntimeInfoGetter{}.Get()
+	hostname, _ := os.Hostname()
+	return installFingerprint{OS: ri.OS, Arch: ri.Arch, Hostname: hostname, Version: version}
+}
+
+// foreign reports whether the sdk was installed for another platform than this machine,
+// e.g. when the version directory got copied from an intel to an arm mac
+func (m installManifest) foreign() bool {
+	host := system.OSRuntimeInfoGetter{}.Get()
+	return m.OS != "" && (m.OS != host.OS || m.Arch != host.Arch)
+}
+
+// foreignWarning describes the foreign install of m
+func (m installManifest) foreignWarning() string {
+	host := system.OSRuntimeInfoGetter{}.Get()
+	msg := fmt.Sprintf("go %s is installed for %s-%s, but this machine is %s-%s", m.Version, m.OS, m.Arch, host.OS, host.Arch)
+	if by := m.InstalledBy; by.Hostname != "" {
+		msg += fmt.Sprintf("; it was installed on %s (%s-%s) by dfctl-go %s", by.Hostname, by.OS, by.Arch, by.Version)
+	}
+	return msg + "; its binaries will fail with exec format errors, reinstall it"
+}
+
+// criticalDirs contain the binaries of an sdk, whose checksums are recorded in the manifest
+var criticalDirs = []string{"bin", filepath.Join("pkg", "tool")}
+
+// criticalFiles returns the checksums of the regular files in the criticalDirs of the version directory dir
+func criticalFiles(fs afero.Fs, dir string) (map[string]string, error) {
+	checksums := map[string]string{}
+	for _, critical := range criticalDirs {
+		err := afero.Walk(fs, filepath.Join(dir, critical), func(path string, fi os.FileInfo, err error) error {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			if err != nil || !fi.Mode().IsRegular() {
+				return err
+			}
+			sum, err := fileSHA256(fs, path)
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+			checksums[filepath.ToSlash(rel)] = sum
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return checksums, nil
+}
+
+// countFiles returns the number of regular files in dir
+func countFiles(fs afero.Fs, dir string) (files int, err error) {
+	err = afero.Walk(fs, dir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if fi.Mode().IsRegular() {
+			files++
+		}
+		return nil
+	})
+	return files, err
+}
+
+// writeManifest writes the install manifest into the version directory dir
+func (e *executor) writeManifest(dir string, m installManifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = afero.WriteFile(e.Fs, filepath.Join(dir, ManifestFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write the install manifest of go %s; err=%v", m.Version, err)
+	}
+	return nil
+}
+
+// readManifest reads the install manifest of the version directory dir
+func (e *executor) readManifest(dir string) (m installManifest, err error) {
+	data, err := afero.ReadFile(e.Fs, filepath.Join(dir, ManifestFile))
+	if err != nil {
+		return m, err
+	}
+	err = json.Unmarshal(data, &m)
+	return m, err
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alex-held/dfctl-kit/pkg/system"
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestInstallManifest(t *testing.T) {
+	testutils.Run(t, "InstallManifest", func(g *goblin.G) {
+		InstallPath = installPath(t)
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+		})
+
+		install := func(source string) installManifest {
+			Ω(defaultExecutor().installArchive("1.22.1", bytes.NewBuffer(archiveData), source)).Should(Succeed())
+			m, err := defaultExecutor().readManifest(filepath.Join(InstallPath, "1.22.1"))
+			Ω(err).Should(Succeed())
+			return m
+		}
+
+		g.It("records the archive and its extraction", func() {
+			started := time.Now()
+			m := install("https://go.dev/dl/go1.22.1.linux-arm64.tar.gz")
+			sum := sha256.Sum256(archiveData)
+			Ω(m.Version).Should(Equal(Version("1.22.1")))
+			Ω(m.Source).Should(Equal("https://go.dev/dl/go1.22.1.linux-arm64.tar.gz"))
+			Ω(m.SHA256).Should(Equal(hex.EncodeToString(sum[:])))
+			Ω(m.InstalledAt).Should(BeTemporally(">=", started.Truncate(time.Second)))
+			Ω(m.OS).Should(Equal("linux"))
+			Ω(m.Arch).Should(Equal("arm64"))
+			Ω(m.Files).Should(Equal(2))
+			Ω(m.foreign()).Should(Equal(system.OSRuntimeInfoGetter{}.Get() != system.RuntimeInfo{OS: "linux", Arch: "arm64"}))
+		})
+
+		g.It("records the environment which performed the install", func() {
+			m := install("test")
+			hostname, _ := os.Hostname()
+			Ω(m.InstalledBy).Should(Equal(hostFingerprint()))
+			Ω(m.InstalledBy.Hostname).Should(Equal(hostname))
+			Ω(m.InstalledBy.Version).Should(Equal(version))
+		})
+
+		g.It("records the host platform for sources without archive name", func() {
+			m := install("test")
+			host := system.OSRuntimeInfoGetter{}.Get()
+			Ω(m.OS).Should(Equal(host.OS))
+			Ω(m.Arch).Should(Equal(host.Arch))
+			Ω(m.foreign()).Should(BeFalse())
+		})
+	})
+}
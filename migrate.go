@@ -0,0 +1,236 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/alex-held/dfctl-kit/pkg/env"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+// version managers migrate imports from
+const (
+	toolGoenv = "goenv"
+	toolGvm   = "gvm"
+	toolAsdf  = "asdf"
+)
+
+var migrateTools = []string{toolGoenv, toolGvm, toolAsdf}
+
+var errUnsupportedTool = errors.New("unsupported version manager")
+
+// migrateOptions are the options of the migrate command
+type migrateOptions struct {
+	From string
+	// Download installs fresh downloads of the versions instead of copying them
+	Download bool
+	// Dir is the project directory whose version files are converted
+	Dir string
+}
+
+// toolInstall is a go sdk installed by another version manager
+type toolInstall struct {
+	Version Version
+	Root    string
+}
+
+func newMigrateCmd() *cobra.Command {
+	opts := migrateOptions{Dir: "."}
+	cmd := &cobra.Command{
+		Use:   "migrate --from <" + strings.Join(migrateTools, "|") + ">",
+		Short: "imports the go versions of goenv, gvm or asdf",
+		Long: "imports the go versions installed by goenv, gvm or asdf by copying them into the install path or downloading them again with --download, " +
+			"converts the " + ToolVersionsFile + " of the project directory to a " + ProjectVersionFile + " and adopts the global version of the tool " +
+			"unless a global version is linked already",
+		Example: "  dfctl-go migrate --from goenv\n  dfctl-go migrate --from asdf --download --dir ~/src/project",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateArgsForSubcommand("migrate", args, 0); err != nil {
+				return err
+			}
+			return defaultExecutor().Migrate(c.Context(), opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.From, "from", "", "version manager to import from; one of "+strings.Join(migrateTools, ", "))
+	cmd.Flags().BoolVar(&opts.Download, "download", false, "download the versions again instead of copying them")
+	cmd.Flags().StringVar(&opts.Dir, "dir", opts.Dir, "project directory whose version files are converted")
+	_ = cmd.MarkFlagRequired("from")
+	return cmd
+}
+
+// toolRoot returns the data directory of the version manager, honoring the variables relocating it
+func toolRoot(tool string) string {
+	vars := env.GetVars()
+	switch tool {
+	case toolGoenv:
+		if root := vars.Get("GOENV_ROOT"); root != "" {
+			return root
+		}
+		return filepath.Join(vars.Get("HOME"), ".goenv")
+	case toolGvm:
+		if root := vars.Get("GVM_ROOT"); root != "" {
+			return root
+		}
+		return filepath.Join(vars.Get("HOME"), ".gvm")
+	}
+	if root := vars.Get("ASDF_DATA_DIR"); root != "" {
+		return root
+	}
+	return filepath.Join(vars.Get("HOME"), ".asdf")
+}
+
+// toolInstalls returns the go sdks installed by the version manager below root
+func (e *executor) toolInstalls(tool, root string) (installs []toolInstall, err error) {
+	pattern := map[string]string{
+		toolGoenv: filepath.Join(root, "versions", "*"),
+		toolGvm:   filepath.Join(root, "gos", "*"),
+		toolAsdf:  filepath.Join(root, "installs", "golang", "*", "go"),
+	}[tool]
+	roots, err := afero.Glob(e.Fs, pattern)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range roots {
+		version, err := sdkVersion(e.Fs, r)
+		if err != nil {
+			log.Debug().Err(err).Msgf("skipping %s, which is no go sdk", r)
+			continue
+		}
+		installs = append(installs, toolInstall{Version: version, Root: r})
+	}
+	sort.SliceStable(installs, func(i, j int) bool {
+		return installs[i].Version.Compare(installs[j].Version) < 0
+	})
+	return installs, nil
+}
+
+// toolGlobalVersion returns the global go version configured in the version manager, if any
+func (e *executor) toolGlobalVersion(tool, root string) (string, bool) {
+	switch tool {
+	case toolGoenv:
+		data, err := afero.ReadFile(e.Fs, filepath.Join(root, "version"))
+		if err != nil {
+			return "", false
+		}
+		v := strings.TrimSpace(strings.SplitN(string(data), "\n", 2)[0])
+		return v, v != "" && v != "system"
+	case toolGvm:
+		// gvm use --default writes the environment of the version, which names it in gvm_go_name
+		data, err := afero.ReadFile(e.Fs, filepath.Join(root, "environments", "default"))
+		if err != nil {
+			return "", false
+		}
+		// the lines look like export gvm_go_name; gvm_go_name="go1.21.5"
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			for _, statement := range strings.Split(scanner.Text(), ";") {
+				if statement = strings.TrimSpace(statement); strings.HasPrefix(statement, "gvm_go_name=") {
+					v := strings.Trim(strings.TrimPrefix(statement, "gvm_go_name="), `"'`)
+					return v, v != "" && v != "system"
+				}
+			}
+		}
+		return "", false
+	}
+	data, err := afero.ReadFile(e.Fs, filepath.Join(env.GetVars().Get("HOME"), ToolVersionsFile))
+	if err != nil {
+		return "", false
+	}
+	v, ok := parseToolVersions(data)
+	return v, ok && v != "system"
+}
+
+// Migrate imports the versions of the version manager, converts the version files of the project directory
+// and adopts the global version if no global version is linked
+func (e *executor) Migrate(ctx context.Context, opts migrateOptions) error {
+	supported := false
+	for _, tool := range migrateTools {
+		supported = supported || tool == opts.From
+	}
+	if !supported {
+		return fmt.Errorf("%w; tool=%s; supported are %s", errUnsupportedTool, opts.From, strings.Join(migrateTools, ", "))
+	}
+	root := toolRoot(opts.From)
+	installs, err := e.toolInstalls(opts.From, root)
+	if err != nil {
+		return err
+	}
+	if len(installs) == 0 {
+		e.infof("found no go versions of %s in %s\n", opts.From, root)
+	}
+
+	var failures []bulkFailure
+	for _, i := range installs {
+		if dir, err := e.versionPath(i.Version); err == nil {
+			e.infof("go %s is already installed in %s\n", i.Version, dir)
+			continue
+		}
+		if opts.Download {
+			err = e.Install(i.Version)
+		} else {
+			_, _, err = e.adopt(i.Root, false)
+		}
+		if err != nil {
+			failures = append(failures, bulkFailure{Item: i.Version.String(), Err: err})
+			continue
+		}
+		e.infof("imported go %s from %s\n", i.Version, i.Root)
+	}
+
+	if err = e.convertToolVersions(opts.Dir); err != nil {
+		failures = append(failures, bulkFailure{Item: filepath.Join(opts.Dir, ToolVersionsFile), Err: err})
+	}
+	if global, ok := e.toolGlobalVersion(opts.From, root); ok {
+		if _, err := e.current(); err == errNoCurrentVersion {
+			if err = e.useToolGlobal(ctx, global); err != nil {
+				failures = append(failures, bulkFailure{Item: "global " + global, Err: err})
+			}
+		} else {
+			log.Debug().Msgf("keeping the global version instead of go %s of %s", global, opts.From)
+		}
+	}
+	return newBulkError("migrate", "versions", len(installs), failures)
+}
+
+// useToolGlobal links the installed version resolved from the global version spec of another version manager
+func (e *executor) useToolGlobal(ctx context.Context, spec string) error {
+	version, err := e.resolveVersion(ctx, strings.TrimPrefix(spec, "go"), installedScope, false)
+	if err != nil {
+		return err
+	}
+	if err = e.Use(version); err != nil {
+		return err
+	}
+	e.infof("using go %s as global version\n", version)
+	return nil
+}
+
+// convertToolVersions writes the go version of the asdf ToolVersionsFile in dir to a ProjectVersionFile,
+// unless dir has one already; the ToolVersionsFile is kept for the other tools it pins
+func (e *executor) convertToolVersions(dir string) error {
+	data, err := afero.ReadFile(e.Fs, filepath.Join(dir, ToolVersionsFile))
+	if err != nil {
+		return nil
+	}
+	pin, ok := parseToolVersions(data)
+	if !ok || pin == "system" {
+		return nil
+	}
+	file := filepath.Join(dir, ProjectVersionFile)
+	if exists, _ := afero.Exists(e.Fs, file); exists {
+		log.Debug().Msgf("keeping %s instead of the go version of %s", file, ToolVersionsFile)
+		return nil
+	}
+	if err = afero.WriteFile(e.Fs, file, []byte(pin+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to write project pin %s; err=%v", file, err)
+	}
+	e.infof("converted the go version %s of %s to %s\n", pin, filepath.Join(dir, ToolVersionsFile), file)
+	return nil
+}
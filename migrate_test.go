@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/env"
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+)
+
+func TestMigrate(t *testing.T) {
+	testutils.Run(t, "Migrate", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		home := testutils.TempDir(t, "home")
+		project := testutils.TempDir(t, "project")
+		var out *Buffer
+
+		g.BeforeEach(func() {
+			createVersionDirs()
+			_ = os.MkdirAll(project, os.ModePerm)
+			env.Overrides.Vars = env.Vars{"HOME": home}
+		})
+
+		g.AfterEach(func() {
+			env.ClearOverrides()
+			_ = os.RemoveAll(InstallPath)
+			_ = os.RemoveAll(home)
+			_ = os.RemoveAll(project)
+		})
+
+		newSut := func() *executor {
+			out = &Buffer{&bytes.Buffer{}}
+			sut := defaultExecutor()
+			sut.Streams.Out = out
+			return sut
+		}
+
+		g.It("imports the versions and the global version of goenv", func() {
+			writeGoRoot(filepath.Join(home, ".goenv", "versions", "1.18.2"), "go1.18.2")
+			writeGoRoot(filepath.Join(home, ".goenv", "versions", "1.17.1"), "go1.17.1")
+			_ = os.WriteFile(filepath.Join(home, ".goenv", "version"), []byte("1.18.2\n"), 0644)
+
+			Ω(newSut().Migrate(context.Background(), migrateOptions{From: toolGoenv, Dir: project})).Should(Succeed())
+			Ω(out.String()).Should(ContainSubstring("go 1.17.1 is already installed"))
+			Ω(out.String()).Should(ContainSubstring("imported go 1.18.2 from " + filepath.Join(home, ".goenv", "versions", "1.18.2")))
+			Ω(filepath.Join(InstallPath, "1.18.2", "bin", "go")).Should(BeARegularFile())
+			Ω(newSut().current()).Should(Equal(Version("1.18.2")))
+		})
+
+		g.It("keeps the linked global version", func() {
+			symlink(afero.NewOsFs(), filepath.Join(InstallPath, "v1.16.8"), filepath.Join(InstallPath, "current"))
+			writeGoRoot(filepath.Join(home, ".goenv", "versions", "1.18.2"), "go1.18.2")
+			_ = os.WriteFile(filepath.Join(home, ".goenv", "version"), []byte("1.18.2\n"), 0644)
+
+			Ω(newSut().Migrate(context.Background(), migrateOptions{From: toolGoenv, Dir: project})).Should(Succeed())
+			Ω(newSut().current()).Should(Equal(Version("1.16.8")))
+		})
+
+		g.It("imports the versions of gvm", func() {
+			gvm := filepath.Join(home, "gvm")
+			env.Overrides.Vars = env.Vars{"HOME": home, "GVM_ROOT": gvm}
+			writeGoRoot(filepath.Join(gvm, "gos", "go1.18.4"), "go1.18.4")
+			_ = os.MkdirAll(filepath.Join(gvm, "environments"), os.ModePerm)
+			_ = os.WriteFile(filepath.Join(gvm, "environments", "default"), []byte("export GVM_ROOT; GVM_ROOT=\""+gvm+"\"\nexport gvm_go_name; gvm_go_name=\"go1.18.4\"\n"), 0644)
+
+			Ω(newSut().Migrate(context.Background(), migrateOptions{From: toolGvm, Dir: project})).Should(Succeed())
+			Ω(filepath.Join(InstallPath, "1.18.4", "bin", "go")).Should(BeARegularFile())
+			Ω(newSut().current()).Should(Equal(Version("1.18.4")))
+		})
+
+		g.It("imports the versions of asdf and converts the .tool-versions", func() {
+			writeGoRoot(filepath.Join(home, ".asdf", "installs", "golang", "1.18.3", "go"), "go1.18.3")
+			_ = os.WriteFile(filepath.Join(project, ToolVersionsFile), []byte("nodejs 18.0.0\ngolang 1.18.3\n"), 0644)
+
+			Ω(newSut().Migrate(context.Background(), migrateOptions{From: toolAsdf, Dir: project})).Should(Succeed())
+			Ω(filepath.Join(InstallPath, "1.18.3", "bin", "go")).Should(BeARegularFile())
+			pin, err := os.ReadFile(filepath.Join(project, ProjectVersionFile))
+			Ω(err).Should(Succeed())
+			Ω(string(pin)).Should(Equal("1.18.3\n"))
+			Ω(filepath.Join(project, ToolVersionsFile)).Should(BeARegularFile())
+		})
+
+		g.It("keeps existing project pins", func() {
+			_ = os.WriteFile(filepath.Join(project, ToolVersionsFile), []byte("golang 1.18.3\n"), 0644)
+			_ = os.WriteFile(filepath.Join(project, ProjectVersionFile), []byte("1.17\n"), 0644)
+			Ω(newSut().Migrate(context.Background(), migrateOptions{From: toolAsdf, Dir: project})).Should(Succeed())
+			pin, _ := os.ReadFile(filepath.Join(project, ProjectVersionFile))
+			Ω(string(pin)).Should(Equal("1.17\n"))
+		})
+
+		g.It("rejects unsupported version managers", func() {
+			err := newSut().Migrate(context.Background(), migrateOptions{From: "nvm", Dir: project})
+			Ω(errors.Is(err, errUnsupportedTool)).Should(BeTrue())
+		})
+	})
+}
@@ -0,0 +1,342 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	semver2 "github.com/Masterminds/semver"
+	"github.com/alex-held/dfctl-kit/pkg/env"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+// MirrorEnv configures the url of a mirror used instead of DownloadURL
+const MirrorEnv = "DFCTL_GO_MIRROR"
+
+// MirrorIndexFile is the release feed of a mirror, relative to its dl directory
+const MirrorIndexFile = "index.json"
+
+// MirrorStatusFile records the outcome of the last replication, relative to the dl directory of a mirror
+const MirrorStatusFile = "status.json"
+
+// replicationStatus is the outcome of a mirror sync, served by the status endpoint of mirror serve
+type replicationStatus struct {
+	Upstream  string    `json:"upstream"`
+	Started   time.Time `json:"started"`
+	Finished  time.Time `json:"finished"`
+	Artifacts int       `json:"artifacts"`
+	// Statuses counts the artifacts by status, e.g. up-to-date, downloaded or resumed
+	Statuses map[string]int `json:"statuses"`
+	Failed   []string       `json:"failed,omitempty"`
+}
+
+var errChecksumMismatch = errors.New("checksum mismatch")
+
+// downloadURL returns the url releases are downloaded from, which is the configured mirror or DownloadURL
+func downloadURL() string {
+	if mirror := env.GetVars().Get(MirrorEnv); mirror != "" {
+		return strings.TrimSuffix(mirror, "/")
+	}
+	return DownloadURL
+}
+
+// mirrorOptions selects the artifacts synced into a mirror
+type mirrorOptions struct {
+	Dest       string
+	Constraint string
+	// Platforms limits the artifacts to os/arch pairs, e.g. linux/amd64; all platforms are synced if empty
+	Platforms       []string
+	IncludeUnstable bool
+	// Zstd recompresses the .tar.gz archives with zstd and advertises them in the index
+	Zstd bool
+	// From is the url of another mirror to replicate from instead of the configured download url
+	From string
+}
+
+const (
+	mirrorStatusUpToDate   = "up-to-date"
+	mirrorStatusDownloaded = "downloaded"
+	mirrorStatusResumed    = "resumed"
+	mirrorStatusReplaced   = "replaced"
+	mirrorStatusFailed     = "failed"
+)
+
+func newMirrorCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mirror",
+		Short: "maintains an offline mirror of go releases",
+	}
+
+	opts := mirrorOptions{}
+	syncCmd := &cobra.Command{
+		Use:   "sync",
+		Short: "downloads the matching releases into a mirror directory",
+		Long: "downloads the artifacts of all releases matching the constraint into <dest>/dl, verifies their checksums and writes the release feed to <dest>/dl/" + MirrorIndexFile + ". " +
+			"Serve <dest> with " + MirrorIndexFile + " as directory index and point clients at it with " + MirrorEnv + ". Artifacts already present with a matching checksum are skipped. " +
+			"With --zstd the archives are additionally recompressed into smaller .tar.zst artifacts, which clients with zstd installed prefer. " +
+			"With --from the releases are replicated from another dfctl-go mirror; interrupted transfers are resumed and every artifact is verified against its checksum",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateArgsForSubcommand("mirror sync", args, 0); err != nil {
+				return err
+			}
+			if opts.Dest == "" {
+				return errors.New("required flag --dest is missing")
+			}
+			if opts.Zstd && !zstdAvailable() {
+				return fmt.Errorf("--zstd requires %s on PATH", zstdCommand)
+			}
+			return defaultExecutor().MirrorSync(c.Context(), opts)
+		},
+	}
+	syncCmd.Flags().StringVar(&opts.Dest, "dest", "", "directory of the mirror")
+	syncCmd.Flags().StringVar(&opts.Constraint, "constraint", "", "semver constraint selecting the releases, e.g. '>=1.20'; all releases are synced if empty")
+	syncCmd.Flags().StringSliceVar(&opts.Platforms, "platform", nil, "os/arch pairs to sync, e.g. linux/amd64,darwin/arm64; all platforms are synced if empty")
+	syncCmd.Flags().BoolVar(&opts.IncludeUnstable, "include-unstable", false, "sync beta and rc releases")
+	syncCmd.Flags().BoolVar(&opts.Zstd, "zstd", false, "additionally provide the archives as smaller .tar.zst artifacts")
+	syncCmd.Flags().StringVar(&opts.From, "from", "", "url of another dfctl-go mirror to replicate from, e.g. https://go-mirror.hq.corp")
+
+	cmd.AddCommand(mutating(syncCmd))
+	cmd.AddCommand(newMirrorServeCmd())
+	return cmd
+}
+
+// mirrorReleases returns the releases and artifacts of the release feed selected by opts
+func (e *executor) mirrorReleases(ctx context.Context, opts mirrorOptions) ([]Release, error) {
+	var constraint *semver2.Constraints
+	if opts.Constraint != "" {
+		c, err := semver2.NewConstraint(opts.Constraint)
+		if err != nil {
+			return nil, fmt.Errorf("invalid constraint %s; err=%v", opts.Constraint, err)
+		}
+		constraint = c
+	}
+	platforms := map[string]bool{}
+	for _, p := range opts.Platforms {
+		platforms[p] = true
+	}
+
+	releases, err := e.releases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var selected []Release
+	for _, r := range releases {
+		v, err := ParseVersion(r.Version)
+		if err != nil || (!opts.IncludeUnstable && !v.IsStable()) {
+			continue
+		}
+		if constraint != nil {
+			if sv, err := v.semver(); err != nil || !constraint.Check(sv) {
+				continue
+			}
+		}
+		files := []ReleaseFile{}
+		for _, f := range r.Files {
+			if len(platforms) == 0 || platforms[f.OS+"/"+f.Arch] {
+				files = append(files, f)
+			}
+		}
+		if len(files) > 0 {
+			r.Files = files
+			selected = append(selected, r)
+		}
+	}
+	return selected, nil
+}
+
+// MirrorSync downloads the artifacts selected by opts into the mirror, writes its release feed and records the replication status
+func (e *executor) MirrorSync(ctx context.Context, opts mirrorOptions) error {
+	if opts.From != "" {
+		e.URL = strings.TrimSuffix(opts.From, "/")
+	}
+	status := &replicationStatus{Upstream: e.URL, Started: time.Now().UTC(), Statuses: map[string]int{}}
+	releases, err := e.mirrorReleases(ctx, opts)
+	if err != nil {
+		return err
+	}
+	dl := filepath.Join(opts.Dest, "dl")
+	if err = e.Fs.MkdirAll(dl, os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create mirror directory %s; err=%v", dl, err)
+	}
+
+	w := tabwriter.NewWriter(e.Streams.Out, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ARTIFACT\tSTATUS")
+	for _, r := range releases {
+		for i, f := range r.Files {
+			fileStatus, err := e.mirrorFile(ctx, dl, f)
+			if err != nil {
+				status.Failed = append(status.Failed, f.Filename)
+				log.Warn().Err(err).Msgf("failed to mirror %s", f.Filename)
+				fileStatus = mirrorStatusFailed
+			} else if opts.Zstd && strings.HasSuffix(f.Filename, ".tar.gz") {
+				if r.Files[i].Zstd, err = e.mirrorZstd(ctx, dl, f, fileStatus == mirrorStatusUpToDate); err != nil {
+					log.Warn().Err(err).Msgf("failed to recompress %s with zstd", f.Filename)
+				}
+			}
+			status.Artifacts++
+			status.Statuses[fileStatus]++
+			_, _ = fmt.Fprintf(w, "%s\t%s\n", f.Filename, fileStatus)
+		}
+	}
+	if err = w.Flush(); err != nil {
+		return err
+	}
+	status.Finished = time.Now().UTC()
+	if err = e.writeReplicationStatus(dl, status); err != nil {
+		return err
+	}
+
+	index, err := json.MarshalIndent(releases, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = afero.WriteFile(e.Fs, filepath.Join(dl, MirrorIndexFile), index, 0644); err != nil {
+		return fmt.Errorf("failed to write mirror index; err=%v", err)
+	}
+	if len(status.Failed) > 0 {
+		return fmt.Errorf("failed to mirror %d artifacts", len(status.Failed))
+	}
+	return nil
+}
+
+// mirrorFile downloads the artifact into dir unless it is already present with the expected checksum.
+// A partial download left behind by an interrupted transfer is resumed if the server supports range requests.
+func (e *executor) mirrorFile(ctx context.Context, dir string, f ReleaseFile) (status string, err error) {
+	target := filepath.Join(dir, f.Filename)
+	status = mirrorStatusDownloaded
+	if sum, err := fileSHA256(e.Fs, target); err == nil {
+		if f.SHA256 != "" && sum == f.SHA256 {
+			return mirrorStatusUpToDate, nil
+		}
+		status = mirrorStatusReplaced
+	}
+
+	tmp := target + ".partial"
+	h := sha256.New()
+	resumed, err := e.resumeDownload(ctx, e.URL+"/dl/"+f.Filename, tmp, h, f.Filename)
+	if err != nil {
+		// keep the partial file to resume the transfer next time
+		return "", err
+	}
+	if resumed && status == mirrorStatusDownloaded {
+		status = mirrorStatusResumed
+	}
+	if sum := hex.EncodeToString(h.Sum(nil)); f.SHA256 != "" && sum != f.SHA256 {
+		_ = e.Fs.Remove(tmp)
+		return "", fmt.Errorf("%w; file=%s; expected=%s; actual=%s", errChecksumMismatch, f.Filename, f.SHA256, sum)
+	}
+	if err = e.Fs.Rename(tmp, target); err != nil {
+		return "", err
+	}
+	if f.SHA256 != "" {
+		if err = afero.WriteFile(e.Fs, target+".sha256", []byte(f.SHA256+"\n"), 0644); err != nil {
+			return "", err
+		}
+	}
+	return status, nil
+}
+
+// resumeDownload downloads url into the file tmp, continuing after its current content if the server supports range requests.
+// h receives the complete content of tmp; resumed reports whether an earlier partial transfer was continued.
+func (e *executor) resumeDownload(ctx context.Context, url, tmp string, h hash.Hash, subject string) (resumed bool, err error) {
+	out, err := e.Fs.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return false, err
+	}
+	defer out.Close()
+	offset, err := out.Seek(0, io.SeekEnd)
+	if err != nil {
+		return false, err
+	}
+	if offset > 0 {
+		in, err := e.Fs.Open(tmp)
+		if err != nil {
+			return false, err
+		}
+		_, err = io.Copy(h, in)
+		_ = in.Close()
+		if err != nil {
+			return false, err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return false, err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+		log.Debug().Msgf("resuming %s at %d bytes", subject, offset)
+		resumed = true
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		// the partial file is complete already
+		return true, nil
+	case resp.StatusCode == http.StatusOK:
+		// the server ignores the range, so start over
+		if err = out.Truncate(0); err != nil {
+			return false, err
+		}
+		h.Reset()
+	default:
+		return false, fmt.Errorf("unexpected response status %s for %s", resp.Status, url)
+	}
+
+	pw := &progressWriter{
+		Writer: io.MultiWriter(out, h),
+		ev:     progressEvent{Phase: phaseDownload, Subject: subject, TotalBytes: resp.ContentLength},
+		report: e.progress,
+	}
+	n, err := io.Copy(pw, resp.Body)
+	e.Summary.addBytes(n)
+	pw.ev.Done = true
+	e.progress(pw.ev)
+	return resumed, err
+}
+
+// fileSHA256 returns the hex encoded sha256 checksum of the file
+func fileSHA256(fs afero.Fs, file string) (string, error) {
+	f, err := fs.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// writeReplicationStatus writes the status into the dl directory of a mirror
+func (e *executor) writeReplicationStatus(dl string, status *replicationStatus) error {
+	data, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = afero.WriteFile(e.Fs, filepath.Join(dl, MirrorStatusFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write replication status; err=%v", err)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+//go:build !nodaemon
+// +build !nodaemon
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+// defaultReplicationInterval is the time between two replications of mirror serve --from
+const defaultReplicationInterval = time.Hour
+
+// serveOptions configures mirror serve
+type serveOptions struct {
+	mirrorOptions
+	Addr string
+	// Interval is the time between two replications from mirrorOptions.From
+	Interval time.Duration
+}
+
+func newMirrorServeCmd() *cobra.Command {
+	opts := serveOptions{}
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "serves a mirror directory and optionally replicates it from another mirror",
+		Long: "serves the release feed and artifacts of a mirror directory over http, including range requests for resumable downloads, and the status of the last replication at /status. " +
+			"With --from the mirror is replicated from another dfctl-go mirror every --interval, which allows mirror hierarchies across sites",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateArgsForSubcommand("mirror serve", args, 0); err != nil {
+				return err
+			}
+			if opts.Dest == "" {
+				return errors.New("required flag --dest is missing")
+			}
+			return defaultExecutor().MirrorServe(c.Context(), opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.Dest, "dest", "", "directory of the mirror")
+	cmd.Flags().StringVar(&opts.Addr, "addr", ":8080", "address to listen on")
+	cmd.Flags().StringVar(&opts.From, "from", "", "url of another dfctl-go mirror to replicate from")
+	cmd.Flags().DurationVar(&opts.Interval, "interval", defaultReplicationInterval, "time between two replications from --from")
+	cmd.Flags().StringVar(&opts.Constraint, "constraint", "", "semver constraint selecting the replicated releases")
+	cmd.Flags().StringSliceVar(&opts.Platforms, "platform", nil, "os/arch pairs to replicate; all platforms are replicated if empty")
+	cmd.Flags().BoolVar(&opts.IncludeUnstable, "include-unstable", false, "replicate beta and rc releases")
+	return cmd
+}
+
+// mirrorServer serves a mirror directory
+type mirrorServer struct {
+	e    *executor
+	dest string
+
+	mu      sync.Mutex
+	syncing bool
+}
+
+// ServeHTTP serves the release feed for /dl/?mode=json, the artifacts below /dl/ and the replication status at /status
+func (s *mirrorServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	dl := filepath.Join(s.dest, "dl")
+	switch {
+	case r.URL.Path == "/status":
+		s.serveStatus(w, filepath.Join(dl, MirrorStatusFile))
+	case r.URL.Path == "/dl/" && r.URL.Query().Get("mode") == "json":
+		s.serveFile(w, r, filepath.Join(dl, MirrorIndexFile))
+	case strings.HasPrefix(r.URL.Path, "/dl/") && path.Base(r.URL.Path) != MirrorStatusFile:
+		name := path.Clean(strings.TrimPrefix(r.URL.Path, "/dl/"))
+		if strings.Contains(name, "/") || strings.HasSuffix(name, ".partial") || name == "." {
+			http.NotFound(w, r)
+			return
+		}
+		s.serveFile(w, r, filepath.Join(dl, name))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// serveFile serves the file of the mirror with support for range requests
+func (s *mirrorServer) serveFile(w http.ResponseWriter, r *http.Request, file string) {
+	f, err := s.e.Fs.Open(file)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil || fi.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+	http.ServeContent(w, r, fi.Name(), fi.ModTime(), f)
+}
+
+func (s *mirrorServer) serveStatus(w http.ResponseWriter, file string) {
+	status := struct {
+		*replicationStatus
+		Syncing bool `json:"syncing"`
+	}{replicationStatus: &replicationStatus{}}
+	if data, err := afero.ReadFile(s.e.Fs, file); err == nil {
+		_ = json.Unmarshal(data, status.replicationStatus)
+	}
+	s.mu.Lock()
+	status.Syncing = s.syncing
+	s.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(status)
+}
+
+// replicate syncs the mirror from its upstream every interval until ctx is done
+func (s *mirrorServer) replicate(ctx context.Context, opts mirrorOptions, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		s.mu.Lock()
+		s.syncing = true
+		s.mu.Unlock()
+		if err := s.e.MirrorSync(ctx, opts); err != nil {
+			log.Warn().Err(err).Msgf("failed to replicate from %s", opts.From)
+		}
+		s.mu.Lock()
+		s.syncing = false
+		s.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// MirrorServe serves the mirror until ctx is done, replicating it from opts.From if set
+func (e *executor) MirrorServe(ctx context.Context, opts serveOptions) error {
+	s := &mirrorServer{e: e, dest: opts.Dest}
+	if opts.From != "" {
+		go s.replicate(ctx, opts.mirrorOptions, opts.Interval)
+	}
+	server := &http.Server{Addr: opts.Addr, Handler: s}
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdown)
+	}()
+	_, _ = fmt.Fprintf(e.Streams.Out, "serving %s on %s\n", opts.Dest, opts.Addr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
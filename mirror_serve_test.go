@@ -0,0 +1,119 @@
+//go:build !nodaemon
+// +build !nodaemon
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/system"
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestMirrorReplication(t *testing.T) {
+	testutils.Run(t, "MirrorReplication", func(g *goblin.G) {
+		upstream := testutils.TempDir(t, "upstream")
+		dest := testutils.TempDir(t, "replica")
+		server := newReleaseServer("1.22.1", "1.21.9")
+		ri := system.OSRuntimeInfoGetter{}.Get()
+		artifact := formatGoArchiveArtifactName(ri, "1.22.1")
+		var mirror *httptest.Server
+		var out *Buffer
+
+		newSut := func() *executor {
+			out = &Buffer{&bytes.Buffer{}}
+			sut := defaultExecutor()
+			sut.URL = server.URL
+			sut.Streams.Out = out
+			return sut
+		}
+
+		g.BeforeEach(func() {
+			Ω(newSut().MirrorSync(context.Background(), mirrorOptions{Dest: upstream, Platforms: []string{ri.OS + "/" + ri.Arch}})).Should(Succeed())
+			mirror = httptest.NewServer(&mirrorServer{e: newSut(), dest: upstream})
+		})
+
+		g.AfterEach(func() {
+			mirror.Close()
+			_ = os.RemoveAll(upstream)
+			_ = os.RemoveAll(dest)
+		})
+
+		g.After(func() {
+			server.Close()
+		})
+
+		sync := func() error {
+			return newSut().MirrorSync(context.Background(), mirrorOptions{Dest: dest, From: mirror.URL})
+		}
+
+		g.It("replicates from another mirror", func() {
+			Ω(sync()).Should(Succeed())
+			Ω(filepath.Join(dest, "dl", artifact)).Should(BeARegularFile())
+			Ω(filepath.Join(dest, "dl", MirrorIndexFile)).Should(BeARegularFile())
+			Ω(out.String()).Should(MatchRegexp(artifact + `\s+downloaded`))
+		})
+
+		g.It("resumes interrupted transfers", func() {
+			_ = os.MkdirAll(filepath.Join(dest, "dl"), os.ModePerm)
+			_ = os.WriteFile(filepath.Join(dest, "dl", artifact+".partial"), archiveData[:len(archiveData)/2], 0644)
+			Ω(sync()).Should(Succeed())
+			Ω(out.String()).Should(MatchRegexp(artifact + `\s+resumed`))
+			Ω(os.ReadFile(filepath.Join(dest, "dl", artifact))).Should(Equal(archiveData))
+		})
+
+		g.It("discards corrupt partial transfers", func() {
+			_ = os.MkdirAll(filepath.Join(dest, "dl"), os.ModePerm)
+			_ = os.WriteFile(filepath.Join(dest, "dl", artifact+".partial"), []byte("corrupt"), 0644)
+			Ω(sync()).ShouldNot(Succeed())
+			Ω(filepath.Join(dest, "dl", artifact+".partial")).ShouldNot(BeAnExistingFile())
+			Ω(sync()).Should(Succeed())
+			Ω(os.ReadFile(filepath.Join(dest, "dl", artifact))).Should(Equal(archiveData))
+		})
+
+		g.It("replaces modified artifacts", func() {
+			Ω(sync()).Should(Succeed())
+			_ = os.WriteFile(filepath.Join(dest, "dl", artifact), []byte("tampered"), 0644)
+			Ω(sync()).Should(Succeed())
+			Ω(out.String()).Should(MatchRegexp(artifact + `\s+replaced`))
+			Ω(os.ReadFile(filepath.Join(dest, "dl", artifact))).Should(Equal(archiveData))
+		})
+
+		g.It("serves the replication status", func() {
+			Ω(sync()).Should(Succeed())
+			replica := httptest.NewServer(&mirrorServer{e: newSut(), dest: dest})
+			defer replica.Close()
+
+			resp, err := http.Get(replica.URL + "/status")
+			Ω(err).Should(Succeed())
+			defer resp.Body.Close()
+			status := struct {
+				replicationStatus
+				Syncing bool `json:"syncing"`
+			}{}
+			Ω(json.NewDecoder(resp.Body).Decode(&status)).Should(Succeed())
+			Ω(status.Upstream).Should(Equal(mirror.URL))
+			Ω(status.Artifacts).Should(Equal(2))
+			Ω(status.Statuses).Should(HaveKeyWithValue(mirrorStatusDownloaded, 2))
+			Ω(status.Failed).Should(BeEmpty())
+			Ω(status.Syncing).Should(BeFalse())
+		})
+
+		g.It("does not serve partial files", func() {
+			_ = os.WriteFile(filepath.Join(upstream, "dl", artifact+".partial"), []byte("partial"), 0644)
+			resp, err := http.Get(mirror.URL + "/dl/" + artifact + ".partial")
+			Ω(err).Should(Succeed())
+			_ = resp.Body.Close()
+			Ω(resp.StatusCode).Should(Equal(http.StatusNotFound))
+		})
+	})
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/env"
+	"github.com/alex-held/dfctl-kit/pkg/system"
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestMirrorSync(t *testing.T) {
+	testutils.Run(t, "MirrorSync", func(g *goblin.G) {
+		dest := testutils.TempDir(t, "mirror")
+		server := newReleaseServer("1.22.1", "1.21.9", "1.19.13", "1.23.0-rc.1")
+		ri := system.OSRuntimeInfoGetter{}.Get()
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(dest)
+		})
+
+		g.After(func() {
+			server.Close()
+		})
+
+		newSut := func() (*executor, *Buffer) {
+			out := &Buffer{&bytes.Buffer{}}
+			sut := defaultExecutor()
+			sut.URL = server.URL
+			sut.Streams.Out = out
+			return sut, out
+		}
+
+		g.It("downloads the matching artifacts with checksums", func() {
+			sut, _ := newSut()
+			Ω(sut.MirrorSync(context.Background(), mirrorOptions{Dest: dest, Constraint: ">=1.20"})).Should(Succeed())
+			Ω(filepath.Join(dest, "dl", formatGoArchiveArtifactName(ri, "1.22.1"))).Should(BeARegularFile())
+			Ω(filepath.Join(dest, "dl", formatGoArchiveArtifactName(ri, "1.22.1")+".sha256")).Should(BeARegularFile())
+			Ω(filepath.Join(dest, "dl", formatGoArchiveArtifactName(ri, "1.19.13"))).ShouldNot(BeAnExistingFile())
+			Ω(filepath.Join(dest, "dl", formatGoArchiveArtifactName(ri, "1.23rc1"))).ShouldNot(BeAnExistingFile())
+		})
+
+		g.It("writes the release feed", func() {
+			sut, _ := newSut()
+			Ω(sut.MirrorSync(context.Background(), mirrorOptions{Dest: dest, Constraint: ">=1.20"})).Should(Succeed())
+			data, err := os.ReadFile(filepath.Join(dest, "dl", MirrorIndexFile))
+			Ω(err).Should(Succeed())
+			var releases []Release
+			Ω(json.Unmarshal(data, &releases)).Should(Succeed())
+			Ω(releases).Should(HaveLen(2))
+		})
+
+		g.It("skips artifacts already mirrored", func() {
+			sut, _ := newSut()
+			Ω(sut.MirrorSync(context.Background(), mirrorOptions{Dest: dest, Constraint: "1.22.1"})).Should(Succeed())
+			sut, out := newSut()
+			Ω(sut.MirrorSync(context.Background(), mirrorOptions{Dest: dest, Constraint: "1.22.1"})).Should(Succeed())
+			Ω(out.String()).Should(ContainSubstring("up-to-date"))
+		})
+
+		g.It("selects platforms", func() {
+			sut, _ := newSut()
+			Ω(sut.MirrorSync(context.Background(), mirrorOptions{Dest: dest, Platforms: []string{"plan9/386"}})).Should(Succeed())
+			Ω(filepath.Join(dest, "dl", formatGoArchiveArtifactName(ri, "1.22.1"))).ShouldNot(BeAnExistingFile())
+		})
+	})
+}
+
+func TestDownloadURL(t *testing.T) {
+	testutils.Run(t, "downloadURL", func(g *goblin.G) {
+
+		g.AfterEach(func() {
+			env.ClearOverrides()
+		})
+
+		g.It("defaults to DownloadURL", func() {
+			Ω(downloadURL()).Should(Equal(DownloadURL))
+		})
+
+		g.It("prefers the configured mirror", func() {
+			env.Overrides.Vars = env.Vars{MirrorEnv: "https://mirror.example.com/"}
+			Ω(downloadURL()).Should(Equal("https://mirror.example.com"))
+		})
+	})
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+var errFileExists = errors.New("file already exists")
+
+// toolchainPattern matches the toolchain directive of a go.mod
+var toolchainPattern = regexp.MustCompile(`(?m)^toolchain\s+\S+[ \t]*$`)
+
+// goDirectivePattern matches the go directive of a go.mod
+var goDirectivePattern = regexp.MustCompile(`(?m)^go\s+\S+[ \t]*$`)
+
+var goModTemplate = template.Must(template.New("go.mod").Parse(`module {{ .Module }}
+
+go {{ .GoDirective }}
+{{- if .Toolchain }}
+
+toolchain go{{ .Version.GoName }}
+{{- end }}
+`))
+
+var envrcTemplate = template.Must(template.New(".envrc").Parse(`# Generated by dfctl-go; puts go {{ .Version }} pinned in ` + ProjectVersionFile + ` on PATH
+eval "$(dfctl-go env --shell bash)"
+`))
+
+var ciTemplates = map[string]struct {
+	File     string
+	Template *template.Template
+}{
+	"github": {
+		File: filepath.Join(".github", "workflows", "go.yml"),
+		Template: template.Must(template.New("github").Parse(`# Generated by dfctl-go; keep the matrix in sync with ` + ProjectVersionFile + `
+name: go
+on: [push, pull_request]
+jobs:
+  test:
+    strategy:
+      matrix:
+        go: ['{{ .Version.GoName }}']
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+      - uses: actions/setup-go@v5
+        with:
+          go-version: ${{ "{{" }} matrix.go {{ "}}" }}
+      - run: go test ./...
+`)),
+	},
+	"gitlab": {
+		File: ".gitlab-ci.yml",
+		Template: template.Must(template.New("gitlab").Parse(`# Generated by dfctl-go; keep the matrix in sync with ` + ProjectVersionFile + `
+test:
+  image: golang:${GO_VERSION}
+  parallel:
+    matrix:
+      - GO_VERSION: ["{{ .Version.GoName }}"]
+  script:
+    - go test ./...
+`)),
+	},
+}
+
+// projectOptions configures the scaffolding of new-project
+type projectOptions struct {
+	Version string
+	Dir     string
+	Module  string
+	Direnv  bool
+	// CI names the ci systems snippets are generated for, see ciTemplates
+	CI    []string
+	Force bool
+}
+
+type projectTemplateData struct {
+	Version     Version
+	Module      string
+	GoDirective string
+	Toolchain   bool
+}
+
+func newNewProjectCmd() *cobra.Command {
+	opts := projectOptions{}
+	cmd := &cobra.Command{
+		Use:   "new-project",
+		Short: "bootstraps a project pinned to a go version",
+		Long: "bootstraps a project pinned to a go version: writes " + ProjectVersionFile + ", a go.mod with a matching toolchain directive, " +
+			"ci snippets testing the same version and optionally a direnv .envrc; an existing go.mod gets its toolchain directive updated",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateArgsForSubcommand("new-project", args, 0); err != nil {
+				return err
+			}
+			return defaultExecutor().NewProject(c.Context(), opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.Version, "version", KeywordStable, "go version of the project; partial versions like 1.22 resolve to the newest release")
+	cmd.Flags().StringVar(&opts.Dir, "dir", ".", "directory of the project")
+	cmd.Flags().StringVar(&opts.Module, "module", "", "module path of a new go.mod; defaults to the name of the directory")
+	cmd.Flags().BoolVar(&opts.Direnv, "direnv", false, "write a direnv .envrc putting the pinned version on PATH")
+	cmd.Flags().StringSliceVar(&opts.CI, "ci", []string{"github"}, "ci systems to write snippets for; github, gitlab or none")
+	cmd.Flags().BoolVar(&opts.Force, "force", false, "overwrite existing files")
+	return cmd
+}
+
+// NewProject scaffolds the pinned project described by opts
+func (e *executor) NewProject(ctx context.Context, opts projectOptions) error {
+	version, err := e.resolveVersion(ctx, opts.Version, remoteScope, false)
+	if err != nil {
+		return err
+	}
+	dir, err := filepath.Abs(opts.Dir)
+	if err != nil {
+		return err
+	}
+	data := projectTemplateData{
+		Version:     version,
+		Module:      opts.Module,
+		GoDirective: goDirective(version),
+		Toolchain:   supportsToolchain(version),
+	}
+	if data.Module == "" {
+		data.Module = filepath.Base(dir)
+	}
+
+	files := map[string][]byte{
+		ProjectVersionFile: []byte(version.String() + "\n"),
+	}
+	if files[GoModFile], err = e.projectGoMod(filepath.Join(dir, GoModFile), data); err != nil {
+		return err
+	}
+	if opts.Direnv {
+		if files[".envrc"], err = render(envrcTemplate, data); err != nil {
+			return err
+		}
+	}
+	for _, ci := range opts.CI {
+		if ci == "none" {
+			continue
+		}
+		t, ok := ciTemplates[ci]
+		if !ok {
+			return fmt.Errorf("unsupported ci %s; use github, gitlab or none", ci)
+		}
+		if files[t.File], err = render(t.Template, data); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range sortedKeys(files) {
+		file := filepath.Join(dir, name)
+		if exists, _ := afero.Exists(e.Fs, file); exists && !opts.Force && name != GoModFile {
+			return fmt.Errorf("%w; file=%s; pass --force to overwrite it", errFileExists, file)
+		}
+	}
+	for _, name := range sortedKeys(files) {
+		file := filepath.Join(dir, name)
+		if err = e.Fs.MkdirAll(filepath.Dir(file), os.ModePerm); err != nil {
+			return err
+		}
+		if err = afero.WriteFile(e.Fs, file, files[name], 0644); err != nil {
+			return fmt.Errorf("failed to write %s; err=%v", file, err)
+		}
+		_, _ = fmt.Fprintf(e.Streams.Out, "wrote %s\n", file)
+	}
+	if !data.Toolchain {
+		_, _ = fmt.Fprintf(e.Streams.Out, "go %s predates the toolchain directive; %s only pins the go directive\n", version, GoModFile)
+	}
+	return nil
+}
+
+// projectGoMod returns the go.mod of the project, which is the existing go.mod with an updated toolchain directive
+func (e *executor) projectGoMod(file string, data projectTemplateData) ([]byte, error) {
+	existing, err := afero.ReadFile(e.Fs, file)
+	if err != nil {
+		return render(goModTemplate, data)
+	}
+	if !data.Toolchain {
+		return existing, nil
+	}
+	directive := "toolchain go" + data.Version.GoName()
+	if toolchainPattern.Match(existing) {
+		return toolchainPattern.ReplaceAll(existing, []byte(directive)), nil
+	}
+	loc := goDirectivePattern.FindIndex(existing)
+	if loc == nil {
+		return nil, fmt.Errorf("%s contains no go directive", file)
+	}
+	return []byte(string(existing[:loc[1]]) + "\n\n" + directive + string(existing[loc[1]:])), nil
+}
+
+// goDirective returns the go directive of a new module for version, e.g. 1.22.0 for go 1.22.1
+func goDirective(version Version) string {
+	if supportsToolchain(version) {
+		return version.Minor() + ".0"
+	}
+	return version.Minor()
+}
+
+// supportsToolchain reports whether version understands the toolchain directive introduced with go 1.21
+func supportsToolchain(version Version) bool {
+	return version.Compare("1.21.0-0") >= 0
+}
+
+func render(t *template.Template, data interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	err := t.Execute(buf, data)
+	return buf.Bytes(), err
+}
+
+func sortedKeys(files map[string][]byte) (keys []string) {
+	for key := range files {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
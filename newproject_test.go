@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestNewProject(t *testing.T) {
+	testutils.Run(t, "NewProject", func(g *goblin.G) {
+		dir := testutils.TempDir(t, "svc-foo")
+		server := newReleaseServer("1.22.1", "1.22.0", "1.20.14")
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(dir)
+		})
+
+		g.After(func() {
+			server.Close()
+		})
+
+		newSut := func() *executor {
+			sut := defaultExecutor()
+			sut.URL = server.URL
+			sut.Streams.Out = &Buffer{&bytes.Buffer{}}
+			return sut
+		}
+
+		read := func(name string) string {
+			data, _ := os.ReadFile(filepath.Join(dir, name))
+			return string(data)
+		}
+
+		g.It("pins the resolved version", func() {
+			Ω(newSut().NewProject(context.Background(), projectOptions{Version: "1.22", Dir: dir, CI: []string{"github", "gitlab"}, Direnv: true})).Should(Succeed())
+			Ω(read(ProjectVersionFile)).Should(Equal("1.22.1\n"))
+			Ω(read(GoModFile)).Should(Equal("module svc-foo\n\ngo 1.22.0\n\ntoolchain go1.22.1\n"))
+			Ω(read(filepath.Join(".github", "workflows", "go.yml"))).Should(ContainSubstring("go: ['1.22.1']"))
+			Ω(read(filepath.Join(".github", "workflows", "go.yml"))).Should(ContainSubstring("go-version: ${{ matrix.go }}"))
+			Ω(read(".gitlab-ci.yml")).Should(ContainSubstring(`GO_VERSION: ["1.22.1"]`))
+			Ω(read(".envrc")).Should(ContainSubstring("dfctl-go env"))
+		})
+
+		g.It("updates the toolchain of an existing go.mod", func() {
+			_ = os.MkdirAll(dir, os.ModePerm)
+			_ = os.WriteFile(filepath.Join(dir, GoModFile), []byte("module example.com/foo\n\ngo 1.21\n\nrequire example.com/bar v1.0.0\n"), 0644)
+			Ω(newSut().NewProject(context.Background(), projectOptions{Version: "1.22.1", Dir: dir})).Should(Succeed())
+			Ω(read(GoModFile)).Should(Equal("module example.com/foo\n\ngo 1.21\n\ntoolchain go1.22.1\n\nrequire example.com/bar v1.0.0\n"))
+
+			Ω(newSut().NewProject(context.Background(), projectOptions{Version: "1.22.0", Dir: dir, Force: true})).Should(Succeed())
+			Ω(read(GoModFile)).Should(ContainSubstring("\ntoolchain go1.22.0\n"))
+		})
+
+		g.It("omits the toolchain directive for old versions", func() {
+			Ω(newSut().NewProject(context.Background(), projectOptions{Version: "1.20", Dir: dir})).Should(Succeed())
+			Ω(read(GoModFile)).Should(Equal("module svc-foo\n\ngo 1.20\n"))
+		})
+
+		g.It("keeps existing files", func() {
+			_ = os.MkdirAll(dir, os.ModePerm)
+			_ = os.WriteFile(filepath.Join(dir, ProjectVersionFile), []byte("1.20\n"), 0644)
+			err := newSut().NewProject(context.Background(), projectOptions{Version: "1.22", Dir: dir})
+			Ω(errors.Is(err, errFileExists)).Should(BeTrue())
+			Ω(read(ProjectVersionFile)).Should(Equal("1.20\n"))
+		})
+	})
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func newNormalizeCmd() *cobra.Command {
+	var removeDuplicates bool
+
+	cmd := &cobra.Command{
+		Use:   "normalize",
+		Short: "renames install directories created by other tools to the canonical version scheme",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateArgsForSubcommand("normalize", args, 0); err != nil {
+				return err
+			}
+			e := defaultExecutor()
+			return e.Normalize(removeDuplicates)
+		},
+	}
+	cmd.Flags().BoolVar(&removeDuplicates, "remove-duplicates", false, "remove directories duplicating an already canonical install")
+
+	return cmd
+}
+
+// Normalize renames every install directory to the canonical name of its version, e.g. v1.22.2 to 1.22.2.
+// Directories duplicating a canonical install are only removed if removeDuplicates is set.
+// The current link is moved along with the renamed directories.
+func (e *executor) Normalize(removeDuplicates bool) error {
+	installs, err := e.installations()
+	if err != nil {
+		return err
+	}
+	current, currentErr := e.current()
+
+	changed := false
+	for _, i := range installs {
+		canonical := i.Version.String()
+		if i.Dir == canonical {
+			continue
+		}
+		from := filepath.Join(e.InstallPath, i.Dir)
+		to := filepath.Join(e.InstallPath, canonical)
+
+		exists, err := afero.DirExists(e.Fs, to)
+		if err != nil {
+			return err
+		}
+		switch {
+		case exists && removeDuplicates:
+			if err = e.Fs.RemoveAll(from); err != nil {
+				return fmt.Errorf("failed to remove duplicate %s of %s; err=%v", i.Dir, canonical, err)
+			}
+			e.infof("removed %s duplicating %s\n", i.Dir, canonical)
+		case exists:
+			e.infof("skipped %s duplicating %s; rerun with --remove-duplicates to remove it\n", i.Dir, canonical)
+			continue
+		default:
+			if err = e.Fs.Rename(from, to); err != nil {
+				return fmt.Errorf("failed to rename %s to %s; err=%v", i.Dir, canonical, err)
+			}
+			e.infof("renamed %s to %s\n", i.Dir, canonical)
+		}
+		changed = true
+	}
+
+	if changed && currentErr == nil {
+		return e.Use(current)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+)
+
+func TestNormalize(t *testing.T) {
+	testutils.Run(t, "Normalize", func(g *goblin.G) {
+		InstallPath = installPath(t)
+
+		g.BeforeEach(func() {
+			for _, dir := range []string{"v1.21.5", "1.20.1", "v1.20.1"} {
+				_ = os.MkdirAll(filepath.Join(InstallPath, dir), os.ModePerm)
+			}
+			symlink(afero.NewOsFs(), filepath.Join(InstallPath, "v1.21.5"), filepath.Join(InstallPath, "current"))
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+		})
+
+		newSut := func() *executor {
+			sut := defaultExecutor()
+			sut.Streams.Out = &Buffer{&bytes.Buffer{}}
+			return sut
+		}
+
+		g.It("renames directories to the canonical version", func() {
+			Ω(newSut().Normalize(false)).Should(Succeed())
+			Ω(filepath.Join(InstallPath, "1.21.5")).Should(BeADirectory())
+			Ω(filepath.Join(InstallPath, "v1.21.5")).ShouldNot(BeADirectory())
+		})
+
+		g.It("relinks current", func() {
+			Ω(newSut().Normalize(false)).Should(Succeed())
+			link, err := os.Readlink(filepath.Join(InstallPath, "current"))
+			Ω(err).Should(Succeed())
+			Ω(link).Should(Equal(filepath.Join(InstallPath, "1.21.5")))
+		})
+
+		g.It("keeps duplicates", func() {
+			Ω(newSut().Normalize(false)).Should(Succeed())
+			Ω(filepath.Join(InstallPath, "v1.20.1")).Should(BeADirectory())
+		})
+
+		g.It("removes duplicates with removeDuplicates", func() {
+			Ω(newSut().Normalize(true)).Should(Succeed())
+			Ω(filepath.Join(InstallPath, "v1.20.1")).ShouldNot(BeADirectory())
+			Ω(filepath.Join(InstallPath, "1.20.1")).Should(BeADirectory())
+		})
+	})
+}
+
+func TestInstallations(t *testing.T) {
+	testutils.Run(t, "installations", func(g *goblin.G) {
+		InstallPath = installPath(t)
+
+		g.BeforeEach(func() {
+			for _, dir := range []string{"v1.21.5", "tmp"} {
+				_ = os.MkdirAll(filepath.Join(InstallPath, dir), os.ModePerm)
+			}
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+		})
+
+		g.It("normalizes versions and skips other directories", func() {
+			Ω(defaultExecutor().installations()).Should(Equal([]installation{{Version: "1.21.5", Dir: "v1.21.5"}}))
+		})
+
+		g.It("finds the install directory of differently spelled versions", func() {
+			Ω(defaultExecutor().versionPath("1.21.5")).Should(Equal(filepath.Join(InstallPath, "v1.21.5")))
+		})
+	})
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/alex-held/dfctl-kit/pkg/system"
+	"github.com/rs/zerolog/log"
+)
+
+// defaultNotifyAfter is the install duration after which a finished install is worth a notification
+const defaultNotifyAfter = 30 * time.Second
+
+// NotificationConfig configures desktop notifications
+type NotificationConfig struct {
+	// Enabled turns desktop notifications on
+	Enabled bool `yaml:"enabled"`
+	// After is the minimum duration of an operation started in a terminal before its completion is notified
+	After time.Duration `yaml:"after,omitempty"`
+}
+
+// notificationCommand returns the command line showing a desktop notification on the given platform
+func notificationCommand(ri system.RuntimeInfo, title, message string) ([]string, error) {
+	switch ri.OS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(message), appleScriptString(title))
+		return []string{"osascript", "-e", script}, nil
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return []string{"notify-send", "--app-name=dfctl-go", title, message}, nil
+	case "windows":
+		script := fmt.Sprintf(`[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$xml = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $xml.GetElementsByTagName('text')
+$text.Item(0).AppendChild($xml.CreateTextNode(%s)) > $null
+$text.Item(1).AppendChild($xml.CreateTextNode(%s)) > $null
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('dfctl-go').Show([Windows.UI.Notifications.ToastNotification]::new($xml))`,
+			powershellString(title), powershellString(message))
+		return []string{"powershell", "-NoProfile", "-NonInteractive", "-Command", script}, nil
+	default:
+		return nil, fmt.Errorf("desktop notifications are not supported on %s", ri.OS)
+	}
+}
+
+func appleScriptString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func powershellString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// notify shows a desktop notification if notifications are enabled.
+// Failing to notify never fails the calling operation.
+func (e *executor) notify(title, message string) {
+	cfg, err := e.config()
+	if err != nil || !cfg.Notifications.Enabled {
+		return
+	}
+	argv, err := notificationCommand(system.Get(), title, message)
+	if err != nil {
+		log.Debug().Err(err).Msg("skipping desktop notification")
+		return
+	}
+	if err = exec.Command(argv[0], argv[1:]...).Run(); err != nil {
+		log.Debug().Err(err).Msgf("failed to send desktop notification using %s", argv[0])
+	}
+}
+
+// notifyFinished notifies about a long running operation started in a terminal, so users can switch away while it runs
+func (e *executor) notifyFinished(message string, started time.Time) {
+	if !isTerminal(e.Streams.Out) {
+		return
+	}
+	cfg, err := e.config()
+	if err != nil {
+		return
+	}
+	after := cfg.Notifications.After
+	if after == 0 {
+		after = defaultNotifyAfter
+	}
+	if elapsed := time.Since(started); elapsed >= after {
+		e.notify("dfctl-go", fmt.Sprintf("%s after %s", message, elapsed.Round(time.Second)))
+	}
+}
+
+func isTerminal(w interface{}) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
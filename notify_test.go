@@ -0,0 +1,60 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alex-held/dfctl-kit/pkg/system"
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestNotificationCommand(t *testing.T) {
+	testutils.Run(t, "notificationCommand", func(g *goblin.G) {
+
+		g.It("uses osascript on darwin", func() {
+			argv, err := notificationCommand(system.RuntimeInfo{OS: "darwin"}, "dfctl-go", `installed "1.22.2"`)
+			Ω(err).Should(Succeed())
+			Ω(argv).Should(Equal([]string{"osascript", "-e", `display notification "installed \"1.22.2\"" with title "dfctl-go"`}))
+		})
+
+		g.It("uses notify-send on linux", func() {
+			argv, err := notificationCommand(system.RuntimeInfo{OS: "linux"}, "dfctl-go", "installed 1.22.2")
+			Ω(err).Should(Succeed())
+			Ω(argv).Should(Equal([]string{"notify-send", "--app-name=dfctl-go", "dfctl-go", "installed 1.22.2"}))
+		})
+
+		g.It("uses a powershell toast on windows", func() {
+			argv, err := notificationCommand(system.RuntimeInfo{OS: "windows"}, "dfctl-go", "it's done")
+			Ω(err).Should(Succeed())
+			Ω(argv[0]).Should(Equal("powershell"))
+			Ω(argv[len(argv)-1]).Should(ContainSubstring("'it''s done'"))
+		})
+
+		g.It("returns error on unsupported platforms", func() {
+			_, err := notificationCommand(system.RuntimeInfo{OS: "plan9"}, "dfctl-go", "installed 1.22.2")
+			Ω(err).ShouldNot(Succeed())
+		})
+	})
+}
+
+func TestNotificationConfig(t *testing.T) {
+	testutils.Run(t, "NotificationConfig", func(g *goblin.G) {
+		ConfigFile = filepath.Join(testutils.TempDir(t), "go.yaml")
+
+		g.AfterEach(func() {
+			_ = os.Remove(ConfigFile)
+		})
+
+		g.It("is loaded from the config file", func() {
+			_ = os.MkdirAll(filepath.Dir(ConfigFile), os.ModePerm)
+			_ = os.WriteFile(ConfigFile, []byte("notifications:\n  enabled: true\n  after: 1m\n"), os.ModePerm)
+			cfg, err := defaultExecutor().config()
+			Ω(err).Should(Succeed())
+			Ω(cfg.Notifications).Should(Equal(NotificationConfig{Enabled: true, After: time.Minute}))
+		})
+	})
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var (
+	errNukeAborted     = errors.New("nuke aborted")
+	errNukeUnconfirmed = errors.New("nuke needs confirmation")
+	errNukeUnsafe      = errors.New("refusing to nuke a system directory")
+)
+
+// nukeOptions configures the nuke command
+type nukeOptions struct {
+	KeepCache bool
+	// Confirm is the install root typed by the user to confirm the nuke
+	Confirm string
+}
+
+func newNukeCmd() *cobra.Command {
+	opts := nukeOptions{}
+	cmd := &cobra.Command{
+		Use:   "nuke",
+		Short: "removes all installed versions, links, shims and the download cache",
+		Long: "removes every installed version, the current link, the shims and the download cache to start from a clean slate; the configuration is kept. " +
+			"The install root has to be typed to confirm, or passed with --confirm when stdin is not a terminal",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateArgsForSubcommand("nuke", args, 0); err != nil {
+				return err
+			}
+			return defaultExecutor().Nuke(opts)
+		},
+	}
+	cmd.Flags().BoolVar(&opts.KeepCache, "keep-cache", false, "keep the downloaded archives")
+	cmd.Flags().StringVar(&opts.Confirm, "confirm", "", "the install root to confirm the nuke without prompting")
+	return cmd
+}
+
+// nukeTargets returns the directories removed by nuke
+func (e *executor) nukeTargets(keepCache bool) []string {
+	targets := []string{e.InstallPath, e.bootstrapPath(), e.ShimPath}
+	if !keepCache {
+		targets = append(targets, e.CachePath)
+	}
+	return targets
+}
+
+// Nuke removes all managed state after the install root got confirmed
+func (e *executor) Nuke(opts nukeOptions) error {
+	targets := e.nukeTargets(opts.KeepCache)
+	home, _ := os.UserHomeDir()
+	for _, target := range targets {
+		if clean := filepath.Clean(target); clean == filepath.Dir(clean) || clean == filepath.Clean(home) || clean == "." {
+			return fmt.Errorf("%w; dir=%s", errNukeUnsafe, target)
+		}
+	}
+
+	_, _ = fmt.Fprintln(e.Streams.Out, "the following directories will be removed:")
+	for _, target := range targets {
+		_, _ = fmt.Fprintf(e.Streams.Out, "  %s\n", target)
+	}
+	confirm := opts.Confirm
+	if confirm == "" {
+		if !isTerminal(e.Streams.In) {
+			return fmt.Errorf("%w; pass --confirm %s", errNukeUnconfirmed, e.InstallPath)
+		}
+		_, _ = fmt.Fprintf(e.Streams.Out, "type the install root %s to confirm: ", e.InstallPath)
+		answer, _ := bufio.NewReader(e.Streams.In).ReadString('\n')
+		confirm = strings.TrimSpace(answer)
+	}
+	if filepath.Clean(confirm) != filepath.Clean(e.InstallPath) {
+		return fmt.Errorf("%w; %q does not match the install root %s", errNukeAborted, confirm, e.InstallPath)
+	}
+
+	// the lock file lives in the install root, which cannot be removed while it is open on windows
+	activeLock.release()
+	for _, target := range targets {
+		if err := e.Fs.RemoveAll(target); err != nil {
+			return fmt.Errorf("failed to remove %s; err=%v", target, err)
+		}
+		_, _ = fmt.Fprintf(e.Streams.Out, "removed %s\n", target)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+func TestNuke(t *testing.T) {
+	testutils.Run(t, "Nuke", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		ShimPath = filepath.Join(testutils.TempDir(t), "shims")
+
+		g.BeforeEach(func() {
+			createVersionDirs()
+			_ = os.MkdirAll(ShimPath, os.ModePerm)
+			_ = os.MkdirAll(CachePath, os.ModePerm)
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+			_ = os.RemoveAll(ShimPath)
+			_ = os.RemoveAll(CachePath)
+		})
+
+		newSut := func() *executor {
+			sut := defaultExecutor()
+			sut.Streams.Out = &Buffer{&bytes.Buffer{}}
+			return sut
+		}
+
+		g.It("removes all managed state", func() {
+			Ω(newSut().Nuke(nukeOptions{Confirm: InstallPath})).Should(Succeed())
+			Ω(InstallPath).ShouldNot(BeADirectory())
+			Ω(ShimPath).ShouldNot(BeADirectory())
+			Ω(CachePath).ShouldNot(BeADirectory())
+		})
+
+		g.It("keeps the cache", func() {
+			Ω(newSut().Nuke(nukeOptions{Confirm: InstallPath, KeepCache: true})).Should(Succeed())
+			Ω(InstallPath).ShouldNot(BeADirectory())
+			Ω(CachePath).Should(BeADirectory())
+		})
+
+		g.It("aborts unless the install root is confirmed", func() {
+			Ω(errors.Is(newSut().Nuke(nukeOptions{Confirm: "/"}), errNukeAborted)).Should(BeTrue())
+			Ω(InstallPath).Should(BeADirectory())
+		})
+
+		g.It("needs confirmation without terminal", func() {
+			sut := newSut()
+			sut.Streams.In = io.NopCloser(&bytes.Buffer{})
+			Ω(errors.Is(sut.Nuke(nukeOptions{}), errNukeUnconfirmed)).Should(BeTrue())
+			Ω(InstallPath).Should(BeADirectory())
+		})
+
+		g.It("refuses to remove system directories", func() {
+			sut := newSut()
+			sut.ShimPath = "/"
+			Ω(errors.Is(sut.Nuke(nukeOptions{Confirm: InstallPath}), errNukeUnsafe)).Should(BeTrue())
+			Ω(InstallPath).Should(BeADirectory())
+		})
+	})
+}
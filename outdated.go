@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// outdatedTrain compares the newest installed version of a release train with its latest release
+type outdatedTrain struct {
+	Minor     string  `json:"minor"`
+	Installed Version `json:"installed"`
+	Latest    Version `json:"latest"`
+	Outdated  bool    `json:"outdated"`
+	Supported bool    `json:"supported"`
+}
+
+func newOutdatedCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "outdated",
+		Short: "lists installed minor lines with newer patch releases",
+		Long:  "compares the newest installed version of every minor line with the latest patch release of the remote release feed, skipping quarantined releases",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateArgsForSubcommand("outdated", args, 0); err != nil {
+				return err
+			}
+			return defaultExecutor().Outdated(c.Context(), outputFormat == outputJSON)
+		},
+	}
+	addJSONAlias(cmd)
+	return cmd
+}
+
+// outdated returns the installed release trains, newest first
+func (e *executor) outdated(ctx context.Context) ([]outdatedTrain, error) {
+	cfg, err := e.config()
+	if err != nil {
+		return nil, err
+	}
+	installed, err := e.installedVersions()
+	if err != nil {
+		return nil, err
+	}
+	remote, err := e.remoteVersions(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	supported := supportedMinors(remote)
+
+	trains := []outdatedTrain{}
+	seen := map[string]bool{}
+	for _, v := range installed {
+		if seen[v.Minor()] || !v.IsStable() {
+			continue
+		}
+		seen[v.Minor()] = true
+		t := outdatedTrain{Minor: v.Minor(), Installed: v, Latest: v, Supported: supported[v.Minor()]}
+		if latest, ok := latestPatch(remote, v.Minor(), cfg); ok && latest.Compare(v) > 0 {
+			t.Latest, t.Outdated = latest, true
+		}
+		trains = append(trains, t)
+	}
+	return trains, nil
+}
+
+// Outdated prints the installed release trains with their latest patch release
+func (e *executor) Outdated(ctx context.Context, asJSON bool) error {
+	trains, err := e.outdated(ctx)
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		enc := json.NewEncoder(e.Streams.Out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(trains)
+	}
+	w := tabwriter.NewWriter(e.Streams.Out, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "MINOR\tINSTALLED\tLATEST\tSTATUS\tSUPPORTED")
+	for _, t := range trains {
+		status := upgradeStatusUpToDate
+		if t.Outdated {
+			status = "outdated"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%t\n", t.Minor, t.Installed, t.Latest, status, t.Supported)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestOutdated(t *testing.T) {
+	testutils.Run(t, "Outdated", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		ConfigFile = filepath.Join(testutils.TempDir(t), "go.yaml")
+		server := newReleaseServer("1.22.1", "1.22.0-rc.1", "1.21.9", "1.21.8", "1.21.3", "1.20.5")
+		var out *Buffer
+
+		g.BeforeEach(func() {
+			for _, v := range []string{"1.20.5", "1.21.3", "1.21.1"} {
+				_ = os.MkdirAll(filepath.Join(InstallPath, v), os.ModePerm)
+			}
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+			_ = os.Remove(ConfigFile)
+		})
+
+		g.After(func() {
+			server.Close()
+		})
+
+		newSut := func() *executor {
+			out = &Buffer{&bytes.Buffer{}}
+			sut := defaultExecutor()
+			sut.URL = server.URL
+			sut.Streams.Out = out
+			return sut
+		}
+
+		g.It("compares the newest installed version of every minor line", func() {
+			Ω(newSut().outdated(context.Background())).Should(Equal([]outdatedTrain{
+				{Minor: "1.21", Installed: "1.21.3", Latest: "1.21.9", Outdated: true, Supported: true},
+				{Minor: "1.20", Installed: "1.20.5", Latest: "1.20.5", Outdated: false, Supported: false},
+			}))
+		})
+
+		g.It("skips quarantined releases", func() {
+			_ = os.MkdirAll(filepath.Dir(ConfigFile), os.ModePerm)
+			_ = os.WriteFile(ConfigFile, []byte("quarantine:\n  - 1.21.9\n"), os.ModePerm)
+			trains, err := newSut().outdated(context.Background())
+			Ω(err).Should(Succeed())
+			Ω(trains[0].Latest).Should(Equal(Version("1.21.8")))
+		})
+
+		g.It("prints json", func() {
+			Ω(newSut().Outdated(context.Background(), true)).Should(Succeed())
+			var trains []map[string]interface{}
+			Ω(json.Unmarshal(out.Bytes(), &trains)).Should(Succeed())
+			Ω(trains[0]).Should(HaveKeyWithValue("latest", "1.21.9"))
+			Ω(trains[0]).Should(HaveKeyWithValue("outdated", true))
+		})
+	})
+}
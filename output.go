@@ -0,0 +1,300 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// outputText prints human readable output and errors
+	outputText = "text"
+	// outputJSON prints machine readable output and reports failures as jsonError on stdout
+	outputJSON = "json"
+)
+
+// errorSchemaVersion is the version of the jsonError contract; it is incremented on incompatible changes only
+const errorSchemaVersion = 1
+
+// outputFormat is set by the --output flag
+var outputFormat = outputText
+
+// outputSchemaVersion is the version of the versionsOutput contract; it is incremented on incompatible changes only
+const outputSchemaVersion = 1
+
+// versionEntry describes a version in the output of --output json
+type versionEntry struct {
+	Version Version `json:"version"`
+	// Path is the GOROOT of the version, which does not exist anymore after an uninstall
+	Path string `json:"path"`
+	// Current is set if the version is linked as current version
+	Current bool `json:"current"`
+	// Size is the size of the regular files of the version in bytes
+	Size     int64 `json:"size"`
+	External bool  `json:"external,omitempty"`
+	// Remote is set for releases of the release feed which are not installed, listed by list --all
+	Remote bool `json:"remote,omitempty"`
+}
+
+// versionsOutput is the envelope printed by list, current, install and uninstall with --output json.
+// Errors lists problems with single versions which did not fail the command; failures are reported as jsonError.
+type versionsOutput struct {
+	SchemaVersion int            `json:"schema_version"`
+	Versions      []versionEntry `json:"versions"`
+	Errors        []jsonError    `json:"errors,omitempty"`
+}
+
+// jsonError is the contract of failures printed with --output json.
+// It is written as a single line to stdout and the exit code is the same as without --output json.
+// Wrappers should branch on Code, which is stable across releases, and treat Message as human readable text.
+// Details holds the key=value context of the error, e.g. the version, and failures of bulk operations.
+type jsonError struct {
+	SchemaVersion int                    `json:"schema_version"`
+	Code          string                 `json:"code"`
+	Message       string                 `json:"message"`
+	Details       map[string]interface{} `json:"details,omitempty"`
+	Remediation   string                 `json:"remediation,omitempty"`
+}
+
+// errorCode is the stable code and generic remediation of a sentinel error
+type errorCode struct {
+	err         error
+	code        string
+	remediation string
+}
+
+// errorCodes maps sentinel errors to their codes; the first match wins
+var errorCodes = []errorCode{
+	{context.Canceled, "interrupted", ""},
+	{errLocked, "locked", "wait for the other dfctl-go run to finish"},
+	{ErrVersionNotInstalled, "not_installed", "run 'dfctl-go install <version>'"},
+	{errNoCurrentVersion, "no_current_version", "run 'dfctl-go use <version>'"},
+	{errNoPreviousVersion, "no_previous_version", "run 'dfctl-go use <version>'"},
+	{errVersionInUse, "version_in_use", "run 'dfctl-go use <version>' to link another version first"},
+	{errExternalVersion, "external_version", "remove the version with the tool managing it"},
+	{errNoMatchingRelease, "no_matching_release", "check the version against the releases of the release feed"},
+	{errUnreleased, "unreleased", "run 'dfctl-go watch --for <version> --install'"},
+	{errUnknownChannel, "unknown_channel", "use a channel like 1.23@rc"},
+	{errUnsatisfied, "unsatisfied", ""},
+	{errInvalidAlias, "invalid_alias", ""},
+	{errUnknownAlias, "unknown_alias", "run 'dfctl-go alias list'"},
+	{errUpgradePinned, "pinned", "remove the pin from the config"},
+	{errPinDrift, "pin_drift", "run 'dfctl-go use' in the project directory"},
+	{errNoProjectPin, "no_project_pin", "run 'dfctl-go local <version>'"},
+	{errNoGoMod, "no_go_mod", "pass a version"},
+	{errChecksumMismatch, "checksum_mismatch", "remove the archive from the cache and download it again"},
+	{errModified, "modified", "run 'dfctl-go repair <version>'"},
+	{errNoManifest, "no_manifest", "run 'dfctl-go repair <version>'"},
+	{errIncompleteExtraction, "incomplete_extraction", "install the version again"},
+	{errUnsafeArchiveEntry, "unsafe_archive", "verify the source of the archive"},
+	{errSandboxUnsupported, "sandbox_unsupported", ""},
+	{errNotCached, "not_cached", "install the version once without --offline"},
+	{errNotCompiledIn, "not_compiled_in", "use a build of dfctl-go which includes the feature"},
+	{errConfigInvalid, "config_invalid", "run 'dfctl-go config edit' to fix the config"},
+	{errUnhealthy, "unhealthy", "run 'dfctl-go doctor' to see the failed checks"},
+	{errInconsistentState, "inconsistent_state", "run 'dfctl-go fsck --repair'"},
+	{errRepairUnconfirmed, "unconfirmed", "pass --yes"},
+	{errUnsupportedShell, "unsupported_shell", ""},
+	{errUnsupportedHost, "unsupported_host", ""},
+	{errUnsupportedInstallKind, "unsupported_install_kind", ""},
+	{errUnknownArchiveVersion, "unknown_archive_version", "pass the version as argument"},
+	{errNoReleaseAsset, "no_release_asset", ""},
+	{errUnknownBuildVersion, "unknown_build_version", "pass --force"},
+	{errFileExists, "file_exists", ""},
+	{errNukeAborted, "aborted", ""},
+	{errNukeUnconfirmed, "unconfirmed", "pass --confirm <install root>"},
+	{errNukeUnsafe, "unsafe", ""},
+	{errPruneAborted, "aborted", ""},
+	{errPruneUnconfirmed, "unconfirmed", "pass --yes"},
+	{errDestNotEmpty, "dest_not_empty", ""},
+	{errUnsupportedArchive, "unsupported_archive", ""},
+}
+
+// newJSONError returns the jsonError describing err.
+// Segments of the error message like version=1.22.1 become details, a segment starting with run becomes the remediation.
+func newJSONError(err error) jsonError {
+	j := jsonError{SchemaVersion: errorSchemaVersion, Code: "error", Message: err.Error()}
+	var bulkErr *bulkError
+	if errors.As(err, &bulkErr) {
+		j.Code = "bulk_failure"
+		if bulkErr.Partial() {
+			j.Code = "partial_failure"
+		}
+		failures := make([]map[string]string, 0, len(bulkErr.Failures))
+		for _, f := range bulkErr.Failures {
+			failures = append(failures, map[string]string{"item": f.Item, "error": f.Err.Error()})
+		}
+		j.Details = map[string]interface{}{"total": bulkErr.Total, "failures": failures}
+		return j
+	}
+	for _, c := range errorCodes {
+		if errors.Is(err, c.err) {
+			j.Code, j.Remediation = c.code, c.remediation
+			break
+		}
+	}
+	for _, segment := range strings.Split(err.Error(), "; ") {
+		if kv := strings.SplitN(segment, "=", 2); len(kv) == 2 && !strings.ContainsAny(kv[0], " '") {
+			if j.Details == nil {
+				j.Details = map[string]interface{}{}
+			}
+			j.Details[kv[0]] = kv[1]
+		} else if strings.HasPrefix(segment, "run '") {
+			j.Remediation = segment
+		}
+	}
+	return j
+}
+
+// writeJSONError writes err to w following the jsonError contract
+func writeJSONError(w io.Writer, err error) {
+	data, merr := json.Marshal(newJSONError(err))
+	if merr != nil {
+		data = []byte(fmt.Sprintf(`{"schema_version":%d,"code":"error","message":%q}`, errorSchemaVersion, err.Error()))
+	}
+	_, _ = fmt.Fprintln(w, string(data))
+}
+
+// jsonAlias is the deprecated --json flag of the commands which printed json before --output, which sets --output json
+type jsonAlias struct{}
+
+func (jsonAlias) String() string {
+	return strconv.FormatBool(outputFormat == outputJSON)
+}
+
+func (jsonAlias) Set(s string) error {
+	v, err := strconv.ParseBool(s)
+	if v {
+		outputFormat = outputJSON
+	}
+	return err
+}
+
+func (jsonAlias) Type() string {
+	return "bool"
+}
+
+// addJSONAlias adds the deprecated --json flag to cmd, which is an alias of --output json
+func addJSONAlias(cmd *cobra.Command) {
+	f := cmd.Flags().VarPF(jsonAlias{}, "json", "", "print json like --output json")
+	f.NoOptDefVal = "true"
+	_ = cmd.Flags().MarkDeprecated("json", "use --output json instead")
+}
+
+// validateOutputFormat fails for unsupported values of --output
+func validateOutputFormat(format string) error {
+	switch format {
+	case outputText, outputJSON:
+		return nil
+	}
+	return fmt.Errorf("unsupported output format %q; supported formats are %s and %s", format, outputText, outputJSON)
+}
+
+// describe returns the versionEntry of version at path; the info is returned with the error if path cannot be sized
+func (e *executor) describe(version Version, path string, external bool) (versionEntry, error) {
+	info := versionEntry{Version: version, Path: path, External: external}
+	if current, err := e.current(); err == nil {
+		info.Current = current.Compare(version) == 0
+	}
+	size, err := dirSize(e.Fs, path)
+	if err != nil {
+		return info, fmt.Errorf("failed to determine the size of go %s; version=%s; err=%v", version, version, err)
+	}
+	info.Size = size
+	return info, nil
+}
+
+// describeInstalled returns the versionEntry of the installed or external version
+func (e *executor) describeInstalled(version Version) (versionEntry, error) {
+	if path, err := e.versionPath(version); err == nil {
+		return e.describe(version, path, false)
+	}
+	if ext, ok := e.external(version); ok {
+		return e.describe(version, ext.Root, true)
+	}
+	if version == SystemVersion {
+		if root, err := e.systemGoroot(); err == nil {
+			return e.describe(version, root, true)
+		}
+	}
+	return versionEntry{}, fmt.Errorf("%w; version=%s", ErrVersionNotInstalled, version)
+}
+
+// writeVersions prints infos as versionsOutput, or with the --format template if set;
+// errs are problems with single versions which did not fail the command
+func (e *executor) writeVersions(infos []versionEntry, errs []error) error {
+	if e.Format != "" {
+		return e.writeFormattedVersions(infos, errs)
+	}
+	out := versionsOutput{SchemaVersion: outputSchemaVersion, Versions: infos}
+	if out.Versions == nil {
+		out.Versions = []versionEntry{}
+	}
+	for _, err := range errs {
+		out.Errors = append(out.Errors, newJSONError(err))
+	}
+	enc := json.NewEncoder(e.Streams.Out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// writeInstalled prints the installed versions as versionsOutput
+func (e *executor) writeInstalled(versions ...Version) error {
+	infos := make([]versionEntry, 0, len(versions))
+	var errs []error
+	for _, version := range versions {
+		info, err := e.describeInstalled(version)
+		if errors.Is(err, ErrVersionNotInstalled) {
+			return err
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+		infos = append(infos, info)
+	}
+	return e.writeVersions(infos, errs)
+}
+
+// listEntries returns the installed and external versions matching the constraint of list; errs are problems with single versions
+func (e *executor) listEntries() (infos []versionEntry, errs []error, err error) {
+	installs, err := e.installations()
+	if err != nil {
+		return nil, nil, err
+	}
+	seen := map[Version]bool{}
+	for _, i := range installs {
+		if ok, err := e.matches(i.Version); err != nil {
+			return nil, nil, err
+		} else if seen[i.Version] || !ok {
+			continue
+		}
+		seen[i.Version] = true
+		info, err := e.describe(i.Version, filepath.Join(e.InstallPath, i.Dir), false)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		infos = append(infos, info)
+	}
+	sort.SliceStable(infos, func(i, j int) bool {
+		return infos[i].Version.Compare(infos[j].Version) > 0
+	})
+	for _, ext := range e.externals() {
+		if ok, _ := e.matches(ext.Version); !ok {
+			continue
+		}
+		info, err := e.describe(ext.Version, ext.Root, true)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		infos = append(infos, info)
+	}
+	return infos, errs, nil
+}
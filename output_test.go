@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+func TestJSONError(t *testing.T) {
+	testutils.Run(t, "jsonError", func(g *goblin.G) {
+		g.It("maps sentinel errors to codes", func() {
+			j := newJSONError(fmt.Errorf("failed to link; %w", ErrVersionNotInstalled))
+			Ω(j.SchemaVersion).Should(Equal(errorSchemaVersion))
+			Ω(j.Code).Should(Equal("not_installed"))
+			Ω(j.Message).Should(Equal("failed to link; go version is not installed locally"))
+			Ω(j.Remediation).Should(Equal("run 'dfctl-go install <version>'"))
+		})
+
+		g.It("extracts details and remediation from the message", func() {
+			j := newJSONError(fmt.Errorf("%w; version=1.23.4; newest=1.23.3; run 'dfctl-go watch --for 1.23.4 --install' to install it once it is published", errUnreleased))
+			Ω(j.Code).Should(Equal("unreleased"))
+			Ω(j.Details).Should(Equal(map[string]interface{}{"version": "1.23.4", "newest": "1.23.3"}))
+			Ω(j.Remediation).Should(Equal("run 'dfctl-go watch --for 1.23.4 --install' to install it once it is published"))
+		})
+
+		g.It("reports the failures of bulk operations", func() {
+			err := newBulkError("upgrade", "minor lines", 2, []bulkFailure{{Item: "1.20", Err: errors.New("boom")}})
+			j := newJSONError(err)
+			Ω(j.Code).Should(Equal("partial_failure"))
+			Ω(j.Details).Should(HaveKeyWithValue("total", 2))
+			Ω(j.Details).Should(HaveKeyWithValue("failures", []map[string]string{{"item": "1.20", "error": "boom"}}))
+		})
+
+		g.It("reports interruptions", func() {
+			Ω(newJSONError(fmt.Errorf("download failed; %w", context.Canceled)).Code).Should(Equal("interrupted"))
+		})
+
+		g.It("falls back to a generic code", func() {
+			j := newJSONError(errors.New("something went wrong"))
+			Ω(j.Code).Should(Equal("error"))
+			Ω(j.Details).Should(BeNil())
+		})
+
+		g.It("writes a single json line", func() {
+			buf := &bytes.Buffer{}
+			writeJSONError(buf, errLocked)
+			Ω(buf.String()).Should(HaveSuffix("}\n"))
+			Ω(bytes.Count(buf.Bytes(), []byte("\n"))).Should(Equal(1))
+			var j map[string]interface{}
+			Ω(json.Unmarshal(buf.Bytes(), &j)).Should(Succeed())
+			Ω(j).Should(HaveKeyWithValue("schema_version", BeNumerically("==", errorSchemaVersion)))
+			Ω(j).Should(HaveKeyWithValue("code", "locked"))
+		})
+
+		g.It("validates the output format", func() {
+			Ω(validateOutputFormat(outputJSON)).Should(Succeed())
+			Ω(validateOutputFormat("yaml")).ShouldNot(Succeed())
+		})
+	})
+}
+
+func TestVersionsOutput(t *testing.T) {
+	testutils.Run(t, "versionsOutput", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		var out *Buffer
+		var sut *executor
+		var externalRoots []string
+
+		g.BeforeEach(func() {
+			externalRoots, ExternalGoRoots = ExternalGoRoots, nil
+			createVersionDirs()
+			_ = afero.WriteFile(afero.NewOsFs(), filepath.Join(InstallPath, "v1.16.8", "VERSION"), []byte("go1.16.8"), 0644)
+			symlink(afero.NewOsFs(), filepath.Join(InstallPath, "v1.16.8"), filepath.Join(InstallPath, "current"))
+			out = &Buffer{&bytes.Buffer{}}
+			sut = defaultExecutor()
+			sut.Streams.Out = out
+			sut.Output = outputJSON
+		})
+
+		g.AfterEach(func() {
+			ExternalGoRoots = externalRoots
+			_ = os.RemoveAll(InstallPath)
+		})
+
+		decode := func() versionsOutput {
+			var o versionsOutput
+			Ω(json.Unmarshal(out.Bytes(), &o)).Should(Succeed())
+			Ω(o.SchemaVersion).Should(Equal(outputSchemaVersion))
+			return o
+		}
+
+		g.It("lists the installed versions", func() {
+			Ω(sut.List()).Should(Succeed())
+			o := decode()
+			Ω(o.Versions).Should(HaveLen(len(Versions)))
+			Ω(o.Versions).Should(ContainElement(versionEntry{Version: "1.16.8", Path: filepath.Join(InstallPath, "v1.16.8"), Current: true, Size: 8}))
+			Ω(o.Versions).Should(ContainElement(versionEntry{Version: "1.17.1", Path: filepath.Join(InstallPath, "v1.17.1")}))
+			Ω(o.Errors).Should(BeEmpty())
+		})
+
+		g.It("prints an empty list without versions", func() {
+			_ = os.RemoveAll(InstallPath)
+			_ = os.MkdirAll(InstallPath, 0755)
+			Ω(sut.List()).Should(Succeed())
+			Ω(out.String()).Should(ContainSubstring(`"versions": []`))
+		})
+
+		g.It("prints the uninstalled version", func() {
+			Ω(sut.Uninstall("1.17.1")).Should(Succeed())
+			o := decode()
+			Ω(o.Versions).Should(Equal([]versionEntry{{Version: "1.17.1", Path: filepath.Join(InstallPath, "v1.17.1")}}))
+		})
+
+		g.It("prints the versions removed by uninstall --all-except-current", func() {
+			Ω(sut.UninstallAllExceptCurrent()).Should(Succeed())
+			o := decode()
+			Ω(o.Versions).Should(HaveLen(len(Versions) - 1))
+			Ω(out.String()).ShouldNot(ContainSubstring("kept go"))
+		})
+
+		g.It("fails for versions which are not installed", func() {
+			Ω(errors.Is(sut.writeInstalled("1.99.1"), ErrVersionNotInstalled)).Should(BeTrue())
+			Ω(out.String()).Should(BeEmpty())
+		})
+	})
+}
+
+func TestJSONAlias(t *testing.T) {
+	testutils.Run(t, "--json", func(g *goblin.G) {
+
+		g.AfterEach(func() {
+			outputFormat = outputText
+		})
+
+		g.It("is a deprecated alias of --output json", func() {
+			for _, name := range []string{"outdated", "info", "history", "version"} {
+				outputFormat = outputText
+				cmd, _, err := NewCmd().Find([]string{name})
+				Ω(err).Should(Succeed())
+				Ω(cmd.ParseFlags([]string{"--json"})).Should(Succeed())
+				Ω(outputFormat).Should(Equal(outputJSON), name)
+				Ω(cmd.Flags().Lookup("json").Deprecated).ShouldNot(BeEmpty())
+			}
+		})
+
+		g.It("keeps the text output if it is false", func() {
+			cmd, _, err := NewCmd().Find([]string{"history"})
+			Ω(err).Should(Succeed())
+			Ω(cmd.ParseFlags([]string{"--json=false"})).Should(Succeed())
+			Ω(outputFormat).Should(Equal(outputText))
+		})
+	})
+}
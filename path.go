@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+func newPathCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "path [version]",
+		Short: "prints the GOROOT of a version",
+		Long: "prints the absolute GOROOT of an installed version; without version the version in effect for the working directory is used, " +
+			"which makes it easy to use in Makefiles, IDE settings and ci scripts",
+		Example:           "  GOROOT=$(dfctl-go path)\n  dfctl-go path 1.21",
+		ValidArgsFunction: completeInstalledVersion,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateMaxArgsForSubcommand("path", args, 1); err != nil {
+				return err
+			}
+			arg := ""
+			if len(args) == 1 {
+				arg = args[0]
+			}
+			return defaultExecutor().Path(c.Context(), arg)
+		},
+	}
+}
+
+// Path prints the absolute GOROOT of the version resolved from arg, or of the version in effect for the working directory if arg is empty
+func (e *executor) Path(ctx context.Context, arg string) error {
+	version, err := e.execVersion(ctx, arg)
+	if err != nil {
+		return err
+	}
+	goroot, err := e.goroot(version)
+	if err != nil {
+		return fmt.Errorf("%w; version=%s", err, version)
+	}
+	if goroot, err = filepath.Abs(goroot); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintln(e.Streams.Out, goroot)
+	return nil
+}
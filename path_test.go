@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/env"
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+)
+
+func TestPath(t *testing.T) {
+	testutils.Run(t, "Path", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		wd, _ := os.Getwd()
+		outside := testutils.TempDir(t, "outside")
+		var out *Buffer
+
+		g.BeforeEach(func() {
+			createVersionDirs()
+			// outside of modules and projects the global version is in effect
+			_ = os.MkdirAll(outside, os.ModePerm)
+			_ = os.Chdir(outside)
+		})
+
+		g.AfterEach(func() {
+			env.ClearOverrides()
+			_ = os.RemoveAll(InstallPath)
+			_ = os.Chdir(wd)
+		})
+
+		newSut := func() *executor {
+			out = &Buffer{&bytes.Buffer{}}
+			sut := defaultExecutor()
+			sut.Streams.Out = out
+			return sut
+		}
+
+		g.It("prints the GOROOT of the version", func() {
+			Ω(newSut().Path(context.Background(), "1.17")).Should(Succeed())
+			Ω(out.String()).Should(Equal(filepath.Join(InstallPath, "v1.17.1") + "\n"))
+		})
+
+		g.It("prints the GOROOT of the version in effect without version", func() {
+			symlink(afero.NewOsFs(), filepath.Join(InstallPath, "v1.16.8"), filepath.Join(InstallPath, "current"))
+			env.Overrides.Vars = env.Vars{SessionVersionEnv: ""}
+			Ω(newSut().Path(context.Background(), "")).Should(Succeed())
+			Ω(out.String()).Should(Equal(filepath.Join(InstallPath, "v1.16.8") + "\n"))
+		})
+
+		g.It("fails for versions which are not installed", func() {
+			err := newSut().Path(context.Background(), "1.18.0")
+			Ω(err).Should(MatchError(ContainSubstring(ErrVersionNotInstalled.Error())))
+			Ω(out.String()).Should(BeEmpty())
+		})
+	})
+}
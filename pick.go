@@ -0,0 +1,17 @@
+package main
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// versionArgOrPick returns the version argument like versionArg. Without argument and go.mod it lets the user pick
+// an installed version, or a release of the release feed if remote is set, when running in a terminal.
+func (e *executor) versionArgOrPick(ctx context.Context, args []string, remote bool) (string, error) {
+	arg, err := e.versionArg(args)
+	if errors.Is(err, errNoGoMod) && e.interactive() {
+		return e.pickVersion(ctx, remote)
+	}
+	return arg, err
+}
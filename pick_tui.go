@@ -0,0 +1,43 @@
+//go:build !notui
+// +build !notui
+
+package main
+
+import (
+	"context"
+
+	"github.com/alex-held/dfctl-go/pkg/picker"
+	"github.com/rs/zerolog/log"
+)
+
+// interactive reports whether versions can be picked interactively, which needs a terminal
+func (e *executor) interactive() bool {
+	return !e.Porcelain && e.Output != outputJSON && picker.IsInteractive(e.Streams.In, e.Streams.Err)
+}
+
+// pickVersion prompts the user to select an installed version, or a release of the release feed if remote is set.
+// The items are labeled with their status and the current version is highlighted.
+func (e *executor) pickVersion(ctx context.Context, remote bool) (string, error) {
+	entries, errs, err := e.listEntries()
+	prompt := "select the go version to use"
+	if remote {
+		entries, errs, err = e.listAllEntries(ctx)
+		prompt = "select the go version to install"
+	}
+	if err != nil {
+		return "", err
+	}
+	for _, err := range errs {
+		log.Warn().Err(err).Send()
+	}
+
+	items := make([]picker.Item, 0, len(entries))
+	for _, entry := range entries {
+		items = append(items, picker.Item{Value: entry.Version.String(), Labels: []string{entry.status()}, Highlight: entry.Current})
+	}
+	item, err := picker.New(prompt, items, picker.WithIO(e.Streams.In, e.Streams.Err), picker.WithColor(colorEnabled(e.Streams.Err))).Run()
+	if err != nil {
+		return "", err
+	}
+	return item.Value, nil
+}
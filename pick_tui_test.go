@@ -0,0 +1,78 @@
+//go:build !notui
+// +build !notui
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alex-held/dfctl-go/pkg/picker"
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+)
+
+func TestPickVersion(t *testing.T) {
+	testutils.Run(t, "pickVersion", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		server := newReleaseServer("1.22.1", "1.21.9")
+		var errOut *Buffer
+		var sut *executor
+		var externalRoots []string
+
+		g.BeforeEach(func() {
+			externalRoots, ExternalGoRoots = ExternalGoRoots, nil
+			createVersionDirs()
+			symlink(afero.NewOsFs(), filepath.Join(InstallPath, "v1.17.1"), filepath.Join(InstallPath, "current"))
+			errOut = &Buffer{&bytes.Buffer{}}
+			sut = defaultExecutor()
+			sut.URL = server.URL
+			sut.Streams.Err = errOut
+		})
+
+		g.AfterEach(func() {
+			ExternalGoRoots = externalRoots
+			_ = os.RemoveAll(InstallPath)
+		})
+
+		g.After(func() {
+			server.Close()
+		})
+
+		pick := func(input string, remote bool) (string, error) {
+			sut.Streams.In = io.NopCloser(strings.NewReader(input))
+			return sut.pickVersion(context.Background(), remote)
+		}
+
+		g.It("lists the installed versions with their status", func() {
+			Ω(pick("3\n", false)).Should(Equal("1.16.8"))
+			Ω(errOut.String()).Should(ContainSubstring("select the go version to use"))
+			Ω(errOut.String()).Should(ContainSubstring("  1) 1.17.1       current\n"))
+		})
+
+		g.It("lists the releases for install", func() {
+			Ω(pick("1\n", true)).Should(Equal("1.22.1"))
+			Ω(errOut.String()).Should(ContainSubstring("  2) 1.21.9       available\n"))
+		})
+
+		g.It("filters the versions fuzzily", func() {
+			Ω(pick("1.13.5\n\n", false)).Should(Equal("1.13.5"))
+		})
+
+		g.It("aborts on q", func() {
+			_, err := pick("q\n", false)
+			Ω(err).Should(MatchError(picker.ErrAborted))
+		})
+
+		g.It("does not pick without terminal", func() {
+			Ω(sut.interactive()).Should(BeFalse())
+		})
+	})
+}
@@ -0,0 +1,159 @@
+// Package godist names the artifacts of go releases like the go download server does,
+// so tools working with go releases don't have to re-implement its naming rules.
+package godist
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultBaseURL is the download directory of the official go releases
+const DefaultBaseURL = "https://go.dev/dl"
+
+// ErrUnsupportedKind is returned for kinds of artifacts which are not published for a platform or version
+var ErrUnsupportedKind = errors.New("artifact kind is not published for the platform")
+
+// Kind is the kind of a release artifact as named in the release feed
+type Kind string
+
+const (
+	// KindArchive is the sdk archive, a .zip on windows and a .tar.gz everywhere else
+	KindArchive Kind = "archive"
+	// KindInstaller is the .pkg installer on darwin and the .msi installer on windows
+	KindInstaller Kind = "installer"
+	// KindSource is the source archive, which is the same for every platform
+	KindSource Kind = "source"
+	// KindBootstrap is the go 1.4 source snapshot used to bootstrap builds of go from source
+	KindBootstrap Kind = "bootstrap"
+)
+
+// ErrNoBootstrap is returned for versions which are built from source without a go bootstrap toolchain
+var ErrNoBootstrap = errors.New("version is built without a go bootstrap toolchain")
+
+// bootstrapArchive is the name of the last go 1.4 bootstrap snapshot
+const bootstrapArchive = "go1.4-bootstrap-20171003.tar.gz"
+
+// versionPattern matches go versions in go or semver notation, e.g. go1.22.1, 1.23rc1 or 1.23.0-rc.1
+var versionPattern = regexp.MustCompile(`^(?:go|v)?(\d+)\.(\d+)(?:\.(\d+))?(?:-?(beta|rc)\.?(\d+))?$`)
+
+// release is a parsed go version
+type release struct {
+	major, minor, patch int
+	pre                 string
+}
+
+func parse(version string) (release, bool) {
+	m := versionPattern.FindStringSubmatch(strings.TrimSpace(version))
+	if m == nil {
+		return release{}, false
+	}
+	r := release{pre: m[4] + m[5]}
+	r.major, _ = strconv.Atoi(m[1])
+	r.minor, _ = strconv.Atoi(m[2])
+	r.patch, _ = strconv.Atoi(m[3])
+	return r, true
+}
+
+// String returns the version as named by go, e.g. 1.20 for the first release of 1.20 but 1.21.0 since go 1.21
+func (r release) String() string {
+	minor := fmt.Sprintf("%d.%d", r.major, r.minor)
+	switch {
+	case r.pre != "":
+		return minor + r.pre
+	case r.patch == 0 && r.major == 1 && r.minor < 21:
+		return minor
+	default:
+		return fmt.Sprintf("%s.%d", minor, r.patch)
+	}
+}
+
+func (r release) before(major, minor int) bool {
+	return r.major < major || r.major == major && r.minor < minor
+}
+
+// Version returns version as named by go, e.g. 1.21.0 for 1.21 and 1.23rc1 for 1.23.0-rc.1;
+// versions which cannot be parsed are returned without go prefix
+func Version(version string) string {
+	if r, ok := parse(version); ok {
+		return r.String()
+	}
+	return strings.TrimPrefix(version, "go")
+}
+
+// Arch returns the architecture as named in artifact names, e.g. armv6l for arm
+func Arch(arch string) string {
+	if arch == "arm" {
+		return "armv6l"
+	}
+	return arch
+}
+
+// ArtifactName returns the file name of the artifact of kind, e.g. go1.22.1.linux-amd64.tar.gz
+func ArtifactName(version, os, arch string, kind Kind) (string, error) {
+	r, ok := parse(version)
+	name := "go" + Version(version)
+	platform := os + "-" + Arch(arch)
+	// darwin releases before go 1.5 were built for os x 10.6 and 10.8, the 10.8 builds are the ones still in use
+	if ok && os == "darwin" && !r.before(1, 2) && r.before(1, 5) {
+		platform += "-osx10.8"
+	}
+
+	switch kind {
+	case KindArchive:
+		if os == "windows" {
+			return fmt.Sprintf("%s.%s.zip", name, platform), nil
+		}
+		return fmt.Sprintf("%s.%s.tar.gz", name, platform), nil
+	case KindInstaller:
+		switch os {
+		case "darwin":
+			return fmt.Sprintf("%s.%s.pkg", name, platform), nil
+		case "windows":
+			return fmt.Sprintf("%s.%s.msi", name, platform), nil
+		}
+	case KindSource:
+		return name + ".src.tar.gz", nil
+	case KindBootstrap:
+		if ok && r.major == 1 && r.minor == 4 {
+			return bootstrapArchive, nil
+		}
+	}
+	return "", fmt.Errorf("%w; kind=%s; version=%s; platform=%s", ErrUnsupportedKind, kind, version, platform)
+}
+
+// BootstrapVersion returns the oldest go version documented to bootstrap a build of version from source, e.g. 1.20.6 for 1.22.1.
+// Go 1.5 up to 1.19 need go 1.4, go 1.20 and 1.21 need go 1.17.13, and since go 1.22 the final point release
+// of the minor line at least two minor releases before the even release at or below version is required.
+func BootstrapVersion(version string) (string, error) {
+	r, ok := parse(version)
+	switch {
+	case !ok:
+		return "", fmt.Errorf("invalid version %s", version)
+	case r.before(1, 5):
+		return "", fmt.Errorf("%w; version=%s", ErrNoBootstrap, version)
+	case r.before(1, 20):
+		return "1.4", nil
+	case r.before(1, 22):
+		return "1.17.13", nil
+	}
+	even := r.minor - r.minor%2
+	return fmt.Sprintf("%d.%d.6", r.major, even-2), nil
+}
+
+// ArtifactURL returns the download url of the artifact of kind on the official download server
+func ArtifactURL(version, os, arch string, kind Kind) (string, error) {
+	return ArtifactURLAt(DefaultBaseURL, version, os, arch, kind)
+}
+
+// ArtifactURLAt returns the download url of the artifact of kind in the download directory base, e.g. of a mirror
+func ArtifactURLAt(base, version, os, arch string, kind Kind) (string, error) {
+	name, err := ArtifactName(version, os, arch, kind)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(base, "/") + "/" + name, nil
+}
@@ -0,0 +1,94 @@
+package godist_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+
+	"github.com/alex-held/dfctl-go/pkg/godist"
+)
+
+func TestArtifactName(t *testing.T) {
+	testutils.Run(t, "ArtifactName", func(g *goblin.G) {
+		name := func(version, os, arch string, kind godist.Kind) string {
+			n, err := godist.ArtifactName(version, os, arch, kind)
+			Ω(err).Should(Succeed())
+			return n
+		}
+
+		g.It("names archives", func() {
+			Ω(name("1.22.1", "linux", "amd64", godist.KindArchive)).Should(Equal("go1.22.1.linux-amd64.tar.gz"))
+			Ω(name("go1.22.1", "windows", "amd64", godist.KindArchive)).Should(Equal("go1.22.1.windows-amd64.zip"))
+			Ω(name("1.22.1", "linux", "arm", godist.KindArchive)).Should(Equal("go1.22.1.linux-armv6l.tar.gz"))
+		})
+
+		g.It("uses the go version naming", func() {
+			Ω(name("1.20.0", "linux", "amd64", godist.KindArchive)).Should(Equal("go1.20.linux-amd64.tar.gz"))
+			Ω(name("1.21", "linux", "amd64", godist.KindArchive)).Should(Equal("go1.21.0.linux-amd64.tar.gz"))
+			Ω(name("1.23.0-rc.1", "linux", "amd64", godist.KindArchive)).Should(Equal("go1.23rc1.linux-amd64.tar.gz"))
+			Ω(name("1.23beta2", "linux", "amd64", godist.KindArchive)).Should(Equal("go1.23beta2.linux-amd64.tar.gz"))
+		})
+
+		g.It("names installers", func() {
+			Ω(name("1.22.1", "darwin", "arm64", godist.KindInstaller)).Should(Equal("go1.22.1.darwin-arm64.pkg"))
+			Ω(name("1.22.1", "windows", "386", godist.KindInstaller)).Should(Equal("go1.22.1.windows-386.msi"))
+			_, err := godist.ArtifactName("1.22.1", "linux", "amd64", godist.KindInstaller)
+			Ω(errors.Is(err, godist.ErrUnsupportedKind)).Should(BeTrue())
+		})
+
+		g.It("names darwin builds before go 1.5 after their os x version", func() {
+			Ω(name("1.4.2", "darwin", "amd64", godist.KindArchive)).Should(Equal("go1.4.2.darwin-amd64-osx10.8.tar.gz"))
+			Ω(name("1.4.2", "darwin", "amd64", godist.KindInstaller)).Should(Equal("go1.4.2.darwin-amd64-osx10.8.pkg"))
+			Ω(name("1.5", "darwin", "amd64", godist.KindArchive)).Should(Equal("go1.5.darwin-amd64.tar.gz"))
+		})
+
+		g.It("names source and bootstrap archives", func() {
+			Ω(name("1.22.1", "", "", godist.KindSource)).Should(Equal("go1.22.1.src.tar.gz"))
+			Ω(name("1.4", "", "", godist.KindBootstrap)).Should(Equal("go1.4-bootstrap-20171003.tar.gz"))
+			_, err := godist.ArtifactName("1.22.1", "", "", godist.KindBootstrap)
+			Ω(errors.Is(err, godist.ErrUnsupportedKind)).Should(BeTrue())
+		})
+	})
+}
+
+func TestBootstrapVersion(t *testing.T) {
+	testutils.Run(t, "BootstrapVersion", func(g *goblin.G) {
+		bootstrap := func(version string) string {
+			v, err := godist.BootstrapVersion(version)
+			Ω(err).Should(Succeed())
+			return v
+		}
+
+		g.It("follows the documented bootstrap requirements", func() {
+			Ω(bootstrap("1.5")).Should(Equal("1.4"))
+			Ω(bootstrap("1.19.13")).Should(Equal("1.4"))
+			Ω(bootstrap("1.20.1")).Should(Equal("1.17.13"))
+			Ω(bootstrap("1.21.9")).Should(Equal("1.17.13"))
+			Ω(bootstrap("go1.22.1")).Should(Equal("1.20.6"))
+			Ω(bootstrap("1.23rc1")).Should(Equal("1.20.6"))
+			Ω(bootstrap("1.24.0")).Should(Equal("1.22.6"))
+			Ω(bootstrap("1.25.2")).Should(Equal("1.22.6"))
+			Ω(bootstrap("1.26.0")).Should(Equal("1.24.6"))
+		})
+
+		g.It("fails for versions built with a c toolchain", func() {
+			_, err := godist.BootstrapVersion("1.4.3")
+			Ω(errors.Is(err, godist.ErrNoBootstrap)).Should(BeTrue())
+		})
+	})
+}
+
+func TestArtifactURL(t *testing.T) {
+	testutils.Run(t, "ArtifactURL", func(g *goblin.G) {
+		g.It("uses the official download server", func() {
+			Ω(godist.ArtifactURL("1.22.1", "linux", "amd64", godist.KindArchive)).Should(Equal("https://go.dev/dl/go1.22.1.linux-amd64.tar.gz"))
+		})
+
+		g.It("uses the base url", func() {
+			Ω(godist.ArtifactURLAt("https://mirror.corp/dl/", "1.22.1", "linux", "amd64", godist.KindArchive)).Should(Equal("https://mirror.corp/dl/go1.22.1.linux-amd64.tar.gz"))
+		})
+	})
+}
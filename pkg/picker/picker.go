@@ -0,0 +1,221 @@
+// Package picker provides an interactive prompt for selecting a version from a list,
+// which dfctl and its plugins embed to present a consistent selection experience.
+package picker
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/alex-held/dfctl-kit/pkg/color"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// ErrAborted is returned when the user leaves the picker without selecting an item
+var ErrAborted = errors.New("selection aborted")
+
+// DefaultPageSize is the number of items shown at once
+const DefaultPageSize = 15
+
+// Item is a selectable entry of the picker
+type Item struct {
+	// Value is returned when the item is selected, e.g. 1.22.2
+	Value string
+	// Labels are shown next to the value, e.g. installed or current
+	Labels []string
+	// Highlight marks the item, e.g. the current version
+	Highlight bool
+}
+
+// Picker prompts the user to select one of Items
+type Picker struct {
+	Prompt   string
+	Items    []Item
+	In       io.Reader
+	Out      io.Writer
+	PageSize int
+	Color    bool
+}
+
+// Option configures a Picker
+type Option func(p *Picker)
+
+// WithPageSize sets the number of items shown at once
+func WithPageSize(size int) Option {
+	return func(p *Picker) {
+		p.PageSize = size
+	}
+}
+
+// WithColor enables highlighting with ANSI colors
+func WithColor(enabled bool) Option {
+	return func(p *Picker) {
+		p.Color = enabled
+	}
+}
+
+// WithIO sets the streams the picker reads from and renders to
+func WithIO(in io.Reader, out io.Writer) Option {
+	return func(p *Picker) {
+		p.In = in
+		p.Out = out
+	}
+}
+
+// New returns a Picker reading from stdin and rendering to stderr
+func New(prompt string, items []Item, opts ...Option) *Picker {
+	p := &Picker{
+		Prompt:   prompt,
+		Items:    items,
+		In:       os.Stdin,
+		Out:      os.Stderr,
+		PageSize: DefaultPageSize,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// ForCommand returns a Picker using the streams of the cobra command
+func ForCommand(c *cobra.Command, prompt string, items []Item, opts ...Option) *Picker {
+	opts = append([]Option{WithIO(c.InOrStdin(), c.ErrOrStderr())}, opts...)
+	return New(prompt, items, opts...)
+}
+
+// IsInteractive reports whether in and out are attached to a terminal, so the picker can be shown
+func IsInteractive(in io.Reader, out io.Writer) bool {
+	return isTerminal(in) && isTerminal(out)
+}
+
+func isTerminal(s interface{}) bool {
+	f, ok := s.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// Run renders the items and reads the selection.
+// Typing text filters the items fuzzily, typing the number of an item selects it, including the items beyond the page size, and
+// an empty line selects the only remaining item. Reading EOF or q aborts the selection.
+func (p *Picker) Run() (Item, error) {
+	if len(p.Items) == 0 {
+		return Item{}, errors.New("nothing to select")
+	}
+
+	scanner := bufio.NewScanner(p.In)
+	matches := p.Items
+	for {
+		p.render(matches)
+		if !scanner.Scan() {
+			return Item{}, ErrAborted
+		}
+		input := strings.TrimSpace(scanner.Text())
+
+		switch n, err := strconv.Atoi(input); {
+		case input == "q":
+			return Item{}, ErrAborted
+		case input == "" && len(matches) == 1:
+			return matches[0], nil
+		case input == "":
+			matches = p.Items
+		case err == nil && n >= 1 && n <= len(matches):
+			return matches[n-1], nil
+		default:
+			if filtered := Filter(p.Items, input); len(filtered) > 0 {
+				matches = filtered
+			} else {
+				_, _ = fmt.Fprintf(p.Out, "no match for %q\n", input)
+			}
+		}
+	}
+}
+
+func (p *Picker) visible(items []Item) int {
+	if p.PageSize > 0 && len(items) > p.PageSize {
+		return p.PageSize
+	}
+	return len(items)
+}
+
+func (p *Picker) render(items []Item) {
+	_, _ = fmt.Fprintln(p.Out, p.Prompt)
+	n := p.visible(items)
+	for i, item := range items[:n] {
+		line := item.Value
+		if len(item.Labels) > 0 {
+			line = fmt.Sprintf("%-12s %s", item.Value, p.paint(strings.Join(item.Labels, ", "), color.DarkGray))
+		}
+		if item.Highlight {
+			line = p.paint(line, color.Green, color.Bold)
+		}
+		_, _ = fmt.Fprintf(p.Out, "%3d) %s\n", i+1, line)
+	}
+	if hidden := len(items) - n; hidden > 0 {
+		_, _ = fmt.Fprintf(p.Out, "     ... %d more numbered %d to %d, type to filter\n", hidden, n+1, len(items))
+	}
+	_, _ = fmt.Fprint(p.Out, "select a number, type to filter or q to abort: ")
+}
+
+func (p *Picker) paint(s string, codes ...color.AnsiiCode) string {
+	if !p.Color {
+		return s
+	}
+	return color.Colorize(s, codes...)
+}
+
+// Filter returns the items whose value or labels contain the characters of query in order,
+// ranked by how closely together the characters match
+func Filter(items []Item, query string) []Item {
+	type match struct {
+		item  Item
+		score int
+		index int
+	}
+	var matches []match
+	for i, item := range items {
+		text := strings.ToLower(item.Value + " " + strings.Join(item.Labels, " "))
+		if score, ok := fuzzyScore(text, strings.ToLower(query)); ok {
+			matches = append(matches, match{item: item, score: score, index: i})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score < matches[j].score
+	})
+
+	filtered := make([]Item, 0, len(matches))
+	for _, m := range matches {
+		filtered = append(filtered, m.item)
+	}
+	return filtered
+}
+
+// fuzzyScore reports whether the characters of query appear in text in order.
+// The score is the number of characters skipped between the first and last matched character; lower is better.
+func fuzzyScore(text, query string) (score int, ok bool) {
+	if query == "" {
+		return 0, true
+	}
+	start, qi := -1, 0
+	q := []rune(query)
+	for i, r := range []rune(text) {
+		if r != q[qi] {
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+		qi++
+		if qi == len(q) {
+			return i - start + 1 - len(q), true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,87 @@
+package picker_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+
+	"github.com/alex-held/dfctl-go/pkg/picker"
+)
+
+var items = []picker.Item{
+	{Value: "1.22.2", Labels: []string{"installed", "current"}, Highlight: true},
+	{Value: "1.22.1", Labels: []string{"installed"}},
+	{Value: "1.21.9"},
+	{Value: "1.20.14"},
+}
+
+func TestPicker(t *testing.T) {
+	testutils.Run(t, "Picker", func(g *goblin.G) {
+
+		run := func(input string) (picker.Item, error) {
+			p := picker.New("select a go version", items, picker.WithIO(strings.NewReader(input), &bytes.Buffer{}))
+			return p.Run()
+		}
+
+		g.It("selects items by number", func() {
+			Ω(run("2\n")).Should(Equal(items[1]))
+		})
+
+		g.It("selects the only match of a filter", func() {
+			Ω(run("1.20\n\n")).Should(Equal(items[3]))
+		})
+
+		g.It("selects by number within the filtered items", func() {
+			Ω(run("1.22\n2\n")).Should(Equal(items[1]))
+		})
+
+		g.It("returns ErrAborted on q", func() {
+			_, err := run("q\n")
+			Ω(err).Should(Equal(picker.ErrAborted))
+		})
+
+		g.It("returns ErrAborted on EOF", func() {
+			_, err := run("")
+			Ω(err).Should(Equal(picker.ErrAborted))
+		})
+
+		g.It("renders labels", func() {
+			out := &bytes.Buffer{}
+			_, _ = picker.New("select a go version", items, picker.WithIO(strings.NewReader("1\n"), out)).Run()
+			Ω(out.String()).Should(ContainSubstring("1.22.2       installed, current"))
+		})
+
+		g.It("limits the rendered items to the page size", func() {
+			out := &bytes.Buffer{}
+			_, _ = picker.New("select a go version", items, picker.WithIO(strings.NewReader("q\n"), out), picker.WithPageSize(2)).Run()
+			Ω(out.String()).Should(ContainSubstring("... 2 more numbered 3 to 4"))
+		})
+
+		g.It("selects items beyond the page size by number", func() {
+			p := picker.New("select a go version", items, picker.WithIO(strings.NewReader("4\n"), &bytes.Buffer{}), picker.WithPageSize(2))
+			Ω(p.Run()).Should(Equal(items[3]))
+		})
+	})
+}
+
+func TestFilter(t *testing.T) {
+	testutils.Run(t, "Filter", func(g *goblin.G) {
+
+		g.It("matches characters in order", func() {
+			Ω(picker.Filter(items, "2014")).Should(Equal([]picker.Item{items[3]}))
+		})
+
+		g.It("matches labels", func() {
+			Ω(picker.Filter(items, "current")).Should(Equal([]picker.Item{items[0]}))
+		})
+
+		g.It("ranks compact matches first", func() {
+			filtered := picker.Filter(items, "1.21")
+			Ω(filtered[0]).Should(Equal(items[2]))
+		})
+	})
+}
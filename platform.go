@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/alex-held/dfctl-kit/pkg/system"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+
+	"github.com/alex-held/dfctl-go/pkg/godist"
+)
+
+var errUnsupportedArchive = errors.New("unsupported archive format")
+var errDestNotEmpty = errors.New("destination is not empty")
+
+// platformRuntimeInfo returns the platform selected with --os and --arch, defaulting to the host for the omitted one
+func platformRuntimeInfo(goos, goarch string) system.RuntimeInfo {
+	ri := system.OSRuntimeInfoGetter{}.Get()
+	if goos != "" {
+		ri.OS = goos
+	}
+	if goarch != "" {
+		ri.Arch = goarch
+	}
+	return ri
+}
+
+// InstallPlatform downloads the archive of version for the platform ri and extracts it into dest instead of the install path,
+// e.g. to provision a cross-build sysroot or an image for another target.
+// dest must not exist, be empty or hold a go sdk extracted before, which is kept if it is the requested one and only replaced with Force.
+func (e *executor) InstallPlatform(ctx context.Context, version Version, ri system.RuntimeInfo, dest string) error {
+	e.Summary.addVersion(version)
+	dest, err := filepath.Abs(dest)
+	if err != nil {
+		return err
+	}
+	if rel, err := filepath.Rel(e.InstallPath, dest); err == nil && !strings.HasPrefix(rel, "..") {
+		return fmt.Errorf("--dest %s must be outside of the install path %s", dest, e.InstallPath)
+	}
+	name, err := godist.ArtifactName(version.GoName(), ri.OS, ri.Arch, godist.KindArchive)
+	if err != nil {
+		return err
+	}
+	if !strings.HasSuffix(name, ".tar.gz") {
+		return fmt.Errorf("%w; file=%s; only .tar.gz archives can be extracted", errUnsupportedArchive, name)
+	}
+	if entries, err := afero.ReadDir(e.Fs, dest); err == nil && len(entries) > 0 {
+		m, err := e.readManifest(dest)
+		if err != nil {
+			return fmt.Errorf("%w; dest=%s; extract the go sdk into an empty directory", errDestNotEmpty, dest)
+		}
+		matches := m.Version.Compare(version) == 0 && m.OS == ri.OS && m.Arch == ri.Arch
+		switch {
+		case !e.Force && matches:
+			e.infof("go %s for %s/%s is already extracted into %s; pass --force to replace it\n", m.Version, m.OS, m.Arch, dest)
+			return nil
+		case !e.Force:
+			return fmt.Errorf("%w; dest=%s; it holds go %s for %s/%s; pass --force to replace it", errDestNotEmpty, dest, m.Version, m.OS, m.Arch)
+		}
+	}
+
+	f, err := e.releaseFile(ctx, name)
+	if err != nil {
+		return fmt.Errorf("%w; go %s is not published for %s/%s", err, version, ri.OS, ri.Arch)
+	}
+	archive, err := e.dlCachedArchive(ctx, f)
+	if err != nil {
+		return fmt.Errorf("failed downloading go sdk %v for %s/%s from the remote server %s; err=%w", version, ri.OS, ri.Arch, e.URL, err)
+	}
+	if err = e.installArchiveInto(version, archive, e.URL+"/dl/"+name, dest); err != nil {
+		return err
+	}
+	e.infof("extracted go %s for %s/%s into %s\n", version, ri.OS, ri.Arch, dest)
+	return nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/system"
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestInstallPlatform(t *testing.T) {
+	testutils.Run(t, "InstallPlatform", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		arm64 := system.RuntimeInfo{OS: "linux", Arch: "arm64"}
+		server := newPlatformReleaseServer(arm64, []Version{"1.22.1"})
+		sysroot := testutils.TempDir(t, "sysroot")
+		dest := filepath.Join(sysroot, "go")
+		var out *Buffer
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(filepath.Dir(InstallPath))
+			_ = os.RemoveAll(sysroot)
+		})
+
+		g.After(func() {
+			server.Close()
+		})
+
+		newSut := func() *executor {
+			out = &Buffer{&bytes.Buffer{}}
+			sut := defaultExecutor()
+			sut.URL = server.URL
+			sut.Streams.Out = out
+			return sut
+		}
+
+		g.It("extracts the sdk of the platform into dest without installing it", func() {
+			Ω(newSut().InstallPlatform(context.Background(), "1.22.1", arm64, dest)).Should(Succeed())
+			Ω(filepath.Join(dest, "bin", "go")).Should(BeARegularFile())
+			Ω(filepath.Join(InstallPath, "1.22.1")).ShouldNot(BeADirectory())
+			Ω(out.String()).Should(Equal("extracted go 1.22.1 for linux/arm64 into " + dest + "\n"))
+			m, err := newSut().readManifest(dest)
+			Ω(err).Should(Succeed())
+			Ω(m.OS).Should(Equal("linux"))
+			Ω(m.Arch).Should(Equal("arm64"))
+			Ω(m.Source).Should(Equal(server.URL + "/dl/go1.22.1.linux-arm64.tar.gz"))
+		})
+
+		g.It("replaces an extracted sdk only with force", func() {
+			Ω(newSut().InstallPlatform(context.Background(), "1.22.1", arm64, dest)).Should(Succeed())
+			Ω(newSut().InstallPlatform(context.Background(), "1.22.1", arm64, dest)).Should(Succeed())
+			Ω(out.String()).Should(ContainSubstring("pass --force to replace it"))
+			sut := newSut()
+			sut.Force = true
+			Ω(sut.InstallPlatform(context.Background(), "1.22.1", arm64, dest)).Should(Succeed())
+			Ω(out.String()).Should(ContainSubstring("extracted go 1.22.1"))
+		})
+
+		g.It("refuses to replace the sdk of another version or platform without force", func() {
+			amd64 := system.RuntimeInfo{OS: "linux", Arch: "amd64"}
+			other := newPlatformReleaseServer(amd64, []Version{"1.21.5"})
+			defer other.Close()
+			sut := newSut()
+			sut.URL = other.URL
+			Ω(sut.InstallPlatform(context.Background(), "1.21.5", amd64, dest)).Should(Succeed())
+
+			err := newSut().InstallPlatform(context.Background(), "1.22.1", arm64, dest)
+			Ω(errors.Is(err, errDestNotEmpty)).Should(BeTrue())
+			Ω(err.Error()).Should(ContainSubstring("it holds go 1.21.5 for linux/amd64"))
+
+			sut = newSut()
+			sut.Force = true
+			Ω(sut.InstallPlatform(context.Background(), "1.22.1", arm64, dest)).Should(Succeed())
+			m, err := newSut().readManifest(dest)
+			Ω(err).Should(Succeed())
+			Ω(m.Version).Should(Equal(Version("1.22.1")))
+			Ω(m.Arch).Should(Equal("arm64"))
+		})
+
+		g.It("refuses to replace other files in dest", func() {
+			_ = os.MkdirAll(dest, os.ModePerm)
+			_ = os.WriteFile(filepath.Join(dest, "notes.txt"), []byte("keep me"), 0644)
+			sut := newSut()
+			sut.Force = true
+			err := sut.InstallPlatform(context.Background(), "1.22.1", arm64, dest)
+			Ω(errors.Is(err, errDestNotEmpty)).Should(BeTrue())
+			Ω(filepath.Join(dest, "notes.txt")).Should(BeARegularFile())
+		})
+
+		g.It("fails for platforms without a published archive", func() {
+			err := newSut().InstallPlatform(context.Background(), "1.22.1", system.RuntimeInfo{OS: "linux", Arch: "riscv64"}, dest)
+			Ω(err).Should(MatchError(ContainSubstring("go 1.22.1 is not published for linux/riscv64")))
+			Ω(dest).ShouldNot(BeADirectory())
+		})
+
+		g.It("rejects zip archives and destinations inside the install path", func() {
+			err := newSut().InstallPlatform(context.Background(), "1.22.1", system.RuntimeInfo{OS: "windows", Arch: "amd64"}, dest)
+			Ω(errors.Is(err, errUnsupportedArchive)).Should(BeTrue())
+			Ω(newSut().InstallPlatform(context.Background(), "1.22.1", arm64, filepath.Join(InstallPath, "1.22.1"))).Should(MatchError(ContainSubstring("must be outside of the install path")))
+		})
+
+		g.It("defaults the omitted platform fields to the host", func() {
+			host := system.OSRuntimeInfoGetter{}.Get()
+			Ω(platformRuntimeInfo("", "arm64")).Should(Equal(system.RuntimeInfo{OS: host.OS, Arch: "arm64"}))
+			Ω(platformRuntimeInfo("linux", "")).Should(Equal(system.RuntimeInfo{OS: "linux", Arch: host.Arch}))
+		})
+	})
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/alex-held/dfctl-kit/pkg/system"
+	"github.com/spf13/cobra"
+
+	"github.com/alex-held/dfctl-go/pkg/godist"
+)
+
+// The plumbing commands are meant for scripts. Their output is part of the stable interface and only changes
+// with a major release: every command prints exactly the documented line to stdout and reports failures with a
+// non-zero exit code, errors are only written to stderr.
+
+func newPlumbingCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "plumbing",
+		Short: "script safe commands with a stable output",
+		Long: "script safe commands with a stable output; unlike the other commands, whose output is meant for humans and may change between releases, " +
+			"the output of the plumbing commands only changes with a major release",
+	}
+	cmd.AddCommand(newPlumbingResolveCmd())
+	cmd.AddCommand(newPlumbingArtifactURLCmd())
+	cmd.AddCommand(newPlumbingVerifyArchiveCmd())
+	cmd.AddCommand(newPlumbingFeaturesCmd())
+	return cmd
+}
+
+func newPlumbingResolveCmd() *cobra.Command {
+	var installed, includeUnstable bool
+	cmd := &cobra.Command{
+		Use:   "resolve [version]",
+		Short: "prints the canonical version a version argument resolves to",
+		Long: "prints the canonical version a version argument resolves to, e.g. 1.22.2, followed by a newline; " +
+			"without version the version in effect for the working directory is printed",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateMaxArgsForSubcommand("plumbing resolve", args, 1); err != nil {
+				return err
+			}
+			e := defaultExecutor()
+			if len(args) == 0 {
+				wd, err := os.Getwd()
+				if err != nil {
+					return err
+				}
+				version, _, err := e.resolved(wd)
+				if err != nil {
+					return err
+				}
+				_, _ = fmt.Fprintln(e.Streams.Out, version)
+				return nil
+			}
+			scope := remoteScope
+			if installed {
+				scope = installedScope
+			}
+			version, err := e.resolveVersion(c.Context(), args[0], scope, includeUnstable)
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintln(e.Streams.Out, version)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&installed, "installed", false, "resolve against the installed versions instead of the release feed")
+	cmd.Flags().BoolVar(&includeUnstable, "include-unstable", false, "let the latest keyword resolve to beta and rc releases")
+	return cmd
+}
+
+func newPlumbingArtifactURLCmd() *cobra.Command {
+	ri := system.Get()
+	kind := string(godist.KindArchive)
+	cmd := &cobra.Command{
+		Use:   "artifact-url <version>",
+		Short: "prints the download url of an artifact of a version",
+		Long:  "prints the download url of an artifact of a version followed by a newline; the version is not checked against the release feed",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateArgsForSubcommand("plumbing artifact-url", args, 1); err != nil {
+				return err
+			}
+			version, err := ParseVersion(args[0])
+			if err != nil {
+				return err
+			}
+			e := defaultExecutor()
+			url, err := godist.ArtifactURLAt(e.URL+"/dl", version.GoName(), ri.OS, ri.Arch, godist.Kind(kind))
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintln(e.Streams.Out, url)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&ri.OS, "os", ri.OS, "operating system of the artifact")
+	cmd.Flags().StringVar(&ri.Arch, "arch", ri.Arch, "architecture of the artifact")
+	cmd.Flags().StringVar(&kind, "kind", kind, "kind of the artifact; archive, installer, source or bootstrap")
+	return cmd
+}
+
+func newPlumbingVerifyArchiveCmd() *cobra.Command {
+	var sha256 string
+	cmd := &cobra.Command{
+		Use:   "verify-archive <file>",
+		Short: "verifies the checksum of a downloaded archive",
+		Long: "verifies the checksum of a downloaded archive against --sha256 or, if omitted, against the release feed entry of the file name; " +
+			"on success the line '<sha256>  <file>' known from sha256sum is printed",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateArgsForSubcommand("plumbing verify-archive", args, 1); err != nil {
+				return err
+			}
+			return defaultExecutor().VerifyArchive(c.Context(), args[0], sha256)
+		},
+	}
+	cmd.Flags().StringVar(&sha256, "sha256", "", "expected hex encoded sha256 checksum")
+	return cmd
+}
+
+// VerifyArchive fails with errChecksumMismatch unless the checksum of file equals expected,
+// which is looked up in the release feed if empty
+func (e *executor) VerifyArchive(ctx context.Context, file, expected string) error {
+	if expected == "" {
+		f, err := e.releaseFile(ctx, filepath.Base(file))
+		if err != nil {
+			return err
+		}
+		expected = f.SHA256
+	}
+	sum, err := fileSHA256(e.Fs, file)
+	if err != nil {
+		return err
+	}
+	if sum != expected {
+		return fmt.Errorf("%w; file=%s; expected=%s; actual=%s", errChecksumMismatch, file, expected, sum)
+	}
+	_, _ = fmt.Fprintf(e.Streams.Out, "%s  %s\n", sum, file)
+	return nil
+}
+
+func newPlumbingFeaturesCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "features",
+		Short: "prints the optional features compiled into the binary",
+		Long:  "prints the names of the optional features compiled into the binary, one per line; packagers remove features with the no<feature> build tags, e.g. -tags notui,nooci",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateArgsForSubcommand("plumbing features", args, 0); err != nil {
+				return err
+			}
+			e := defaultExecutor()
+			for _, feature := range enabledFeatures() {
+				_, _ = fmt.Fprintln(e.Streams.Out, feature)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/system"
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestArtifactURL(t *testing.T) {
+	testutils.Run(t, "artifactURL", func(g *goblin.G) {
+
+		g.It("uses the go release name", func() {
+			sut := defaultExecutor()
+			sut.URL = "https://mirror.example.com"
+			Ω(sut.artifactURL(system.RuntimeInfo{OS: "darwin", Arch: "arm64"}, "1.21.0-rc.2")).Should(Equal("https://mirror.example.com/dl/go1.21rc2.darwin-arm64.tar.gz"))
+		})
+	})
+}
+
+func TestVerifyArchive(t *testing.T) {
+	testutils.Run(t, "VerifyArchive", func(g *goblin.G) {
+		dir := testutils.TempDir(t, "downloads")
+		ri := system.OSRuntimeInfoGetter{}.Get()
+		file := filepath.Join(dir, formatGoArchiveArtifactName(ri, "1.22.1"))
+		server := newReleaseServer("1.22.1")
+		sum := sha256.Sum256(archiveData)
+		var out *Buffer
+
+		g.BeforeEach(func() {
+			_ = os.MkdirAll(dir, os.ModePerm)
+			_ = os.WriteFile(file, archiveData, os.ModePerm)
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(dir)
+		})
+
+		g.After(func() {
+			server.Close()
+		})
+
+		newSut := func() *executor {
+			out = &Buffer{&bytes.Buffer{}}
+			sut := defaultExecutor()
+			sut.URL = server.URL
+			sut.Streams.Out = out
+			return sut
+		}
+
+		g.It("verifies against the release feed", func() {
+			Ω(newSut().VerifyArchive(context.Background(), file, "")).Should(Succeed())
+			Ω(out.String()).Should(Equal(hex.EncodeToString(sum[:]) + "  " + file + "\n"))
+		})
+
+		g.It("verifies against the provided checksum", func() {
+			err := newSut().VerifyArchive(context.Background(), file, "abc")
+			Ω(errors.Is(err, errChecksumMismatch)).Should(BeTrue())
+			Ω(out.String()).Should(BeEmpty())
+		})
+
+		g.It("fails for files missing in the release feed", func() {
+			other := filepath.Join(dir, "go1.0.linux-amd64.tar.gz")
+			_ = os.WriteFile(other, archiveData, os.ModePerm)
+			Ω(errors.Is(newSut().VerifyArchive(context.Background(), other, ""), errNoMatchingRelease)).Should(BeTrue())
+		})
+	})
+}
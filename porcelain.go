@@ -0,0 +1,19 @@
+package main
+
+import (
+	"fmt"
+)
+
+var (
+	// quiet is set by --quiet, which only prints essential values and errors
+	quiet bool
+	// porcelain is set by --porcelain, which prints the plain formats that stay stable across releases
+	porcelain bool
+)
+
+// infof prints an informational message like "removed go 1.21.3", which --quiet suppresses
+func (e *executor) infof(format string, args ...interface{}) {
+	if !e.Quiet {
+		_, _ = fmt.Fprintf(e.Streams.Out, format, args...)
+	}
+}
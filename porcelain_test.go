@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+)
+
+func TestQuiet(t *testing.T) {
+	testutils.Run(t, "--quiet", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		var out *Buffer
+		var sut *executor
+
+		g.BeforeEach(func() {
+			createVersionDirs()
+			symlink(afero.NewOsFs(), filepath.Join(InstallPath, "v1.16.4"), filepath.Join(InstallPath, "current"))
+			out = &Buffer{&bytes.Buffer{}}
+			sut = defaultExecutor()
+			sut.Streams.Out = out
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+		})
+
+		g.It("prints informational messages by default", func() {
+			sut.infof("removed go %s\n", "1.21.3")
+			Ω(out.String()).Should(Equal("removed go 1.21.3\n"))
+		})
+
+		g.It("suppresses informational messages", func() {
+			sut.Quiet = true
+			Ω(sut.UninstallAllExceptCurrent()).Should(Succeed())
+			Ω(filepath.Join(InstallPath, "v1.17.1")).ShouldNot(BeADirectory())
+			Ω(out.String()).Should(BeEmpty())
+		})
+
+		g.It("keeps essential values", func() {
+			sut.Quiet = true
+			Ω(sut.List()).Should(Succeed())
+			Ω(out.String()).Should(HavePrefix("1.17.1\n"))
+		})
+	})
+}
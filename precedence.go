@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/alex-held/dfctl-kit/pkg/env"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// sources of the version in effect, in order of precedence
+const (
+	sourceSession = "session"
+	sourceProject = "project"
+	sourceGoMod   = "go.mod"
+	sourceGlobal  = "global"
+)
+
+// versionSpec is the version requested by one source
+type versionSpec struct {
+	Source string
+	// Spec is the version as configured, e.g. 1.21
+	Spec string
+	// File is the file configuring the version; empty for the session and the global version
+	File string
+}
+
+func (s versionSpec) origin() string {
+	if s.File != "" {
+		return s.File
+	}
+	return s.Source
+}
+
+func newStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "prints the go version in effect and which source it came from",
+		Long:  "prints the go version in effect for the working directory and which source it came from; the session variable " + SessionVersionEnv + " wins over the project pin, which wins over the go.mod, which wins over the global version. The go directive of go.mod is a minimum, so it is skipped with a warning if no matching version is installed",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateArgsForSubcommand("status", args, 0); err != nil {
+				return err
+			}
+			wd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			return defaultExecutor().Status(wd)
+		},
+	}
+}
+
+// specs returns the versions requested for dir by each configured source, ordered by precedence
+func (e *executor) specs(dir string) ([]versionSpec, error) {
+	var specs []versionSpec
+	if v := env.GetVars().Get(SessionVersionEnv); v != "" {
+		specs = append(specs, versionSpec{Source: sourceSession, Spec: v})
+	}
+
+	switch pin, file, err := findProjectPin(e.Fs, dir); {
+	case err == nil:
+		specs = append(specs, versionSpec{Source: sourceProject, Spec: pin, File: file})
+	case err != errNoProjectPin:
+		return nil, err
+	}
+
+	switch v, file, err := findGoModVersion(e.Fs, dir); {
+	case err == nil:
+		specs = append(specs, versionSpec{Source: sourceGoMod, Spec: v, File: file})
+	case err != errNoGoMod:
+		log.Debug().Err(err).Msg("ignoring go.mod")
+	}
+
+	switch current, err := e.current(); {
+	case err == nil:
+		specs = append(specs, versionSpec{Source: sourceGlobal, Spec: current.String()})
+	case err == errNoCurrentVersion && e.systemActive():
+		specs = append(specs, versionSpec{Source: sourceGlobal, Spec: KeywordSystem})
+	case err != errNoCurrentVersion:
+		return nil, err
+	}
+	return specs, nil
+}
+
+// effective resolves the installed version in effect from specs and returns it with the index of the winning spec.
+// The go directive of go.mod is a minimum rather than a pin, so it only wins if a matching version is installed
+// and falls through to the next source otherwise.
+func (e *executor) effective(specs []versionSpec) (version Version, winner int, err error) {
+	if len(specs) == 0 {
+		return "", 0, errNoCurrentVersion
+	}
+	for i, spec := range specs {
+		version, err = e.resolveVersion(context.Background(), spec.Spec, installedScope, false)
+		if err == nil || spec.Source != sourceGoMod || i == len(specs)-1 {
+			return version, i, err
+		}
+		log.Debug().Err(err).Msgf("go %s required by %s is not installed; falling through to %s", spec.Spec, spec.File, specs[i+1].Source)
+	}
+	return version, len(specs) - 1, err
+}
+
+// resolved returns the version in effect for dir, resolved from the source with the highest precedence,
+// and the origin of the version, which is the configuring file or the name of the source
+func (e *executor) resolved(dir string) (version Version, origin string, err error) {
+	specs, err := e.specs(dir)
+	if err != nil {
+		return "", "", err
+	}
+	version, winner, err := e.effective(specs)
+	if err != nil {
+		return "", "", err
+	}
+	return version, specs[winner].origin(), nil
+}
+
+// Status prints the version in effect for dir and every source requesting a version, marking the winning one
+func (e *executor) Status(dir string) error {
+	specs, err := e.specs(dir)
+	if err != nil {
+		return err
+	}
+	if len(specs) == 0 {
+		return errNoCurrentVersion
+	}
+
+	version, winner, err := e.effective(specs)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.Streams.Out, "go %s requested by %s is not installed\n\n", specs[winner].Spec, specs[winner].origin())
+	} else {
+		_, _ = fmt.Fprintf(e.Streams.Out, "go %s from %s\n\n", version, specs[winner].origin())
+	}
+	for _, spec := range specs[:winner] {
+		if spec.Source == sourceGoMod {
+			_, _ = fmt.Fprintf(e.Streams.Err, "warning: go %s required by %s is not installed; install it with 'dfctl-go install %s'\n", spec.Spec, spec.File, spec.Spec)
+		}
+	}
+
+	w := tabwriter.NewWriter(e.Streams.Out, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "\tSOURCE\tVERSION\tFILE")
+	for i, spec := range specs {
+		marker := ""
+		if i == winner {
+			marker = "*"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", marker, spec.Source, spec.Spec, spec.File)
+	}
+	return w.Flush()
+}
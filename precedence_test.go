@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/env"
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+)
+
+func TestResolved(t *testing.T) {
+	testutils.Run(t, "resolved", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		project := filepath.Join(testutils.TempDir(t), "project")
+		nested := filepath.Join(project, "cmd")
+
+		g.BeforeEach(func() {
+			createVersionDirs()
+			_ = os.MkdirAll(nested, os.ModePerm)
+			symlink(afero.NewOsFs(), filepath.Join(InstallPath, "v1.16.8"), filepath.Join(InstallPath, "current"))
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+			_ = os.RemoveAll(project)
+			env.ClearOverrides()
+		})
+
+		g.It("falls back to the global version", func() {
+			version, source, err := defaultExecutor().resolved(nested)
+			Ω(err).Should(Succeed())
+			Ω(version).Should(Equal(Version("1.16.8")))
+			Ω(source).Should(Equal("global"))
+		})
+
+		g.It("prefers go.mod over the global version", func() {
+			_ = os.WriteFile(filepath.Join(project, GoModFile), []byte("module example.com/foo\n\ngo 1.13\n"), os.ModePerm)
+			version, source, err := defaultExecutor().resolved(nested)
+			Ω(err).Should(Succeed())
+			Ω(version).Should(Equal(Version("1.13.5")))
+			Ω(source).Should(Equal(filepath.Join(project, GoModFile)))
+		})
+
+		g.It("falls through to the global version if the go directive is not installed", func() {
+			_ = os.WriteFile(filepath.Join(project, GoModFile), []byte("module example.com/foo\n\ngo 1.99\n"), os.ModePerm)
+			version, source, err := defaultExecutor().resolved(nested)
+			Ω(err).Should(Succeed())
+			Ω(version).Should(Equal(Version("1.16.8")))
+			Ω(source).Should(Equal("global"))
+		})
+
+		g.It("keeps current working in a module whose go directive is not installed", func() {
+			_ = os.WriteFile(filepath.Join(project, GoModFile), []byte("module example.com/foo\n\ngo 1.99\n"), os.ModePerm)
+			wd, _ := os.Getwd()
+			defer func() {
+				_ = os.Chdir(wd)
+			}()
+			Ω(os.Chdir(nested)).Should(Succeed())
+			out := &Buffer{&bytes.Buffer{}}
+			sut := defaultExecutor()
+			sut.Streams.Out = out
+			Ω(sut.Current()).Should(Succeed())
+			Ω(out.String()).Should(Equal("1.16.8"))
+		})
+
+		g.It("fails if the go directive is not installed and there is no global version", func() {
+			_ = os.Remove(filepath.Join(InstallPath, "current"))
+			_ = os.WriteFile(filepath.Join(project, GoModFile), []byte("module example.com/foo\n\ngo 1.99\n"), os.ModePerm)
+			_, _, err := defaultExecutor().resolved(nested)
+			Ω(err).Should(HaveOccurred())
+		})
+
+		g.It("prefers the nearest project pin over go.mod", func() {
+			_ = os.WriteFile(filepath.Join(project, GoModFile), []byte("module example.com/foo\n\ngo 1.13\n"), os.ModePerm)
+			_ = os.WriteFile(filepath.Join(project, ProjectVersionFile), []byte("1.17\n"), os.ModePerm)
+			version, source, err := defaultExecutor().resolved(nested)
+			Ω(err).Should(Succeed())
+			Ω(version).Should(Equal(Version("1.17.1")))
+			Ω(source).Should(Equal(filepath.Join(project, ProjectVersionFile)))
+		})
+
+		g.It("prefers the session version over the project pin", func() {
+			_ = os.WriteFile(filepath.Join(project, ProjectVersionFile), []byte("1.17\n"), os.ModePerm)
+			env.Overrides.Vars = env.Vars{SessionVersionEnv: "1.16.4"}
+			version, source, err := defaultExecutor().resolved(nested)
+			Ω(err).Should(Succeed())
+			Ω(version).Should(Equal(Version("1.16.4")))
+			Ω(source).Should(Equal("session"))
+		})
+	})
+}
+
+func TestStatus(t *testing.T) {
+	testutils.Run(t, "Status", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		project := filepath.Join(testutils.TempDir(t), "project")
+
+		g.BeforeEach(func() {
+			createVersionDirs()
+			_ = os.MkdirAll(project, os.ModePerm)
+			symlink(afero.NewOsFs(), filepath.Join(InstallPath, "v1.16.8"), filepath.Join(InstallPath, "current"))
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+			_ = os.RemoveAll(project)
+		})
+
+		g.It("prints the winning version and all sources", func() {
+			_ = os.WriteFile(filepath.Join(project, ProjectVersionFile), []byte("1.17\n"), os.ModePerm)
+			out := &Buffer{&bytes.Buffer{}}
+			sut := defaultExecutor()
+			sut.Streams.Out = out
+			Ω(sut.Status(project)).Should(Succeed())
+			Ω(out.String()).Should(HavePrefix("go 1.17.1 from " + filepath.Join(project, ProjectVersionFile)))
+			Ω(out.String()).Should(MatchRegexp(`\*\s+project\s+1.17`))
+			Ω(out.String()).Should(MatchRegexp(`\n\s+global\s+1.16.8`))
+		})
+
+		g.It("marks the global version and warns if the go directive is not installed", func() {
+			_ = os.WriteFile(filepath.Join(project, GoModFile), []byte("module example.com/foo\n\ngo 1.99\n"), os.ModePerm)
+			out, errOut := &Buffer{&bytes.Buffer{}}, &Buffer{&bytes.Buffer{}}
+			sut := defaultExecutor()
+			sut.Streams.Out, sut.Streams.Err = out, errOut
+			Ω(sut.Status(project)).Should(Succeed())
+			Ω(out.String()).Should(HavePrefix("go 1.16.8 from global"))
+			Ω(out.String()).Should(MatchRegexp(`\n\s+go.mod\s+1.99`))
+			Ω(out.String()).Should(MatchRegexp(`\*\s+global\s+1.16.8`))
+			Ω(errOut.String()).Should(ContainSubstring("warning: go 1.99 required by " + filepath.Join(project, GoModFile) + " is not installed"))
+		})
+	})
+}
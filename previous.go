@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// previousArg is the version argument of use switching back to the previous version, like cd -
+const previousArg = "-"
+
+// previousMarker is the file in the install path holding the version which was current before the last use
+const previousMarker = "previous"
+
+var errNoPreviousVersion = errors.New("no previous version recorded")
+
+// recordPrevious remembers before as the previous version after current changed from before to after
+func (e *executor) recordPrevious(before, after Version) {
+	if before == "" || before.Compare(after) == 0 {
+		return
+	}
+	_ = afero.WriteFile(e.Fs, filepath.Join(e.InstallPath, previousMarker), []byte(before.String()+"\n"), 0644)
+}
+
+// previous returns the version which was current before the last use
+func (e *executor) previous() (Version, error) {
+	data, err := afero.ReadFile(e.Fs, filepath.Join(e.InstallPath, previousMarker))
+	if err != nil {
+		return "", errNoPreviousVersion
+	}
+	return ParseVersion(strings.TrimSpace(string(data)))
+}
+
+// UsePrevious switches back to the version which was current before the last use
+func (e *executor) UsePrevious() error {
+	version, err := e.previous()
+	if err != nil {
+		return err
+	}
+	if err = e.Use(version); err != nil {
+		return fmt.Errorf("failed to switch back to go %s; %w", version, err)
+	}
+	e.infof("switched back to go %s\n", version)
+	return nil
+}
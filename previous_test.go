@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestUsePrevious(t *testing.T) {
+	testutils.Run(t, "UsePrevious", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		var out *Buffer
+
+		g.BeforeEach(func() {
+			createVersionDirs()
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+		})
+
+		newSut := func() *executor {
+			out = &Buffer{&bytes.Buffer{}}
+			sut := defaultExecutor()
+			sut.Streams.Out = out
+			return sut
+		}
+
+		g.It("switches back to the previous version", func() {
+			sut := newSut()
+			Ω(sut.Use("v1.16.8")).Should(Succeed())
+			Ω(sut.Use("v1.17.1")).Should(Succeed())
+			Ω(sut.UsePrevious()).Should(Succeed())
+			Ω(sut.current()).Should(Equal(Version("1.16.8")))
+			Ω(out.String()).Should(Equal("switched back to go 1.16.8\n"))
+		})
+
+		g.It("toggles between the last two versions", func() {
+			sut := newSut()
+			Ω(sut.Use("v1.16.8")).Should(Succeed())
+			Ω(sut.Use("v1.17.1")).Should(Succeed())
+			Ω(sut.UsePrevious()).Should(Succeed())
+			Ω(sut.UsePrevious()).Should(Succeed())
+			Ω(sut.current()).Should(Equal(Version("1.17.1")))
+		})
+
+		g.It("does not record using the current version again", func() {
+			sut := newSut()
+			Ω(sut.Use("v1.16.8")).Should(Succeed())
+			Ω(sut.Use("v1.17.1")).Should(Succeed())
+			Ω(sut.Use("v1.17.1")).Should(Succeed())
+			Ω(sut.previous()).Should(Equal(Version("1.16.8")))
+		})
+
+		g.It("fails without previous version", func() {
+			Ω(newSut().UsePrevious()).Should(MatchError(errNoPreviousVersion))
+			Ω(filepath.Join(InstallPath, "current")).ShouldNot(BeAnExistingFile())
+		})
+	})
+}
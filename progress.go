@@ -0,0 +1,181 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// phases of an install reported by progressEvents
+const (
+	phaseDownload = "downloading"
+	phaseExtract  = "extracting"
+	phaseRemove   = "removing"
+)
+
+// progressInterval is the minimum interval between two rendered progress updates
+const progressInterval = 100 * time.Millisecond
+
+// progressEvent reports the progress of a phase of an operation
+type progressEvent struct {
+	Phase   string
+	Subject string
+	// Bytes counts the processed bytes of TotalBytes, which is -1 if unknown
+	Bytes, TotalBytes int64
+	// Files counts the extracted archive entries or removed files of TotalFiles; both are zero for downloads
+	Files, TotalFiles int
+	// Done marks the last event of the phase
+	Done bool
+}
+
+// progressFunc receives progressEvents
+type progressFunc func(ev progressEvent)
+
+// progress passes ev to the progress pipeline of the executor, if any
+func (e *executor) progress(ev progressEvent) {
+	if e.Progress != nil {
+		e.Progress(ev)
+	}
+}
+
+// progressRenderer renders progressEvents as a single, throttled updating line on w
+func progressRenderer(w io.Writer) progressFunc {
+	var mu sync.Mutex
+	var last time.Time
+	return func(ev progressEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !ev.Done && time.Since(last) < progressInterval {
+			return
+		}
+		last = time.Now()
+
+		line := fmt.Sprintf("%s %s", ev.Phase, ev.Subject)
+		if ev.TotalFiles > 0 {
+			line += fmt.Sprintf(" %d/%d files", ev.Files, ev.TotalFiles)
+		}
+		if ev.TotalBytes > 0 {
+			line += fmt.Sprintf(" %s/%s (%d%%)", formatBytes(ev.Bytes), formatBytes(ev.TotalBytes), ev.Bytes*100/ev.TotalBytes)
+		} else if ev.Bytes > 0 || ev.TotalFiles == 0 {
+			line += " " + formatBytes(ev.Bytes)
+		}
+		// clear the rest of the previous line
+		_, _ = fmt.Fprintf(w, "\r%s\033[K", line)
+		if ev.Done {
+			_, _ = fmt.Fprintln(w)
+		}
+	}
+}
+
+// multiProgress renders the progressEvents of concurrent operations as one combined, throttled updating line,
+// e.g. "go1.22.1.linux-amd64.tar.gz downloading 45% | go 1.21.9 extracting 80%"
+type multiProgress struct {
+	w    io.Writer
+	mu   sync.Mutex
+	last time.Time
+	// subjects are the subjects in the order of their first event
+	subjects []string
+	events   map[string]progressEvent
+}
+
+func newMultiProgress(w io.Writer) *multiProgress {
+	return &multiProgress{w: w, events: map[string]progressEvent{}}
+}
+
+func (p *multiProgress) report(ev progressEvent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.events[ev.Subject]; !ok {
+		p.subjects = append(p.subjects, ev.Subject)
+	}
+	p.events[ev.Subject] = ev
+	if !ev.Done && time.Since(p.last) < progressInterval {
+		return
+	}
+	p.last = time.Now()
+	_, _ = fmt.Fprintf(p.w, "\r%s\033[K", p.line())
+}
+
+// line summarizes the operations which are not done yet
+func (p *multiProgress) line() string {
+	var parts []string
+	for _, subject := range p.subjects {
+		ev := p.events[subject]
+		if ev.Done {
+			continue
+		}
+		part := fmt.Sprintf("%s %s", subject, ev.Phase)
+		switch {
+		case ev.TotalFiles > 0:
+			part += fmt.Sprintf(" %d%%", ev.Files*100/ev.TotalFiles)
+		case ev.TotalBytes > 0:
+			part += fmt.Sprintf(" %d%%", ev.Bytes*100/ev.TotalBytes)
+		default:
+			part += " " + formatBytes(ev.Bytes)
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " | ")
+}
+
+// finish clears the line once every operation is done
+func (p *multiProgress) finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.subjects) > 0 {
+		_, _ = fmt.Fprint(p.w, "\r\033[K")
+	}
+}
+
+// formatBytes formats n in binary units, e.g. 64.2 MiB
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// progressWriter reports the bytes written through it as progressEvents
+type progressWriter struct {
+	io.Writer
+	ev     progressEvent
+	report progressFunc
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	n, err := w.Writer.Write(p)
+	w.ev.Bytes += int64(n)
+	w.report(w.ev)
+	return n, err
+}
+
+// tarGzipStats counts the entries and the uncompressed bytes of the regular files of a .tar.gz or plain .tar archive
+func tarGzipStats(data []byte) (files int, size int64, err error) {
+	r, err := tarStream(bytes.NewBuffer(data))
+	if err != nil {
+		return 0, 0, err
+	}
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return files, size, nil
+		} else if err != nil {
+			return 0, 0, err
+		}
+		files++
+		if header.Typeflag == tar.TypeReg {
+			size += header.Size
+		}
+	}
+}
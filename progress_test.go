@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestProgress(t *testing.T) {
+	testutils.Run(t, "progress", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		server := newReleaseServer("1.22.1")
+		var events []progressEvent
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+		})
+
+		g.After(func() {
+			server.Close()
+		})
+
+		newSut := func() *executor {
+			events = nil
+			sut := defaultExecutor()
+			sut.URL = server.URL
+			sut.Progress = func(ev progressEvent) {
+				events = append(events, ev)
+			}
+			return sut
+		}
+
+		last := func(phase string) (ev progressEvent) {
+			for _, e := range events {
+				if e.Phase == phase {
+					ev = e
+				}
+			}
+			return ev
+		}
+
+		g.It("reports the downloaded bytes", func() {
+			Ω(newSut().Install("1.22.1")).Should(Succeed())
+			ev := last(phaseDownload)
+			Ω(ev.Done).Should(BeTrue())
+			Ω(ev.Bytes).Should(Equal(int64(len(archiveData))))
+		})
+
+		g.It("reports the extracted files", func() {
+			Ω(newSut().Install("1.22.1")).Should(Succeed())
+			ev := last(phaseExtract)
+			Ω(ev.Done).Should(BeTrue())
+			Ω(ev.Files).Should(Equal(4))
+			Ω(ev.TotalFiles).Should(Equal(4))
+			Ω(ev.Bytes).Should(Equal(ev.TotalBytes))
+		})
+
+		g.It("renders a single updating line", func() {
+			out := &bytes.Buffer{}
+			render := progressRenderer(out)
+			render(progressEvent{Phase: phaseExtract, Subject: "go 1.22.1", Files: 2, TotalFiles: 4, Bytes: 512, TotalBytes: 2048})
+			render(progressEvent{Phase: phaseExtract, Subject: "go 1.22.1", Files: 4, TotalFiles: 4, Bytes: 2048, TotalBytes: 2048, Done: true})
+			Ω(out.String()).Should(HavePrefix("\rextracting go 1.22.1 2/4 files 512 B/2.0 KiB (25%)"))
+			Ω(out.String()).Should(HaveSuffix("\rextracting go 1.22.1 4/4 files 2.0 KiB/2.0 KiB (100%)\033[K\n"))
+		})
+	})
+}
+
+func TestMultiProgress(t *testing.T) {
+	testutils.Run(t, "multiProgress", func(g *goblin.G) {
+		g.It("combines the operations which are not done yet into one line", func() {
+			p := newMultiProgress(&bytes.Buffer{})
+			p.report(progressEvent{Phase: phaseDownload, Subject: "go1.22.1.tar.gz", Bytes: 45, TotalBytes: 100})
+			p.report(progressEvent{Phase: phaseExtract, Subject: "go 1.21.9", Files: 4, TotalFiles: 5})
+			p.report(progressEvent{Phase: phaseDownload, Subject: "go1.20.14.tar.gz", Bytes: 2048, TotalBytes: -1})
+			Ω(p.line()).Should(Equal("go1.22.1.tar.gz downloading 45% | go 1.21.9 extracting 80% | go1.20.14.tar.gz downloading 2.0 KiB"))
+
+			p.report(progressEvent{Phase: phaseExtract, Subject: "go 1.21.9", Files: 5, TotalFiles: 5, Done: true})
+			Ω(p.line()).Should(Equal("go1.22.1.tar.gz downloading 45% | go1.20.14.tar.gz downloading 2.0 KiB"))
+		})
+
+		g.It("clears the line when finished", func() {
+			out := &bytes.Buffer{}
+			p := newMultiProgress(out)
+			p.report(progressEvent{Phase: phaseDownload, Subject: "go1.22.1.tar.gz", Bytes: 100, TotalBytes: 100, Done: true})
+			p.finish()
+			Ω(out.String()).Should(HaveSuffix("\r\033[K"))
+		})
+	})
+}
+
+func TestFormatBytes(t *testing.T) {
+	testutils.Run(t, "formatBytes", func(g *goblin.G) {
+
+		g.It("uses binary units", func() {
+			Ω(formatBytes(512)).Should(Equal("512 B"))
+			Ω(formatBytes(67319808)).Should(Equal("64.2 MiB"))
+		})
+	})
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/alex-held/dfctl-kit/pkg/env"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/afero"
+)
+
+// ProjectVersionFile pins the go version of a project directory and its children
+const ProjectVersionFile = ".go-version"
+
+// ToolVersionsFile is the asdf version file, which pins go using the golang or go entry
+const ToolVersionsFile = ".tool-versions"
+
+// GoModFile is the module file whose go and toolchain directives name the version required by a module
+const GoModFile = "go.mod"
+
+// SessionVersionEnv overrides the global version for the current shell session
+const SessionVersionEnv = "DFCTL_GO_VERSION"
+
+var errNoProjectPin = errors.New("no project pin found")
+var errPinDrift = errors.New("active go version does not match the project pin")
+var errNoGoMod = errors.New("no version provided and no go.mod found in the working directory or its parents")
+
+// findUpwards returns the content of the nearest file called name in dir or its parents
+func findUpwards(fs afero.Fs, dir, name string) (data []byte, file string, err error) {
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return nil, "", err
+	}
+	for {
+		file = filepath.Join(dir, name)
+		if data, err = afero.ReadFile(fs, file); err == nil {
+			return data, file, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, "", os.ErrNotExist
+		}
+		dir = parent
+	}
+}
+
+// findProjectPin returns the pinned version of the nearest ProjectVersionFile or ToolVersionsFile in dir or its parents.
+// Within the same directory the ProjectVersionFile takes precedence.
+func findProjectPin(fs afero.Fs, dir string) (pin string, file string, err error) {
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return "", "", err
+	}
+	for {
+		file = filepath.Join(dir, ProjectVersionFile)
+		if data, err := afero.ReadFile(fs, file); err == nil {
+			pin = strings.TrimSpace(string(data))
+			if pin == "" {
+				return "", file, fmt.Errorf("project pin %s is empty", file)
+			}
+			return pin, file, nil
+		}
+		file = filepath.Join(dir, ToolVersionsFile)
+		if data, err := afero.ReadFile(fs, file); err == nil {
+			if pin, ok := parseToolVersions(data); ok {
+				return pin, file, nil
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", "", errNoProjectPin
+		}
+		dir = parent
+	}
+}
+
+// parseToolVersions returns the go version of an asdf .tool-versions file.
+// If multiple versions are listed, the first one is preferred by asdf and returned.
+func parseToolVersions(data []byte) (version string, ok bool) {
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(strings.SplitN(line, "#", 2)[0])
+		if len(fields) < 2 {
+			continue
+		}
+		if fields[0] == "golang" || fields[0] == "go" {
+			return fields[1], true
+		}
+	}
+	return "", false
+}
+
+// findGoModVersion returns the version required by the nearest go.mod in dir or its parents.
+// The toolchain directive takes precedence over the go directive.
+func findGoModVersion(fs afero.Fs, dir string) (version string, file string, err error) {
+	data, file, err := findUpwards(fs, dir, GoModFile)
+	if err != nil {
+		return "", "", errNoGoMod
+	}
+	var goDirective, toolchain string
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(strings.SplitN(line, "//", 2)[0])
+		if len(fields) != 2 {
+			continue
+		}
+		switch fields[0] {
+		case "go":
+			goDirective = fields[1]
+		case "toolchain":
+			if fields[1] != "default" {
+				toolchain = fields[1]
+			}
+		}
+	}
+	switch {
+	case toolchain != "":
+		return toolchain, file, nil
+	case goDirective != "":
+		return goDirective, file, nil
+	default:
+		return "", file, fmt.Errorf("%s contains neither a go nor a toolchain directive", file)
+	}
+}
+
+// versionArg returns the version argument of args, falling back to the version required by the module of the working directory
+func (e *executor) versionArg(args []string) (string, error) {
+	if len(args) > 0 {
+		return args[0], nil
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	version, file, err := findGoModVersion(e.Fs, wd)
+	if err != nil {
+		return "", err
+	}
+	log.Info().Msgf("using go %s required by %s", version, file)
+	return version, nil
+}
+
+// pinMatches reports whether v satisfies pin. Partial pins like 1.21 match every patch of the release train,
+// channel pins like 1.23@rc every release candidate and final release of the train.
+func pinMatches(pin string, v Version) bool {
+	if train, ok, err := parseChannel(pin); ok {
+		return err == nil && inChannel(v, train)
+	}
+	pinned, err := ParseVersion(pin)
+	if err != nil {
+		return false
+	}
+	if isPartialVersion(pin) {
+		return pinned.Minor() == v.Minor()
+	}
+	return pinned.Compare(v) == 0
+}
+
+// active returns the version in effect for the current session and where it was configured
+func (e *executor) active() (version Version, source string, err error) {
+	if v := env.GetVars().Get(SessionVersionEnv); v != "" {
+		version, err = ParseVersion(v)
+		return version, "session", err
+	}
+	version, err = e.current()
+	return version, "global", err
+}
+
+// VerifyPin fails with errPinDrift if the active version does not match the project pin of dir
+func (e *executor) VerifyPin(dir string) error {
+	pin, file, err := findProjectPin(e.Fs, dir)
+	if err != nil {
+		return err
+	}
+	active, source, err := e.active()
+	if err != nil {
+		return err
+	}
+	if !pinMatches(pin, active) {
+		return fmt.Errorf("%w; active=%s (%s); pinned=%s (%s)", errPinDrift, active, source, pin, file)
+	}
+	_, _ = fmt.Fprintf(e.Streams.Out, "%s (%s) matches project pin %s (%s)\n", active, source, pin, file)
+	return nil
+}
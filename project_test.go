@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/env"
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+)
+
+func TestFindProjectPin(t *testing.T) {
+	testutils.Run(t, "findProjectPin", func(g *goblin.G) {
+		project := filepath.Join(testutils.TempDir(t), "project")
+		nested := filepath.Join(project, "cmd", "tool")
+
+		g.BeforeEach(func() {
+			_ = os.MkdirAll(nested, os.ModePerm)
+			_ = os.WriteFile(filepath.Join(project, ProjectVersionFile), []byte("1.21.5\n"), os.ModePerm)
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(project)
+		})
+
+		g.It("finds the pin of the project directory", func() {
+			pin, file, err := findProjectPin(afero.NewOsFs(), project)
+			Ω(err).Should(Succeed())
+			Ω(pin).Should(Equal("1.21.5"))
+			Ω(file).Should(Equal(filepath.Join(project, ProjectVersionFile)))
+		})
+
+		g.It("finds the pin of a parent directory", func() {
+			pin, _, err := findProjectPin(afero.NewOsFs(), nested)
+			Ω(err).Should(Succeed())
+			Ω(pin).Should(Equal("1.21.5"))
+		})
+
+		g.It("finds the golang entry of .tool-versions", func() {
+			_ = os.WriteFile(filepath.Join(nested, ToolVersionsFile), []byte("nodejs 20.11.0\ngolang 1.22.1 1.21.9 # fallback\n"), os.ModePerm)
+			pin, file, err := findProjectPin(afero.NewOsFs(), nested)
+			Ω(err).Should(Succeed())
+			Ω(pin).Should(Equal("1.22.1"))
+			Ω(file).Should(Equal(filepath.Join(nested, ToolVersionsFile)))
+		})
+
+		g.It("skips .tool-versions without go entry", func() {
+			_ = os.WriteFile(filepath.Join(nested, ToolVersionsFile), []byte("nodejs 20.11.0\n"), os.ModePerm)
+			pin, _, err := findProjectPin(afero.NewOsFs(), nested)
+			Ω(err).Should(Succeed())
+			Ω(pin).Should(Equal("1.21.5"))
+		})
+
+		g.It("prefers .go-version within the same directory", func() {
+			_ = os.WriteFile(filepath.Join(project, ToolVersionsFile), []byte("go 1.22.1\n"), os.ModePerm)
+			pin, _, err := findProjectPin(afero.NewOsFs(), project)
+			Ω(err).Should(Succeed())
+			Ω(pin).Should(Equal("1.21.5"))
+		})
+
+		g.It("returns errNoProjectPin without pin", func() {
+			_ = os.Remove(filepath.Join(project, ProjectVersionFile))
+			_, _, err := findProjectPin(afero.NewOsFs(), nested)
+			Ω(err).Should(Equal(errNoProjectPin))
+		})
+	})
+}
+
+func TestPinMatches(t *testing.T) {
+	testutils.Run(t, "pinMatches", func(g *goblin.G) {
+
+		g.It("matches exact pins", func() {
+			Ω(pinMatches("1.21.5", "1.21.5")).Should(BeTrue())
+			Ω(pinMatches("1.21.5", "1.21.6")).Should(BeFalse())
+		})
+
+		g.It("matches every patch of partial pins", func() {
+			Ω(pinMatches("1.21", "1.21.6")).Should(BeTrue())
+			Ω(pinMatches("1.21", "1.22.0")).Should(BeFalse())
+		})
+	})
+}
+
+func TestVerifyPin(t *testing.T) {
+	testutils.Run(t, "VerifyPin", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		project := filepath.Join(testutils.TempDir(t), "project")
+
+		g.BeforeEach(func() {
+			createVersionDirs()
+			_ = os.MkdirAll(project, os.ModePerm)
+			symlink(afero.NewOsFs(), filepath.Join(InstallPath, "v1.16.8"), filepath.Join(InstallPath, "current"))
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+			_ = os.RemoveAll(project)
+			env.ClearOverrides()
+		})
+
+		pin := func(version string) {
+			_ = os.WriteFile(filepath.Join(project, ProjectVersionFile), []byte(version), os.ModePerm)
+		}
+
+		newSut := func() *executor {
+			sut := defaultExecutor()
+			sut.Streams.Out = &Buffer{&bytes.Buffer{}}
+			return sut
+		}
+
+		g.It("succeeds if the global version matches the pin", func() {
+			pin("1.16.8")
+			Ω(newSut().VerifyPin(project)).Should(Succeed())
+		})
+
+		g.It("returns errPinDrift if the global version drifts", func() {
+			pin("1.17.1")
+			err := newSut().VerifyPin(project)
+			Ω(errors.Is(err, errPinDrift)).Should(BeTrue())
+			Ω(err.Error()).Should(ContainSubstring("active=1.16.8 (global)"))
+		})
+
+		g.It("prefers the session version", func() {
+			pin("1.17.1")
+			env.Overrides.Vars = env.Vars{SessionVersionEnv: "1.17.1"}
+			Ω(newSut().VerifyPin(project)).Should(Succeed())
+		})
+	})
+}
+
+func TestFindGoModVersion(t *testing.T) {
+	testutils.Run(t, "findGoModVersion", func(g *goblin.G) {
+		module := filepath.Join(testutils.TempDir(t), "module")
+		nested := filepath.Join(module, "internal", "pkg")
+
+		g.BeforeEach(func() {
+			_ = os.MkdirAll(nested, os.ModePerm)
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(module)
+		})
+
+		goMod := func(content string) {
+			_ = os.WriteFile(filepath.Join(module, GoModFile), []byte(content), os.ModePerm)
+		}
+
+		g.It("returns the go directive", func() {
+			goMod("module example.com/foo\n\ngo 1.20\n")
+			version, file, err := findGoModVersion(afero.NewOsFs(), nested)
+			Ω(err).Should(Succeed())
+			Ω(version).Should(Equal("1.20"))
+			Ω(file).Should(Equal(filepath.Join(module, GoModFile)))
+		})
+
+		g.It("prefers the toolchain directive", func() {
+			goMod("module example.com/foo\n\ngo 1.21.0 // minimum\n\ntoolchain go1.21.5\n")
+			version, _, err := findGoModVersion(afero.NewOsFs(), module)
+			Ω(err).Should(Succeed())
+			Ω(version).Should(Equal("go1.21.5"))
+		})
+
+		g.It("ignores toolchain default", func() {
+			goMod("module example.com/foo\n\ngo 1.21.3\ntoolchain default\n")
+			version, _, err := findGoModVersion(afero.NewOsFs(), module)
+			Ω(err).Should(Succeed())
+			Ω(version).Should(Equal("1.21.3"))
+		})
+
+		g.It("returns errNoGoMod outside of modules", func() {
+			_, _, err := findGoModVersion(afero.NewOsFs(), nested)
+			Ω(err).Should(Equal(errNoGoMod))
+		})
+	})
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var errPruneAborted = errors.New("prune aborted")
+var errPruneUnconfirmed = errors.New("prune is not confirmed")
+
+// pruneOptions selects the versions kept by prune
+type pruneOptions struct {
+	// Keep is the number of newest versions kept
+	Keep int
+	// PerMinor applies Keep to every release train instead of all versions
+	PerMinor bool
+	DryRun   bool
+	Yes      bool
+}
+
+func newPruneCmd() *cobra.Command {
+	opts := pruneOptions{}
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "removes old installed versions",
+		Long:  "removes installed versions except the newest ones and the current version; the versions to remove are listed and confirmed before removing them, which requires --yes if stdin is no terminal",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateArgsForSubcommand("prune", args, 0); err != nil {
+				return err
+			}
+			if opts.Keep < 1 {
+				return fmt.Errorf("--keep must be at least 1; keep=%d", opts.Keep)
+			}
+			return defaultExecutor().Prune(opts)
+		},
+	}
+	cmd.Flags().IntVar(&opts.Keep, "keep", 2, "number of newest versions to keep")
+	cmd.Flags().BoolVar(&opts.PerMinor, "keep-per-minor", false, "keep the newest versions of every release train, e.g. 1.21 and 1.22")
+	addDryRunFlag(cmd, &opts.DryRun)
+	cmd.Flags().BoolVarP(&opts.Yes, "yes", "y", false, "remove without confirmation")
+	return cmd
+}
+
+// prunable returns the installations removed by prune, newest first
+func (e *executor) prunable(opts pruneOptions) ([]installation, error) {
+	installs, err := e.installations()
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]Version, 0, len(installs))
+	for _, i := range installs {
+		versions = append(versions, i.Version)
+	}
+	sortVersions(versions)
+
+	current, _ := e.current()
+	kept := map[string]int{}
+	remove := map[Version]bool{}
+	for i, v := range versions {
+		if i > 0 && versions[i-1] == v {
+			continue
+		}
+		group := ""
+		if opts.PerMinor {
+			group = v.Minor()
+		}
+		if kept[group] < opts.Keep {
+			kept[group]++
+		} else if v.Compare(current) != 0 {
+			remove[v] = true
+		}
+	}
+
+	var prunable []installation
+	for _, v := range versions {
+		for _, i := range installs {
+			if remove[v] && i.Version == v {
+				prunable = append(prunable, i)
+			}
+		}
+		delete(remove, v)
+	}
+	return prunable, nil
+}
+
+// Prune lists the versions selected by opts and removes them after confirmation
+func (e *executor) Prune(opts pruneOptions) error {
+	prunable, err := e.prunable(opts)
+	if err != nil {
+		return err
+	}
+	if len(prunable) == 0 {
+		e.infof("nothing to prune\n")
+		return nil
+	}
+	if opts.DryRun {
+		for _, i := range prunable {
+			if err = e.planRemove(i.Version, filepath.Join(e.InstallPath, i.Dir)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	e.infof("the following versions will be removed:\n")
+	for _, i := range prunable {
+		e.infof("  %s (%s)\n", i.Version, filepath.Join(e.InstallPath, i.Dir))
+	}
+	if !opts.Yes {
+		if !isTerminal(e.Streams.In) {
+			return fmt.Errorf("%w; pass --yes to remove %d versions", errPruneUnconfirmed, len(prunable))
+		}
+		if !e.confirm(fmt.Sprintf("remove %d versions?", len(prunable))) {
+			return errPruneAborted
+		}
+	}
+
+	for _, i := range prunable {
+		e.Summary.addVersion(i.Version)
+		if err = e.removeVersionDir(i.Version.String(), filepath.Join(e.InstallPath, i.Dir)); err != nil {
+			return fmt.Errorf("failed to remove go %s; err=%v", i.Version, err)
+		}
+		e.infof("removed go %s\n", i.Version)
+	}
+	e.rehashIfEnabled()
+	return nil
+}
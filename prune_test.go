@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+)
+
+func TestPrune(t *testing.T) {
+	testutils.Run(t, "Prune", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		var out *Buffer
+
+		g.BeforeEach(func() {
+			createVersionDirs()
+			symlink(afero.NewOsFs(), filepath.Join(InstallPath, "v1.13.5"), filepath.Join(InstallPath, "current"))
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+		})
+
+		newSut := func() *executor {
+			out = &Buffer{&bytes.Buffer{}}
+			sut := defaultExecutor()
+			sut.Streams.Out = out
+			return sut
+		}
+
+		versions := func(installs []installation, err error) (versions []Version) {
+			Ω(err).Should(Succeed())
+			for _, i := range installs {
+				versions = append(versions, i.Version)
+			}
+			return versions
+		}
+
+		g.It("keeps the newest versions and the current version", func() {
+			Ω(versions(newSut().prunable(pruneOptions{Keep: 2}))).Should(Equal([]Version{"1.16.8", "1.16.4", "1.16.3", "1.16.0"}))
+		})
+
+		g.It("keeps the newest versions per minor", func() {
+			Ω(versions(newSut().prunable(pruneOptions{Keep: 1, PerMinor: true}))).Should(Equal([]Version{"1.17.0", "1.16.4", "1.16.3", "1.16.0"}))
+		})
+
+		g.It("only lists versions on dry runs", func() {
+			Ω(newSut().Prune(pruneOptions{Keep: 2, DryRun: true})).Should(Succeed())
+			Ω(out.String()).Should(ContainSubstring("would delete go 1.16.8 in " + filepath.Join(InstallPath, "v1.16.8")))
+			Ω(filepath.Join(InstallPath, "v1.16.8")).Should(BeADirectory())
+		})
+
+		g.It("removes the listed versions", func() {
+			Ω(newSut().Prune(pruneOptions{Keep: 2, Yes: true})).Should(Succeed())
+			Ω(filepath.Join(InstallPath, "v1.16.8")).ShouldNot(BeADirectory())
+			Ω(filepath.Join(InstallPath, "v1.17.1")).Should(BeADirectory())
+			Ω(filepath.Join(InstallPath, "v1.13.5")).Should(BeADirectory())
+		})
+
+		g.It("refuses to remove without --yes if stdin is no terminal", func() {
+			sut := newSut()
+			sut.Streams.In = io.NopCloser(&bytes.Buffer{})
+			err := sut.Prune(pruneOptions{Keep: 2})
+			Ω(err).Should(MatchError(errPruneUnconfirmed))
+			Ω(err.Error()).Should(ContainSubstring("pass --yes"))
+			Ω(filepath.Join(InstallPath, "v1.16.8")).Should(BeADirectory())
+		})
+	})
+}
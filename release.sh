@@ -8,10 +8,15 @@ if [ "${tag}" == "" ]; then
   exit 1
 fi
 
+ldflags="-X main.version=${tag} -X main.commit=$(git rev-parse HEAD) -X main.date=$(date -u +%Y-%m-%dT%H:%M:%SZ) -X main.builtBy=release.sh"
+# optional build tags removing features from minimal builds, e.g. TAGS=notui,nooci
+tags="${TAGS:-}"
+
 rm -rf dist
-GOOS=darwin GOARCH=amd64 go build -o "dist/darwin-amd64"
-GOOS=darwin GOARCH=arm64 go build -o "dist/darwin-arm64"
-GOOS=linux GOARCH=386 go build -o "dist/linux-i386"
-GOOS=linux GOARCH=amd64 go build -o "dist/linux-amd64"
+GOOS=darwin GOARCH=amd64 go build -tags "${tags}" -ldflags "${ldflags}" -o "dist/darwin-amd64"
+GOOS=darwin GOARCH=arm64 go build -tags "${tags}" -ldflags "${ldflags}" -o "dist/darwin-arm64"
+GOOS=linux GOARCH=386 go build -tags "${tags}" -ldflags "${ldflags}" -o "dist/linux-i386"
+GOOS=linux GOARCH=amd64 go build -tags "${tags}" -ldflags "${ldflags}" -o "dist/linux-amd64"
+(cd dist && sha256sum * > checksums.txt)
 
 gh release create $tag ./dist/* --title="${tag}" --notes "${tag}"
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/afero"
+)
+
+// Release is an entry of the release feed published at golang.org/dl
+type Release struct {
+	Version string        `json:"version"`
+	Stable  bool          `json:"stable"`
+	Files   []ReleaseFile `json:"files"`
+}
+
+// ReleaseFile is a downloadable artifact of a Release
+type ReleaseFile struct {
+	Filename string `json:"filename"`
+	OS       string `json:"os"`
+	Arch     string `json:"arch"`
+	Version  string `json:"version"`
+	SHA256   string `json:"sha256"`
+	Size     int64  `json:"size"`
+	Kind     string `json:"kind"`
+	// Zstd is a smaller copy of the archive advertised by mirrors
+	Zstd *ZstdArtifact `json:"zstd,omitempty"`
+}
+
+// releaseFeedFile is the copy of the release feed in the cache
+const releaseFeedFile = "releases.json"
+
+// releases fetches every release listed in the remote release feed.
+// The feed is kept in the cache for shell completions, which must not wait for the network.
+func (e *executor) releases(ctx context.Context) (releases []Release, err error) {
+	buf := &bytes.Buffer{}
+	if err = e.download(ctx, e.URL+"/dl/?mode=json&include=all", buf); err != nil {
+		return nil, fmt.Errorf("failed to fetch the release feed from %s; err=%v", e.URL, err)
+	}
+	data := buf.Bytes()
+	if err = json.Unmarshal(data, &releases); err != nil {
+		return nil, fmt.Errorf("failed to decode the release feed from %s; err=%v", e.URL, err)
+	}
+	if err = e.Fs.MkdirAll(e.CachePath, os.ModePerm); err == nil {
+		err = afero.WriteFile(e.Fs, filepath.Join(e.CachePath, releaseFeedFile), data, 0644)
+	}
+	if err != nil {
+		log.Debug().Err(err).Msg("failed to cache the release feed")
+	}
+	return releases, nil
+}
+
+// cachedReleases returns the releases of the release feed cached by the last call of releases
+func (e *executor) cachedReleases() (releases []Release, err error) {
+	data, err := afero.ReadFile(e.Fs, filepath.Join(e.CachePath, releaseFeedFile))
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, &releases); err != nil {
+		return nil, fmt.Errorf("failed to decode the cached release feed; err=%v", err)
+	}
+	return releases, nil
+}
+
+// remoteVersions returns the parsed versions of the release feed in descending order.
+// Unstable releases are only included if includeUnstable is set.
+func (e *executor) remoteVersions(ctx context.Context, includeUnstable bool) (versions []Version, err error) {
+	releases, err := e.releases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range releases {
+		v, err := ParseVersion(r.Version)
+		if err != nil {
+			continue
+		}
+		if !includeUnstable && !v.IsStable() {
+			continue
+		}
+		versions = append(versions, v)
+	}
+	sortVersions(versions)
+	return versions, nil
+}
+
+// releaseFile returns the artifact of the release feed with the file name
+func (e *executor) releaseFile(ctx context.Context, filename string) (ReleaseFile, error) {
+	releases, err := e.releases(ctx)
+	if err != nil {
+		return ReleaseFile{}, err
+	}
+	for _, r := range releases {
+		for _, f := range r.Files {
+			if f.Filename == filename {
+				return f, nil
+			}
+		}
+	}
+	return ReleaseFile{}, fmt.Errorf("%w; file=%s", errNoMatchingRelease, filename)
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/system"
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+// newReleaseServer serves a release feed listing versions and answers every artifact download with archiveData
+func newReleaseServer(versions ...Version) *httptest.Server {
+	return newPlatformReleaseServer(system.OSRuntimeInfoGetter{}.Get(), versions)
+}
+
+// newPlatformReleaseServer serves a release feed listing the archives of versions for the platform ri like newReleaseServer.
+// The extra files, e.g. source archives or installer packages, are listed in the releases of their version.
+func newPlatformReleaseServer(ri system.RuntimeInfo, versions []Version, extra ...ReleaseFile) *httptest.Server {
+	releases := []Release{}
+	for _, v := range versions {
+		releases = append(releases, Release{
+			Version: "go" + v.GoName(),
+			Stable:  v.IsStable(),
+			Files:   []ReleaseFile{archiveReleaseFile(formatGoArchiveArtifactName(ri, v.GoName()), "archive", ri, v)},
+		})
+	}
+	for _, f := range extra {
+		i := 0
+		for i < len(releases) && releases[i].Version != f.Version {
+			i++
+		}
+		if i == len(releases) {
+			releases = append(releases, Release{Version: f.Version, Stable: Version(normalizeGoVersion(f.Version)).IsStable()})
+		}
+		releases[i].Files = append(releases[i].Files, f)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/dl/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("mode") == "json" {
+			_ = json.NewEncoder(w).Encode(releases)
+			return
+		}
+		_, _ = w.Write(archiveData)
+	})
+	return httptest.NewServer(mux)
+}
+
+// archiveReleaseFile lists the file name of kind for version v and the platform ri with the checksum and size of archiveData
+func archiveReleaseFile(name, kind string, ri system.RuntimeInfo, v Version) ReleaseFile {
+	sum := sha256.Sum256(archiveData)
+	return ReleaseFile{
+		Filename: name,
+		OS:       ri.OS,
+		Arch:     ri.Arch,
+		Version:  "go" + v.GoName(),
+		SHA256:   hex.EncodeToString(sum[:]),
+		Size:     int64(len(archiveData)),
+		Kind:     kind,
+	}
+}
+
+func TestRemoteVersions(t *testing.T) {
+	testutils.Run(t, "remoteVersions", func(g *goblin.G) {
+		server := newReleaseServer("1.20.0", "1.21.0-rc.2", "1.21.5", "1.21.0")
+
+		g.After(func() {
+			server.Close()
+		})
+
+		g.It("returns stable versions in descending order", func() {
+			sut := defaultExecutor()
+			sut.URL = server.URL
+			Ω(sut.remoteVersions(context.Background(), false)).Should(Equal([]Version{"1.21.5", "1.21.0", "1.20.0"}))
+		})
+
+		g.It("includes unstable versions when requested", func() {
+			sut := defaultExecutor()
+			sut.URL = server.URL
+			Ω(sut.remoteVersions(context.Background(), true)).Should(Equal([]Version{"1.21.5", "1.21.0", "1.21.0-rc.2", "1.20.0"}))
+		})
+	})
+}
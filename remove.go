@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+// TrashDir is the directory in the install root holding removed versions until they are deleted in the background
+const TrashDir = ".trash"
+
+// emptyTrashCmd is the hidden command deleting the trash of an install root in the background
+const emptyTrashCmd = "__empty-trash"
+
+// removeWorkers is the number of files removed concurrently
+const removeWorkers = 8
+
+// removeInBackground starts a detached process emptying the trash of installPath, which outlives the cli
+var removeInBackground = func(installPath string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(exe, emptyTrashCmd, installPath)
+	if err = cmd.Start(); err != nil {
+		return err
+	}
+	return cmd.Process.Release()
+}
+
+func newEmptyTrashCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:    emptyTrashCmd + " <install-path>",
+		Short:  "deletes the versions moved to the trash of the install root",
+		Hidden: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateArgsForSubcommand(emptyTrashCmd, args, 1); err != nil {
+				return err
+			}
+			e := defaultExecutor()
+			e.InstallPath = args[0]
+			return e.EmptyTrash()
+		},
+	}
+}
+
+// removeTree removes dir by deleting its files with up to workers goroutines, then the remaining directories.
+// report receives the number of removed files of the total after every file; its calls are serialized.
+func removeTree(fs afero.Fs, dir string, workers int, report func(files, total int)) error {
+	var files []string
+	err := afero.Walk(fs, dir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !fi.IsDir() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	paths := make(chan string)
+	var removed int
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range paths {
+				if err := fs.Remove(path); err != nil && !os.IsNotExist(err) {
+					once.Do(func() { firstErr = err })
+					continue
+				}
+				mu.Lock()
+				removed++
+				if report != nil {
+					report(removed, len(files))
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, path := range files {
+		paths <- path
+	}
+	close(paths)
+	wg.Wait()
+	if firstErr != nil {
+		return firstErr
+	}
+	return fs.RemoveAll(dir)
+}
+
+// removeVersionDir removes the install directory dir of subject.
+// It is renamed into the trash and deleted in the background, so the cli returns quickly;
+// if the rename fails, e.g. because dir is on another filesystem, it is removed in place reporting its progress.
+func (e *executor) removeVersionDir(subject, dir string) error {
+	trash := filepath.Join(e.InstallPath, TrashDir)
+	if err := e.Fs.MkdirAll(trash, 0755); err == nil {
+		target := filepath.Join(trash, fmt.Sprintf("%s-%d", filepath.Base(dir), time.Now().UnixNano()))
+		if err = e.Fs.Rename(dir, target); err == nil {
+			e.emptyTrash()
+			return nil
+		}
+		log.Debug().Err(err).Msgf("failed to move %s to the trash; removing it in place", dir)
+	}
+
+	last := progressEvent{Phase: phaseRemove, Subject: subject}
+	err := removeTree(e.Fs, dir, removeWorkers, func(files, total int) {
+		last.Files, last.TotalFiles = files, total
+		e.progress(last)
+	})
+	last.Done = true
+	e.progress(last)
+	return err
+}
+
+// emptyTrash deletes the trash in a background process, or right away if that cannot be started
+func (e *executor) emptyTrash() {
+	if _, ok := e.Fs.(*afero.OsFs); ok {
+		err := removeInBackground(e.InstallPath)
+		if err == nil {
+			return
+		}
+		log.Debug().Err(err).Msg("failed to empty the trash in the background")
+	}
+	if err := e.EmptyTrash(); err != nil {
+		log.Warn().Err(err).Msgf("failed to empty the trash; run 'dfctl-go fsck --repair' to retry")
+	}
+}
+
+// EmptyTrash deletes the versions moved to the trash of the install root
+func (e *executor) EmptyTrash() error {
+	trash := filepath.Join(e.InstallPath, TrashDir)
+	fis, err := afero.ReadDir(e.Fs, trash)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	for _, fi := range fis {
+		if err = removeTree(e.Fs, filepath.Join(trash, fi.Name()), removeWorkers, nil); err != nil {
+			return fmt.Errorf("failed to empty the trash %s; err=%v", trash, err)
+		}
+	}
+	// another removal may have moved a version into the trash meanwhile, which keeps it
+	_ = e.Fs.Remove(trash)
+	return nil
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// emptyTrashNow empties the trash of installPath in the test process
+func emptyTrashNow(installPath string) error {
+	return (&executor{Fs: afero.NewOsFs(), InstallPath: installPath}).EmptyTrash()
+}
+
+func init() {
+	// the tests must not start the test binary as background process
+	removeInBackground = emptyTrashNow
+}
+
+// renameFailingFs fails every rename like a rename across filesystems
+type renameFailingFs struct {
+	afero.Fs
+}
+
+func (fs renameFailingFs) Rename(oldname, newname string) error {
+	return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: errors.New("invalid cross-device link")}
+}
+
+// createTree creates n files in nested directories below dir
+func createTree(fs afero.Fs, dir string, n int) {
+	for i := 0; i < n; i++ {
+		_ = afero.WriteFile(fs, filepath.Join(dir, fmt.Sprintf("pkg%d", i%4), fmt.Sprintf("file%d", i)), []byte("content"), 0644)
+	}
+}
+
+func TestRemoveTree(t *testing.T) {
+	testutils.Run(t, "removeTree", func(g *goblin.G) {
+		g.It("removes every file and directory and reports the progress", func() {
+			fs := afero.NewMemMapFs()
+			createTree(fs, "/sdk/go1.17.1", 50)
+			var last, total int
+			Ω(removeTree(fs, "/sdk/go1.17.1", 4, func(files, t int) {
+				last, total = files, t
+			})).Should(Succeed())
+			Ω(afero.Exists(fs, "/sdk/go1.17.1")).Should(BeFalse())
+			Ω(last).Should(Equal(50))
+			Ω(total).Should(Equal(50))
+		})
+
+		g.It("succeeds if dir does not exist", func() {
+			Ω(removeTree(afero.NewMemMapFs(), "/missing", 4, nil)).Should(Succeed())
+		})
+	})
+}
+
+func TestRemoveVersionDir(t *testing.T) {
+	testutils.Run(t, "removeVersionDir", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		var background []string
+
+		g.BeforeEach(func() {
+			createVersionDirs()
+			background = nil
+			removeInBackground = func(installPath string) error {
+				background = append(background, installPath)
+				return nil
+			}
+		})
+
+		g.AfterEach(func() {
+			removeInBackground = emptyTrashNow
+			_ = os.RemoveAll(InstallPath)
+		})
+
+		g.It("moves the version into the trash and empties it in the background", func() {
+			sut := defaultExecutor()
+			Ω(sut.removeVersionDir("1.17.1", filepath.Join(InstallPath, "v1.17.1"))).Should(Succeed())
+			Ω(filepath.Join(InstallPath, "v1.17.1")).ShouldNot(BeADirectory())
+			Ω(background).Should(Equal([]string{InstallPath}))
+			trashed, _ := afero.ReadDir(afero.NewOsFs(), filepath.Join(InstallPath, TrashDir))
+			Ω(trashed).Should(HaveLen(1))
+
+			Ω(sut.EmptyTrash()).Should(Succeed())
+			Ω(filepath.Join(InstallPath, TrashDir)).ShouldNot(BeADirectory())
+		})
+
+		g.It("hides the trash from the installed versions", func() {
+			sut := defaultExecutor()
+			Ω(sut.removeVersionDir("1.17.1", filepath.Join(InstallPath, "v1.17.1"))).Should(Succeed())
+			versions, err := sut.list()
+			Ω(err).Should(Succeed())
+			Ω(versions).ShouldNot(ContainElement(Version(TrashDir)))
+		})
+
+		g.It("reports the trash as leftover until it is emptied", func() {
+			sut := defaultExecutor()
+			Ω(sut.removeVersionDir("1.17.1", filepath.Join(InstallPath, "v1.17.1"))).Should(Succeed())
+			problems, err := sut.fsckLeftovers()
+			Ω(err).Should(Succeed())
+			Ω(problems).Should(HaveLen(1))
+			Ω(problems[0].Description).Should(ContainSubstring(TrashDir))
+			Ω(problems[0].Repair()).Should(Succeed())
+			Ω(filepath.Join(InstallPath, TrashDir)).ShouldNot(BeADirectory())
+		})
+
+		g.It("removes the version in place if it cannot be moved into the trash", func() {
+			fs := renameFailingFs{afero.NewMemMapFs()}
+			createTree(fs, "/sdk/go/v1.17.1", 20)
+			var events []progressEvent
+			sut := &executor{Fs: fs, InstallPath: "/sdk/go", Progress: func(ev progressEvent) {
+				events = append(events, ev)
+			}}
+			Ω(sut.removeVersionDir("1.17.1", "/sdk/go/v1.17.1")).Should(Succeed())
+			Ω(afero.Exists(fs, "/sdk/go/v1.17.1")).Should(BeFalse())
+			Ω(background).Should(BeEmpty())
+			Ω(events).ShouldNot(BeEmpty())
+			last := events[len(events)-1]
+			Ω(last.Phase).Should(Equal(phaseRemove))
+			Ω(last.Done).Should(BeTrue())
+			Ω(last.Files).Should(Equal(20))
+			Ω(last.TotalFiles).Should(Equal(20))
+		})
+	})
+}
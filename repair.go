@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/alex-held/dfctl-kit/pkg/system"
+
+	"github.com/spf13/cobra"
+)
+
+func newRepairCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "repair <version>",
+		Short: "restores a corrupted or partially installed go sdk",
+		Long: "redownloads the archive of an installed version, or takes it from the cache, and extracts it into the version directory again, " +
+			"e.g. after verify found modified files or an install got interrupted; other versions and the current link are left untouched",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateArgsForSubcommand("repair", args, 1); err != nil {
+				return err
+			}
+			version, err := ParseVersion(args[0])
+			if err != nil {
+				return err
+			}
+			return defaultExecutor().Repair(version)
+		},
+	}
+}
+
+// Repair replaces the version directory with a fresh extraction of its archive
+func (e *executor) Repair(version Version) error {
+	dir, err := e.versionPath(version)
+	if err != nil {
+		return err
+	}
+	e.Summary.addVersion(version)
+	archive, err := e.dlArchive(version)
+	if err != nil {
+		return err
+	}
+	source := e.artifactURL(system.OSRuntimeInfoGetter{}.Get(), version)
+	if err = e.installArchiveInto(version, archive, source, dir); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(e.Streams.Out, "repaired go %s in %s\n", version, dir)
+	return nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+)
+
+func TestRepair(t *testing.T) {
+	testutils.Run(t, "Repair", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		server := newReleaseServer("1.22.1", "1.21.3")
+
+		g.BeforeEach(func() {
+			Ω(defaultExecutor().installArchiveInto("1.22.1", bytes.NewBuffer(archiveData), "test", filepath.Join(InstallPath, "v1.22.1"))).Should(Succeed())
+			Ω(defaultExecutor().installArchive("1.21.3", bytes.NewBuffer(archiveData), "test")).Should(Succeed())
+			symlink(afero.NewOsFs(), filepath.Join(InstallPath, "v1.22.1"), filepath.Join(InstallPath, "current"))
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+		})
+
+		g.After(func() {
+			server.Close()
+		})
+
+		newSut := func() *executor {
+			sut := defaultExecutor()
+			sut.URL = server.URL
+			sut.Streams.Out = &Buffer{&bytes.Buffer{}}
+			sut.Streams.Err = &Buffer{&bytes.Buffer{}}
+			return sut
+		}
+
+		g.It("restores modified and missing files in place", func() {
+			dir := filepath.Join(InstallPath, "v1.22.1")
+			_ = os.WriteFile(filepath.Join(dir, "bin", "go"), []byte("tampered"), 0755)
+			_ = os.Remove(filepath.Join(dir, "VERSION"))
+			_ = os.WriteFile(filepath.Join(dir, "junk"), []byte("junk"), 0644)
+
+			sut := newSut()
+			Ω(sut.Verify("1.22.1", false)).ShouldNot(Succeed())
+			Ω(sut.Repair("1.22.1")).Should(Succeed())
+			Ω(sut.Verify("1.22.1", false)).Should(Succeed())
+			Ω(filepath.Join(dir, "VERSION")).Should(BeARegularFile())
+			Ω(filepath.Join(dir, "junk")).ShouldNot(BeAnExistingFile())
+			Ω(filepath.Join(InstallPath, "1.22.1")).ShouldNot(BeADirectory())
+			Ω(sut.current()).Should(Equal(Version("1.22.1")))
+		})
+
+		g.It("leaves other versions untouched", func() {
+			_ = os.WriteFile(filepath.Join(InstallPath, "1.21.3", "junk"), []byte("junk"), 0644)
+			Ω(newSut().Repair("1.22.1")).Should(Succeed())
+			Ω(filepath.Join(InstallPath, "1.21.3", "junk")).Should(BeARegularFile())
+		})
+
+		g.It("fails for versions which are not installed", func() {
+			Ω(newSut().Repair("1.20.1")).Should(MatchError(ErrVersionNotInstalled))
+		})
+	})
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	semver2 "github.com/Masterminds/semver"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// KeywordLatest resolves to the newest release, including pre-releases if requested
+	KeywordLatest = "latest"
+	// KeywordStable always resolves to the newest stable release
+	KeywordStable = "stable"
+)
+
+var errNoMatchingRelease = errors.New("no matching go release found")
+
+// partialVersionPattern matches versions naming a release train without patch, e.g. 1.21 or go1.21
+var partialVersionPattern = regexp.MustCompile(`^(?:go|v)?\d+\.\d+$`)
+
+// resolveScope selects the versions partial versions are resolved against
+type resolveScope int
+
+const (
+	// remoteScope resolves against the releases of the remote release feed
+	remoteScope resolveScope = iota
+	// installedScope resolves against the locally installed versions
+	installedScope
+	// cachedScope resolves against the versions in the archive cache, which replaces remoteScope offline
+	cachedScope
+)
+
+// isPartialVersion reports whether arg names a release train rather than a specific release
+func isPartialVersion(arg string) bool {
+	return partialVersionPattern.MatchString(arg)
+}
+
+// resolveVersion turns a version argument into a concrete version.
+// The latest and stable keywords are resolved against the remote release feed,
+// partial versions like 1.21 and semver constraints resolve to the newest matching release available in scope
+// and channel pins like 1.23@rc resolve to the newest release candidate until the final release is available.
+// The system keyword selects the go of the operating system and the tip keyword the development toolchain among the installed versions.
+// Aliases of the config are expanded first.
+func (e *executor) resolveVersion(ctx context.Context, arg string, scope resolveScope, includeUnstable bool) (Version, error) {
+	arg = e.expandAlias(arg)
+	if e.Offline && scope == remoteScope {
+		scope = cachedScope
+	}
+	switch keyword := strings.ToLower(arg); keyword {
+	case KeywordSystem:
+		if scope != installedScope {
+			return "", errSystemNotInstallable
+		}
+		return SystemVersion, nil
+	case KeywordTip:
+		if scope != installedScope {
+			return "", errTipNotReleased
+		}
+		return TipVersion, nil
+	case KeywordLatest, KeywordStable:
+		keywordScope := remoteScope
+		if scope == cachedScope {
+			keywordScope = cachedScope
+		}
+		versions, err := e.scopedVersions(ctx, keywordScope, includeUnstable && keyword == KeywordLatest)
+		if err != nil {
+			return "", err
+		}
+		if len(versions) == 0 {
+			return "", errNoMatchingRelease
+		}
+		log.Debug().Msgf("resolved %s to %s", keyword, versions[0])
+		return versions[0], nil
+	}
+
+	if train, ok, err := parseChannel(arg); ok {
+		if err != nil {
+			return "", err
+		}
+		return e.resolveChannel(ctx, arg, train, scope)
+	}
+
+	version, err := ParseVersion(arg)
+	if err != nil {
+		constraint, cerr := semver2.NewConstraint(arg)
+		if cerr != nil {
+			return "", err
+		}
+		return e.resolveConstraint(ctx, arg, constraint, scope, includeUnstable)
+	}
+	partial := isPartialVersion(arg)
+	if !partial && scope == remoteScope {
+		if err = e.checkUnreleased(ctx, version); err != nil {
+			return "", err
+		}
+	}
+	if !partial && scope != installedScope {
+		return version, nil
+	}
+
+	candidates, err := e.scopedVersions(ctx, scope, includeUnstable)
+	if err != nil {
+		return "", err
+	}
+	for _, candidate := range candidates {
+		if partial && candidate.Minor() == version.Minor() && (includeUnstable || candidate.IsStable()) {
+			log.Debug().Msgf("resolved %s to %s", arg, candidate)
+			return candidate, nil
+		}
+		if !partial && candidate.Compare(version) == 0 {
+			return candidate, nil
+		}
+	}
+	if partial {
+		return "", fmt.Errorf("%w; version=%s", errNoMatchingRelease, arg)
+	}
+	return version, nil
+}
+
+// resolveConstraint returns the newest version of scope satisfying the semver constraint, e.g. ^1.21 or >=1.20, <1.23
+func (e *executor) resolveConstraint(ctx context.Context, arg string, constraint *semver2.Constraints, scope resolveScope, includeUnstable bool) (Version, error) {
+	candidates, err := e.scopedVersions(ctx, scope, includeUnstable)
+	if err != nil {
+		return "", err
+	}
+	for _, candidate := range candidates {
+		sv, err := candidate.semver()
+		if err == nil && constraint.Check(sv) {
+			log.Debug().Msgf("resolved constraint %s to %s", arg, candidate)
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("%w; constraint=%s", errNoMatchingRelease, arg)
+}
+
+// scopedVersions returns the versions of scope in descending order
+func (e *executor) scopedVersions(ctx context.Context, scope resolveScope, includeUnstable bool) ([]Version, error) {
+	switch scope {
+	case remoteScope:
+		return e.remoteVersions(ctx, includeUnstable)
+	case cachedScope:
+		return e.cachedVersions(includeUnstable)
+	}
+	return e.installedVersions()
+}
+
+// installedVersions returns the canonical installed and external versions in descending order
+func (e *executor) installedVersions() (versions []Version, err error) {
+	installs, err := e.installations()
+	if err != nil {
+		return nil, err
+	}
+	seen := map[Version]bool{}
+	for _, i := range installs {
+		if !seen[i.Version] {
+			seen[i.Version] = true
+			versions = append(versions, i.Version)
+		}
+	}
+	for _, ext := range e.externals() {
+		if !seen[ext.Version] {
+			seen[ext.Version] = true
+			versions = append(versions, ext.Version)
+		}
+	}
+	sortVersions(versions)
+	return versions, nil
+}
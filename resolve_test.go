@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestResolveVersion(t *testing.T) {
+	testutils.Run(t, "resolveVersion", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		server := newReleaseServer("1.22.0-rc.1", "1.21.9", "1.21.8", "1.20.5")
+
+		g.BeforeEach(func() {
+			createVersionDirs()
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+		})
+
+		g.After(func() {
+			server.Close()
+		})
+
+		newSut := func() *executor {
+			sut := defaultExecutor()
+			sut.URL = server.URL
+			return sut
+		}
+
+		g.Describe("keywords", func() {
+			g.It("resolves latest to the newest stable release", func() {
+				Ω(newSut().resolveVersion(context.Background(), "latest", remoteScope, false)).Should(Equal(Version("1.21.9")))
+			})
+
+			g.It("resolves latest to pre-releases with includeUnstable", func() {
+				Ω(newSut().resolveVersion(context.Background(), "latest", remoteScope, true)).Should(Equal(Version("1.22.0-rc.1")))
+			})
+
+			g.It("resolves stable to the newest stable release with includeUnstable", func() {
+				Ω(newSut().resolveVersion(context.Background(), "stable", remoteScope, true)).Should(Equal(Version("1.21.9")))
+			})
+		})
+
+		g.Describe("partial versions", func() {
+			g.It("resolves to the newest remote patch release", func() {
+				Ω(newSut().resolveVersion(context.Background(), "1.21", remoteScope, false)).Should(Equal(Version("1.21.9")))
+			})
+
+			g.It("resolves to the newest installed patch release", func() {
+				Ω(newSut().resolveVersion(context.Background(), "1.16", installedScope, false)).Should(Equal(Version("1.16.8")))
+			})
+
+			g.It("returns errNoMatchingRelease without matching release", func() {
+				_, err := newSut().resolveVersion(context.Background(), "1.19", remoteScope, false)
+				Ω(errors.Is(err, errNoMatchingRelease)).Should(BeTrue())
+			})
+		})
+
+		g.Describe("constraints", func() {
+			g.It("resolves caret constraints", func() {
+				Ω(newSut().resolveVersion(context.Background(), "^1.20", remoteScope, false)).Should(Equal(Version("1.21.9")))
+			})
+
+			g.It("resolves range constraints", func() {
+				Ω(newSut().resolveVersion(context.Background(), ">=1.20, <1.21.9", remoteScope, false)).Should(Equal(Version("1.21.8")))
+			})
+
+			g.It("resolves against installed versions", func() {
+				Ω(newSut().resolveVersion(context.Background(), "~1.16.3", installedScope, false)).Should(Equal(Version("1.16.8")))
+			})
+
+			g.It("returns errNoMatchingRelease without matching release", func() {
+				_, err := newSut().resolveVersion(context.Background(), ">=1.23", remoteScope, false)
+				Ω(errors.Is(err, errNoMatchingRelease)).Should(BeTrue())
+			})
+		})
+
+		g.Describe("versions", func() {
+			g.It("parses versions", func() {
+				Ω(newSut().resolveVersion(context.Background(), "go1.20.5", remoteScope, false)).Should(Equal(Version("1.20.5")))
+			})
+
+			g.It("resolves installed versions to their canonical version", func() {
+				Ω(newSut().resolveVersion(context.Background(), "v1.17.1", installedScope, false)).Should(Equal(Version("1.17.1")))
+			})
+		})
+	})
+}
+
+func TestIsPartialVersion(t *testing.T) {
+	testutils.Run(t, "isPartialVersion", func(g *goblin.G) {
+
+		g.It("accepts release trains", func() {
+			for _, arg := range []string{"1.21", "go1.21", "v1.21"} {
+				Ω(isPartialVersion(arg)).Should(BeTrue())
+			}
+		})
+
+		g.It("rejects releases", func() {
+			for _, arg := range []string{"1.21.0", "1.21rc1", filepath.Join("1.21", "bin")} {
+				Ω(isPartialVersion(arg)).Should(BeFalse())
+			}
+		})
+	})
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+// sandboxExtractCmd is the hidden command extracting an archive from stdin inside the sandbox
+const sandboxExtractCmd = "__extract-sandboxed"
+
+var errSandboxUnsupported = errors.New("sandboxed extraction is not supported on this platform")
+
+// ExtractionConfig configures how archives are extracted
+type ExtractionConfig struct {
+	// Sandbox extracts archives in a child process confined to the target directory by a chroot,
+	// without network access and with private mounts, using unprivileged linux namespaces
+	Sandbox bool `yaml:"sandbox,omitempty"`
+}
+
+// sandboxArgs returns the command line of the sandboxed extraction into target
+var sandboxArgs = func(target string) ([]string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	return []string{exe, sandboxExtractCmd, target}, nil
+}
+
+func newSandboxExtractCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:    sandboxExtractCmd + " <dir>",
+		Short:  "extracts the archive on stdin inside a sandbox confined to dir",
+		Hidden: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateArgsForSubcommand(sandboxExtractCmd, args, 1); err != nil {
+				return err
+			}
+			return sandboxExtract(c.InOrStdin(), args[0])
+		},
+	}
+}
+
+// sandboxExtract confines the process to dir and extracts the archive read from r into it
+func sandboxExtract(r io.Reader, dir string) error {
+	archive := &bytes.Buffer{}
+	if _, err := io.Copy(archive, r); err != nil {
+		return err
+	}
+	if err := enterSandbox(dir); err != nil {
+		return fmt.Errorf("failed to enter the sandbox at %s; err=%v", dir, err)
+	}
+	return untar(archive, "/", unarchiveRenamer(), afero.NewOsFs(), nil)
+}
+
+// extractArchive extracts the archive into target, inside the sandbox if configured
+func (e *executor) extractArchive(subject string, archive *bytes.Buffer, target string) error {
+	if cfg, err := e.config(); err != nil || !cfg.Extraction.Sandbox {
+		return e.extract(subject, archive, target)
+	}
+	return e.extractSandboxed(archive, target)
+}
+
+// extractSandboxed extracts the archive into target in a child process confined to target
+func (e *executor) extractSandboxed(archive *bytes.Buffer, target string) error {
+	attr := sandboxAttr()
+	if attr == nil {
+		return fmt.Errorf("%w; disable extraction.sandbox in %s", errSandboxUnsupported, e.ConfigFile)
+	}
+	argv, err := sandboxArgs(target)
+	if err != nil {
+		return err
+	}
+	log.Debug().Msgf("extracting into %s inside a sandbox", target)
+	cmd := exec.CommandContext(e.Ctx, argv[0], argv[1:]...)
+	cmd.SysProcAttr = attr
+	cmd.Stdin = bytes.NewReader(archive.Bytes())
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	if err = cmd.Run(); err != nil {
+		return fmt.Errorf("sandboxed extraction failed; err=%v; stderr=%s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+// +build !linux
+
+package main
+
+import "syscall"
+
+// sandboxAttr returns nil, as sandboxed extraction relies on linux namespaces
+func sandboxAttr() *syscall.SysProcAttr {
+	return nil
+}
+
+func enterSandbox(string) error {
+	return errSandboxUnsupported
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+const sandboxHelperEnv = "DFCTL_GO_SANDBOX_HELPER"
+
+// TestSandboxHelperProcess is the sandboxed extraction started by the tests instead of the hidden command
+func TestSandboxHelperProcess(t *testing.T) {
+	if os.Getenv(sandboxHelperEnv) != "1" {
+		return
+	}
+	if err := sandboxExtract(os.Stdin, os.Args[len(os.Args)-1]); err != nil {
+		_, _ = os.Stderr.WriteString(err.Error())
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// escapingArchive returns a tar archive with an entry escaping the target directory
+func escapingArchive() *bytes.Buffer {
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	content := []byte("escaped")
+	_ = tw.WriteHeader(&tar.Header{Name: "go/../../escaped", Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+	_, _ = tw.Write(content)
+	_ = tw.Close()
+	return buf
+}
+
+func TestUntarEscapingEntries(t *testing.T) {
+	testutils.Run(t, "untar", func(g *goblin.G) {
+		g.It("refuses entries escaping the target directory", func() {
+			dir := filepath.Join(testutils.TempDir(t), "target")
+			defer os.RemoveAll(filepath.Dir(dir))
+			err := untar(escapingArchive(), dir, unarchiveRenamer(), afero.NewOsFs(), nil)
+			Ω(errors.Is(err, errUnsafeArchiveEntry)).Should(BeTrue())
+			Ω(filepath.Join(filepath.Dir(dir), "escaped")).ShouldNot(BeAnExistingFile())
+		})
+	})
+}
+
+func TestSandboxedExtraction(t *testing.T) {
+	probe := exec.Command("/bin/true")
+	probe.SysProcAttr = sandboxAttr()
+	if probe.SysProcAttr == nil || probe.Run() != nil {
+		t.Log("unprivileged namespaces are not available")
+		return
+	}
+
+	testutils.Run(t, "SandboxedExtraction", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		ConfigFile = filepath.Join(testutils.TempDir(t), "go.yaml")
+		defaultArgs := sandboxArgs
+
+		g.BeforeEach(func() {
+			_ = os.MkdirAll(filepath.Dir(ConfigFile), os.ModePerm)
+			_ = os.WriteFile(ConfigFile, []byte("extraction:\n  sandbox: true\n"), 0644)
+			_ = os.Setenv(sandboxHelperEnv, "1")
+			sandboxArgs = func(target string) ([]string, error) {
+				return []string{os.Args[0], "-test.run=TestSandboxHelperProcess", "--", target}, nil
+			}
+		})
+
+		g.AfterEach(func() {
+			sandboxArgs = defaultArgs
+			_ = os.Unsetenv(sandboxHelperEnv)
+			_ = os.RemoveAll(InstallPath)
+			_ = os.Remove(ConfigFile)
+		})
+
+		g.It("extracts archives inside the sandbox", func() {
+			Ω(defaultExecutor().installArchive("1.22.1", bytes.NewBuffer(archiveData), "go1.22.1.linux-amd64.tar.gz")).Should(Succeed())
+			Ω(filepath.Join(InstallPath, "1.22.1", "bin", "go")).Should(BeARegularFile())
+			fi, err := os.Stat(filepath.Join(InstallPath, "1.22.1", "VERSION"))
+			Ω(err).Should(Succeed())
+			Ω(fi.Mode().IsRegular()).Should(BeTrue())
+		})
+
+		g.It("confines escaping entries to the target directory", func() {
+			dir := filepath.Join(InstallPath, "sandbox")
+			_ = os.MkdirAll(dir, os.ModePerm)
+			Ω(defaultExecutor().extractSandboxed(escapingArchive(), dir)).Should(Succeed())
+			Ω(filepath.Join(dir, "escaped")).Should(BeARegularFile())
+			Ω(filepath.Join(InstallPath, "escaped")).ShouldNot(BeAnExistingFile())
+		})
+	})
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+
+	semver2 "github.com/Masterminds/semver"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// errUnsatisfied lets satisfies exit with 1 without reporting an error
+var errUnsatisfied = errors.New("no version satisfies the constraint")
+
+func newSatisfiesCmd() *cobra.Command {
+	var installed bool
+	cmd := &cobra.Command{
+		Use:   "satisfies <version> <constraint> | --installed <constraint>",
+		Short: "checks a version against a constraint for scripts",
+		Long: "exits with 0 if the version satisfies the constraint and with 1 otherwise; with --installed the newest installed version satisfying the constraint is printed. " +
+			"Constraints are semver constraints like '>=1.21, <1.23', partial versions like 1.21, channels like 1.23@rc or versions, " +
+			"so scripts, Makefiles and hooks compare versions like dfctl-go resolves them",
+		Example: "  dfctl-go satisfies 1.21.9 '>=1.21, <1.23' && echo supported\n  GOROOT=$(dfctl-go satisfies --installed '^1.21')",
+		// the exit code is the result; errors are reported by main
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(c *cobra.Command, args []string) error {
+			e := defaultExecutor()
+			if installed {
+				if err := validateArgsForSubcommand("satisfies --installed", args, 1); err != nil {
+					return err
+				}
+				return e.BestInstalled(args[0])
+			}
+			if err := validateArgsForSubcommand("satisfies", args, 2); err != nil {
+				return err
+			}
+			version, err := ParseVersion(args[0])
+			if err != nil {
+				return err
+			}
+			ok, err := satisfies(version, args[1])
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return errUnsatisfied
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&installed, "installed", false, "print the newest installed version satisfying the constraint")
+	return cmd
+}
+
+// satisfies reports whether v satisfies the constraint, which is a semver constraint, a partial version, a channel or a version
+func satisfies(v Version, constraint string) (bool, error) {
+	if _, ok, err := parseChannel(constraint); ok {
+		return err == nil && pinMatches(constraint, v), err
+	}
+	if _, err := ParseVersion(constraint); err == nil {
+		return pinMatches(constraint, v), nil
+	}
+	c, err := semver2.NewConstraint(constraint)
+	if err != nil {
+		return false, fmt.Errorf("invalid constraint %s; err=%v", constraint, err)
+	}
+	sv, err := v.semver()
+	if err != nil {
+		return false, err
+	}
+	return c.Check(sv), nil
+}
+
+// BestInstalled prints the newest installed version satisfying the constraint and fails with errUnsatisfied if there is none
+func (e *executor) BestInstalled(constraint string) error {
+	installed, err := e.installedVersions()
+	if err != nil {
+		return err
+	}
+	for _, v := range installed {
+		ok, err := satisfies(v, constraint)
+		if err != nil {
+			return err
+		}
+		if ok {
+			_, _ = fmt.Fprintln(e.Streams.Out, v)
+			return nil
+		}
+	}
+	return errUnsatisfied
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestSatisfies(t *testing.T) {
+	testutils.Run(t, "satisfies", func(g *goblin.G) {
+		g.It("checks semver constraints", func() {
+			Ω(satisfies("1.21.9", ">=1.21, <1.23")).Should(BeTrue())
+			Ω(satisfies("1.23.1", ">=1.21, <1.23")).Should(BeFalse())
+			Ω(satisfies("1.22.0", "^1.21")).Should(BeTrue())
+		})
+
+		g.It("checks partial versions, channels and versions", func() {
+			Ω(satisfies("1.21.9", "1.21")).Should(BeTrue())
+			Ω(satisfies("1.22.0", "1.21")).Should(BeFalse())
+			Ω(satisfies("1.23.0-rc.1", "1.23@rc")).Should(BeTrue())
+			Ω(satisfies("1.21.9", "go1.21.9")).Should(BeTrue())
+			Ω(satisfies("1.21.8", "1.21.9")).Should(BeFalse())
+		})
+
+		g.It("fails for invalid constraints", func() {
+			_, err := satisfies("1.21.9", "one twenty")
+			Ω(err).Should(MatchError(ContainSubstring("invalid constraint")))
+		})
+	})
+}
+
+func TestBestInstalled(t *testing.T) {
+	testutils.Run(t, "BestInstalled", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		var out *Buffer
+
+		g.BeforeEach(func() {
+			createVersionDirs()
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+		})
+
+		newSut := func() *executor {
+			out = &Buffer{&bytes.Buffer{}}
+			sut := defaultExecutor()
+			sut.Streams.Out = out
+			return sut
+		}
+
+		g.It("prints the newest installed version satisfying the constraint", func() {
+			Ω(newSut().BestInstalled(">=1.16, <1.17")).Should(Succeed())
+			Ω(out.String()).Should(Equal("1.16.8\n"))
+		})
+
+		g.It("fails without satisfying version", func() {
+			Ω(newSut().BestInstalled(">=1.18")).Should(MatchError(errUnsatisfied))
+			Ω(out.String()).Should(BeEmpty())
+		})
+	})
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	semver2 "github.com/Masterminds/semver"
+	"github.com/alex-held/dfctl-kit/pkg/system"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// SelfUpdateURL is the github api endpoint of the latest dfctl-go release
+var SelfUpdateURL = "https://api.github.com/repos/alex-held/dfctl-go/releases/latest"
+
+// checksumsAsset is the release asset listing the sha256 checksums of the binaries, written by release.sh
+const checksumsAsset = "checksums.txt"
+
+var errNoReleaseAsset = errors.New("release has no binary for this platform")
+var errUnknownBuildVersion = errors.New("the version of the running build is unknown")
+
+// selfUpdateOptions configures self-update
+type selfUpdateOptions struct {
+	// Check only reports whether an update is available
+	Check bool
+	// Force replaces the executable even if its version is unknown, e.g. of dev builds, or not older than the latest release
+	Force bool
+}
+
+// githubRelease is the subset of a github release used by self-update
+type githubRelease struct {
+	TagName string `json:"tag_name"`
+	Assets  []struct {
+		Name string `json:"name"`
+		URL  string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+func (r githubRelease) assetURL(name string) (string, bool) {
+	for _, a := range r.Assets {
+		if a.Name == name {
+			return a.URL, true
+		}
+	}
+	return "", false
+}
+
+func newSelfUpdateCmd() *cobra.Command {
+	opts := selfUpdateOptions{}
+	cmd := &cobra.Command{
+		Use:   "self-update",
+		Short: "updates dfctl-go to the latest release",
+		Long: "downloads the dfctl-go binary of the latest github release for this platform, verifies its checksum and atomically replaces the running executable if it is older. " +
+			"Builds without a release version, like dev builds, are only replaced with --force",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateArgsForSubcommand("self-update", args, 0); err != nil {
+				return err
+			}
+			exe, err := os.Executable()
+			if err != nil {
+				return err
+			}
+			if exe, err = filepath.EvalSymlinks(exe); err != nil {
+				return err
+			}
+			return defaultExecutor().SelfUpdate(c.Context(), exe, opts)
+		},
+	}
+	cmd.Flags().BoolVar(&opts.Check, "check", false, "only report whether an update is available")
+	cmd.Flags().BoolVar(&opts.Force, "force", false, "replace the executable even if it is a dev build or not older than the latest release")
+	return cmd
+}
+
+// selfAssetName returns the name of the release asset of the platform, following release.sh
+func selfAssetName(ri system.RuntimeInfo) string {
+	arch := ri.Arch
+	if arch == "386" {
+		arch = "i386"
+	}
+	return ri.OS + "-" + arch
+}
+
+// SelfUpdate replaces the executable exe with the binary of the latest release if the running build is older,
+// unless opts.Check is set. Builds of unknown version and builds not older than the latest release are only replaced with opts.Force.
+func (e *executor) SelfUpdate(ctx context.Context, exe string, opts selfUpdateOptions) error {
+	buf := &bytes.Buffer{}
+	if err := e.download(ctx, SelfUpdateURL, buf); err != nil {
+		return fmt.Errorf("failed to query the latest release; err=%v", err)
+	}
+	release := githubRelease{}
+	if err := json.NewDecoder(buf).Decode(&release); err != nil {
+		return fmt.Errorf("failed to decode the latest release; err=%v", err)
+	}
+	latest, err := semver2.NewVersion(release.TagName)
+	if err != nil {
+		return fmt.Errorf("failed to parse the version of the latest release %s; err=%v", release.TagName, err)
+	}
+
+	running := currentBuildInfo().Version
+	current, err := semver2.NewVersion(running)
+	switch {
+	case err != nil && opts.Check:
+		_, _ = fmt.Fprintf(e.Streams.Out, "dfctl-go %s is the latest release; the running build %s has no release version to compare\n", release.TagName, running)
+		return nil
+	case err != nil && !opts.Force:
+		return fmt.Errorf("%w; running=%s; pass --force to replace it with %s", errUnknownBuildVersion, running, release.TagName)
+	case err == nil && !latest.GreaterThan(current) && (opts.Check || !opts.Force):
+		if latest.Equal(current) {
+			_, _ = fmt.Fprintf(e.Streams.Out, "dfctl-go %s is up to date\n", running)
+		} else {
+			_, _ = fmt.Fprintf(e.Streams.Out, "dfctl-go %s is newer than the latest release %s\n", running, release.TagName)
+		}
+		return nil
+	case opts.Check:
+		_, _ = fmt.Fprintf(e.Streams.Out, "dfctl-go %s is available (running %s)\n", release.TagName, running)
+		return nil
+	}
+
+	name := selfAssetName(system.Get())
+	binaryURL, ok := release.assetURL(name)
+	if !ok {
+		return fmt.Errorf("%w; release=%s; asset=%s", errNoReleaseAsset, release.TagName, name)
+	}
+	checksumsURL, ok := release.assetURL(checksumsAsset)
+	if !ok {
+		return fmt.Errorf("release %s publishes no %s to verify the binary", release.TagName, checksumsAsset)
+	}
+
+	sums := &bytes.Buffer{}
+	if err := e.download(ctx, checksumsURL, sums); err != nil {
+		return fmt.Errorf("failed to download %s; err=%v", checksumsAsset, err)
+	}
+	expected, err := checksumOf(sums.String(), name)
+	if err != nil {
+		return err
+	}
+	binary := &bytes.Buffer{}
+	if err = e.downloadWithProgress(ctx, binaryURL, binary, "dfctl-go "+release.TagName); err != nil {
+		return fmt.Errorf("failed to download %s; err=%v", binaryURL, err)
+	}
+	if sum := sha256.Sum256(binary.Bytes()); hex.EncodeToString(sum[:]) != expected {
+		return fmt.Errorf("%w; asset=%s; expected=%s; actual=%s", errChecksumMismatch, name, expected, hex.EncodeToString(sum[:]))
+	}
+
+	// write next to the executable, so the rename replacing it is atomic
+	tmp := exe + ".new"
+	if err = os.WriteFile(tmp, binary.Bytes(), 0755); err != nil {
+		return fmt.Errorf("failed to write %s; err=%v", tmp, err)
+	}
+	if err = os.Rename(tmp, exe); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to replace %s; err=%v", exe, err)
+	}
+	_, _ = fmt.Fprintf(e.Streams.Out, "updated dfctl-go from %s to %s\n", running, release.TagName)
+	return nil
+}
+
+// checksumOf returns the checksum of file listed in the sha256sum output sums
+func checksumOf(sums, file string) (string, error) {
+	scanner := bufio.NewScanner(strings.NewReader(sums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == file {
+			return fields[0], nil
+		}
+	}
+	return "", fmt.Errorf("%s lists no checksum of %s", checksumsAsset, file)
+}
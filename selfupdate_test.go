@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/system"
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+// newGithubServer serves a latest release with the binary of this platform and checksums.txt listing checksum
+func newGithubServer(tag string, binary []byte, checksum string) *httptest.Server {
+	asset := selfAssetName(system.Get())
+	mux := http.NewServeMux()
+	var server *httptest.Server
+	mux.HandleFunc("/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `{"tag_name": %q, "assets": [{"name": %q, "browser_download_url": "%s/bin"}, {"name": "checksums.txt", "browser_download_url": "%s/sums"}]}`,
+			tag, asset, server.URL, server.URL)
+	})
+	mux.HandleFunc("/bin", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(binary)
+	})
+	mux.HandleFunc("/sums", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, "%s  %s\n", checksum, asset)
+	})
+	server = httptest.NewServer(mux)
+	return server
+}
+
+func TestSelfUpdate(t *testing.T) {
+	testutils.Run(t, "SelfUpdate", func(g *goblin.G) {
+		dir := testutils.TempDir(t, "bin")
+		exe := filepath.Join(dir, "dfctl-go")
+		binary := []byte("new dfctl-go")
+		sum := sha256.Sum256(binary)
+		defaultURL, defaultVersion := SelfUpdateURL, version
+		var out *Buffer
+
+		g.BeforeEach(func() {
+			version = "v1.0.0"
+			_ = os.MkdirAll(dir, os.ModePerm)
+			_ = os.WriteFile(exe, []byte("old dfctl-go"), 0755)
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(dir)
+			SelfUpdateURL, version = defaultURL, defaultVersion
+		})
+
+		newSut := func(server *httptest.Server) *executor {
+			SelfUpdateURL = server.URL + "/releases/latest"
+			out = &Buffer{&bytes.Buffer{}}
+			sut := defaultExecutor()
+			sut.Streams.Out = out
+			return sut
+		}
+
+		g.It("replaces the executable", func() {
+			server := newGithubServer("v9.9.9", binary, hex.EncodeToString(sum[:]))
+			defer server.Close()
+			Ω(newSut(server).SelfUpdate(context.Background(), exe, selfUpdateOptions{})).Should(Succeed())
+			Ω(os.ReadFile(exe)).Should(Equal(binary))
+		})
+
+		g.It("only reports with check", func() {
+			server := newGithubServer("v9.9.9", binary, hex.EncodeToString(sum[:]))
+			defer server.Close()
+			Ω(newSut(server).SelfUpdate(context.Background(), exe, selfUpdateOptions{Check: true})).Should(Succeed())
+			Ω(out.String()).Should(ContainSubstring("dfctl-go v9.9.9 is available"))
+			Ω(os.ReadFile(exe)).Should(Equal([]byte("old dfctl-go")))
+		})
+
+		g.It("keeps a build newer than the latest release", func() {
+			version = "v10.0.0"
+			server := newGithubServer("v9.9.9", binary, hex.EncodeToString(sum[:]))
+			defer server.Close()
+			Ω(newSut(server).SelfUpdate(context.Background(), exe, selfUpdateOptions{})).Should(Succeed())
+			Ω(out.String()).Should(Equal("dfctl-go v10.0.0 is newer than the latest release v9.9.9\n"))
+			Ω(newSut(server).SelfUpdate(context.Background(), exe, selfUpdateOptions{Check: true})).Should(Succeed())
+			Ω(out.String()).ShouldNot(ContainSubstring("available"))
+			Ω(os.ReadFile(exe)).Should(Equal([]byte("old dfctl-go")))
+		})
+
+		g.It("replaces dev builds only with force", func() {
+			version = "dev"
+			server := newGithubServer("v9.9.9", binary, hex.EncodeToString(sum[:]))
+			defer server.Close()
+			err := newSut(server).SelfUpdate(context.Background(), exe, selfUpdateOptions{})
+			Ω(errors.Is(err, errUnknownBuildVersion)).Should(BeTrue())
+			Ω(err).Should(MatchError(ContainSubstring("pass --force")))
+			Ω(newSut(server).SelfUpdate(context.Background(), exe, selfUpdateOptions{Check: true})).Should(Succeed())
+			Ω(out.String()).ShouldNot(ContainSubstring("available"))
+			Ω(os.ReadFile(exe)).Should(Equal([]byte("old dfctl-go")))
+
+			Ω(newSut(server).SelfUpdate(context.Background(), exe, selfUpdateOptions{Force: true})).Should(Succeed())
+			Ω(os.ReadFile(exe)).Should(Equal(binary))
+		})
+
+		g.It("keeps the executable on checksum mismatches", func() {
+			server := newGithubServer("v9.9.9", binary, "abc")
+			defer server.Close()
+			err := newSut(server).SelfUpdate(context.Background(), exe, selfUpdateOptions{})
+			Ω(errors.Is(err, errChecksumMismatch)).Should(BeTrue())
+			Ω(os.ReadFile(exe)).Should(Equal([]byte("old dfctl-go")))
+		})
+	})
+}
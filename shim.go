@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/alex-held/dfctl-kit/pkg/env"
+	"github.com/alex-held/dfctl-kit/pkg/system"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+// ShimPath is the directory of the shims dispatching go, gofmt and friends to the version in effect
+var ShimPath = filepath.Join(env.Home(), "shims")
+
+func newRehashCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rehash",
+		Short: "regenerates the shims of the binaries of all installed go sdks",
+		Long: "regenerates the shims in " + ShimPath + " for the binaries of all installed go sdks, e.g. go and gofmt. " +
+			"A shim runs the binary of the version in effect for the working directory, so with the shim directory on PATH the version switches per project",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateArgsForSubcommand("rehash", args, 0); err != nil {
+				return err
+			}
+			e := defaultExecutor()
+			n, err := e.Rehash()
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(e.Streams.Out, "rehashed %d shims in %s\n", n, e.ShimPath)
+			return nil
+		},
+	}
+}
+
+// shimmedBinaries returns the names of the binaries of all installed and external sdks
+func (e *executor) shimmedBinaries() (names []string, err error) {
+	installs, err := e.installations()
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	var roots []string
+	for _, i := range installs {
+		roots = append(roots, filepath.Join(e.InstallPath, i.Dir))
+	}
+	for _, ext := range e.externals() {
+		roots = append(roots, ext.Root)
+	}
+
+	seen := map[string]bool{}
+	for _, root := range roots {
+		fis, err := afero.ReadDir(e.Fs, filepath.Join(root, "bin"))
+		if err != nil {
+			continue
+		}
+		for _, fi := range fis {
+			name := strings.TrimSuffix(fi.Name(), ".exe")
+			if !fi.IsDir() && !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Rehash writes a shim for every binary of the installed sdks and removes stale shims
+func (e *executor) Rehash() (int, error) {
+	names, err := e.shimmedBinaries()
+	if err != nil {
+		return 0, err
+	}
+	if err = e.Fs.MkdirAll(e.ShimPath, os.ModePerm); err != nil {
+		return 0, fmt.Errorf("failed to create shim directory %s; err=%v", e.ShimPath, err)
+	}
+
+	self, err := os.Executable()
+	if err != nil {
+		self = "dfctl-go"
+	}
+	ri := system.Get()
+	shims := map[string]bool{}
+	for _, name := range names {
+		file, content := shim(ri, self, name)
+		shims[file] = true
+		if err = afero.WriteFile(e.Fs, filepath.Join(e.ShimPath, file), []byte(content), 0755); err != nil {
+			return 0, fmt.Errorf("failed to write shim %s; err=%v", file, err)
+		}
+	}
+
+	fis, err := afero.ReadDir(e.Fs, e.ShimPath)
+	if err != nil {
+		return 0, err
+	}
+	for _, fi := range fis {
+		if !shims[fi.Name()] {
+			log.Debug().Msgf("removing stale shim %s", fi.Name())
+			_ = e.Fs.Remove(filepath.Join(e.ShimPath, fi.Name()))
+		}
+	}
+	return len(names), nil
+}
+
+// rehashIfEnabled regenerates the shims after the installed sdks changed, if shims are in use
+func (e *executor) rehashIfEnabled() {
+	if exists, _ := afero.DirExists(e.Fs, e.ShimPath); !exists {
+		return
+	}
+	if _, err := e.Rehash(); err != nil {
+		log.Warn().Err(err).Msg("failed to rehash shims; run 'dfctl-go rehash'")
+	}
+}
+
+// shim returns the file name and content of the shim of the binary name, which execs it using dfctl-go at self
+func shim(ri system.RuntimeInfo, self, name string) (file, content string) {
+	if ri.OS == "windows" {
+		return name + ".cmd", fmt.Sprintf("@echo off\r\n\"%s\" exec -- %s %%*\r\n", self, name)
+	}
+	return name, fmt.Sprintf("#!/bin/sh\nexec %s exec -- %s \"$@\"\n", shellQuote(self), name)
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/system"
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestRehash(t *testing.T) {
+	testutils.Run(t, "Rehash", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		ShimPath = testutils.TempDir(t, "shims")
+
+		g.BeforeEach(func() {
+			createVersionDirs()
+			for _, bin := range []string{filepath.Join("v1.17.1", "bin", "go"), filepath.Join("v1.16.8", "bin", "gofmt")} {
+				_ = os.MkdirAll(filepath.Dir(filepath.Join(InstallPath, bin)), os.ModePerm)
+				_ = os.WriteFile(filepath.Join(InstallPath, bin), []byte("#!/bin/sh\necho \"$GOROOT\"\n"), 0755)
+			}
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+			_ = os.RemoveAll(ShimPath)
+		})
+
+		newSut := func() *executor {
+			sut := defaultExecutor()
+			sut.Streams.Out = &Buffer{&bytes.Buffer{}}
+			return sut
+		}
+
+		g.It("writes a shim for the binaries of all versions", func() {
+			Ω(newSut().Rehash()).Should(Equal(2))
+			Ω(filepath.Join(ShimPath, "go")).Should(BeARegularFile())
+			Ω(filepath.Join(ShimPath, "gofmt")).Should(BeARegularFile())
+		})
+
+		g.It("removes stale shims", func() {
+			_ = os.MkdirAll(ShimPath, os.ModePerm)
+			_ = os.WriteFile(filepath.Join(ShimPath, "godoc"), []byte{}, 0755)
+			Ω(newSut().Rehash()).Should(Equal(2))
+			Ω(filepath.Join(ShimPath, "godoc")).ShouldNot(BeAnExistingFile())
+		})
+
+		g.It("rehashes after installs if shims are in use", func() {
+			server := newReleaseServer("1.22.1")
+			defer server.Close()
+			_ = os.MkdirAll(ShimPath, os.ModePerm)
+			sut := newSut()
+			sut.URL = server.URL
+			Ω(sut.Install("1.22.1")).Should(Succeed())
+			Ω(filepath.Join(ShimPath, "go")).Should(BeARegularFile())
+		})
+
+		g.It("keeps the shims off PATH of executed commands", func() {
+			Ω(withoutPathEntry(ShimPath+":/usr/bin", ShimPath)).Should(Equal("/usr/bin"))
+		})
+	})
+}
+
+func TestShim(t *testing.T) {
+	testutils.Run(t, "shim", func(g *goblin.G) {
+
+		g.It("execs dfctl-go on unix", func() {
+			file, content := shim(system.RuntimeInfo{OS: "linux", Arch: "amd64"}, "/usr/local/bin/dfctl-go", "go")
+			Ω(file).Should(Equal("go"))
+			Ω(content).Should(Equal("#!/bin/sh\nexec /usr/local/bin/dfctl-go exec -- go \"$@\"\n"))
+		})
+
+		g.It("writes cmd files on windows", func() {
+			file, content := shim(system.RuntimeInfo{OS: "windows", Arch: "amd64"}, `C:\dfctl\dfctl-go.exe`, "gofmt")
+			Ω(file).Should(Equal("gofmt.cmd"))
+			Ω(content).Should(ContainSubstring(`"C:\dfctl\dfctl-go.exe" exec -- gofmt %*`))
+		})
+	})
+}
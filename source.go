@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/alex-held/dfctl-kit/pkg/system"
+	"github.com/rs/zerolog/log"
+
+	"github.com/alex-held/dfctl-go/pkg/godist"
+)
+
+// InstallFromSource downloads the source archive of version and builds it with a bootstrap toolchain into its version directory,
+// which installs go on platforms without official binary archives.
+// Installed versions are skipped unless Force is set, which rebuilds them.
+func (e *executor) InstallFromSource(ctx context.Context, version Version) error {
+	e.Summary.addVersion(version)
+	existing, err := e.versionPath(version)
+	if err == nil && !e.Force {
+		e.infof("go %s is already installed in %s; pass --force to reinstall it\n", version, existing)
+		return nil
+	}
+	name, err := godist.ArtifactName(version.GoName(), "", "", godist.KindSource)
+	if err != nil {
+		return err
+	}
+	f, err := e.releaseFile(ctx, name)
+	if err != nil {
+		return err
+	}
+	archive, err := e.dlCachedArchive(ctx, f)
+	if err != nil {
+		return fmt.Errorf("failed downloading the source of go %v from the remote server %s; err=%w", version, e.URL, err)
+	}
+	bootstrap, err := e.resolveBootstrap(ctx, version)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Join(e.InstallPath, version.String())
+	source := e.URL + "/dl/" + name
+	ri := system.OSRuntimeInfoGetter{}.Get()
+	m := installManifest{Version: version, Source: source, SHA256: f.SHA256, InstalledAt: time.Now().UTC(), OS: ri.OS, Arch: ri.Arch, InstalledBy: hostFingerprint()}
+	staging := dir + stagingSuffix
+	if err = e.Fs.RemoveAll(staging); err != nil {
+		return fmt.Errorf("failed to remove the leftover staging directory %s; %w", staging, err)
+	}
+	if err = e.Fs.MkdirAll(staging, os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create install directory at %s; %w", staging, err)
+	}
+	defer func() {
+		_ = e.Fs.RemoveAll(staging)
+	}()
+	log.Debug().Msgf("extracting the source of %v to staging path %v", version, staging)
+	data := archive.Bytes()
+	if err = e.extractArchive(fmt.Sprintf("go %s source", version), archive, staging); err != nil {
+		return fmt.Errorf("failed to extract the source of go %s; dest=%s; err=%v", version, staging, err)
+	}
+	extracted, err := countFiles(e.Fs, staging)
+	if err != nil {
+		return fmt.Errorf("failed to count the files of go sdk %s; err=%v", version, err)
+	}
+	if expected, err := archiveFileCount(data); err != nil || expected != extracted {
+		return fmt.Errorf("%w; version=%s; extracted %d of %d files; err=%v", errIncompleteExtraction, version, extracted, expected, err)
+	}
+
+	e.infof("building go %s from source with the bootstrap toolchain %s\n", version, bootstrap)
+	if _, err = runBuildStep(ctx, filepath.Join(staging, "src"), []string{"GOROOT_BOOTSTRAP=" + bootstrap}, makeScript(ri)); err != nil {
+		return err
+	}
+	if m.Checksums, err = criticalFiles(e.Fs, staging); err != nil {
+		return fmt.Errorf("failed to checksum go sdk %s; err=%v", version, err)
+	}
+	if m.Files, err = countFiles(e.Fs, staging); err != nil {
+		return fmt.Errorf("failed to count the files of go sdk %s; err=%v", version, err)
+	}
+	if err = e.writeManifest(staging, m); err != nil {
+		return err
+	}
+	if err = e.Fs.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to replace %s; %w", dir, err)
+	}
+	if err = e.Fs.Rename(staging, dir); err != nil {
+		return fmt.Errorf("failed to move go sdk %s into place at %s; %w", version, dir, err)
+	}
+	// a differently spelled directory like v1.22.1 is replaced by the canonical one
+	if existing != "" && existing != dir {
+		if err = e.removeVersionDir(version.String(), existing); err != nil {
+			return err
+		}
+	}
+	e.record(historyEntry{Op: historyInstall, Version: version, Source: source})
+	e.rehashIfEnabled()
+	e.infof("built go %s from source in %s\n", version, dir)
+	return nil
+}
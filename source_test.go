@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/env"
+	"github.com/alex-held/dfctl-kit/pkg/system"
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestInstallFromSource(t *testing.T) {
+	testutils.Run(t, "InstallFromSource", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		// the source of 1.22.1 and the binary archive of the bootstrap toolchain 1.21.5
+		source := archiveReleaseFile("go1.22.1.src.tar.gz", "source", system.RuntimeInfo{}, "1.22.1")
+		server := newPlatformReleaseServer(system.OSRuntimeInfoGetter{}.Get(), []Version{"1.21.5"}, source)
+		var out *Buffer
+		var commands []string
+		var restore func()
+
+		g.BeforeEach(func() {
+			env.Overrides.Vars = env.Vars{"PATH": ""}
+			commands = nil
+			restore = fakeBuild(&commands)
+		})
+
+		g.AfterEach(func() {
+			env.ClearOverrides()
+			restore()
+			_ = os.RemoveAll(filepath.Dir(InstallPath))
+		})
+
+		g.After(func() {
+			server.Close()
+		})
+
+		newSut := func() *executor {
+			out = &Buffer{&bytes.Buffer{}}
+			sut := defaultExecutor()
+			sut.URL = server.URL
+			sut.Streams.Out = out
+			return sut
+		}
+
+		g.It("builds the source archive with a bootstrap toolchain into the version directory", func() {
+			Ω(newSut().InstallFromSource(context.Background(), "1.22.1")).Should(Succeed())
+			dir := filepath.Join(InstallPath, "1.22.1")
+			bootstrap := filepath.Join(filepath.Dir(InstallPath), bootstrapDir, "1.21.5")
+			Ω(commands).Should(Equal([]string{"GOROOT_BOOTSTRAP=" + bootstrap + " " + makeScript(system.OSRuntimeInfoGetter{}.Get())}))
+			Ω(filepath.Join(dir, "bin", "go")).Should(BeARegularFile())
+			Ω(dir + stagingSuffix).ShouldNot(BeADirectory())
+			Ω(out.String()).Should(ContainSubstring("built go 1.22.1 from source in " + dir))
+			m, err := newSut().readManifest(dir)
+			Ω(err).Should(Succeed())
+			Ω(m.Source).Should(Equal(server.URL + "/dl/go1.22.1.src.tar.gz"))
+			Ω(newSut().list()).Should(ContainElement(Version("1.22.1")))
+		})
+
+		g.It("skips installed versions unless forced", func() {
+			Ω(newSut().InstallFromSource(context.Background(), "1.22.1")).Should(Succeed())
+			commands = nil
+			Ω(newSut().InstallFromSource(context.Background(), "1.22.1")).Should(Succeed())
+			Ω(commands).Should(BeEmpty())
+			Ω(out.String()).Should(ContainSubstring("pass --force to reinstall it"))
+
+			sut := newSut()
+			sut.Force = true
+			Ω(sut.InstallFromSource(context.Background(), "1.22.1")).Should(Succeed())
+			Ω(commands).Should(HaveLen(1))
+		})
+
+		g.It("keeps the install path clean if the build fails", func() {
+			runBuildCommand = func(ctx context.Context, dir string, vars []string, name string, args ...string) ([]byte, error) {
+				return []byte("cmd/dist: missing bootstrap\n"), os.ErrInvalid
+			}
+			err := newSut().InstallFromSource(context.Background(), "1.22.1")
+			Ω(err).Should(HaveOccurred())
+			Ω(strings.Contains(err.Error(), "cmd/dist: missing bootstrap")).Should(BeTrue())
+			Ω(filepath.Join(InstallPath, "1.22.1")).ShouldNot(BeADirectory())
+			Ω(filepath.Join(InstallPath, "1.22.1"+stagingSuffix)).ShouldNot(BeADirectory())
+		})
+
+		g.It("fails for versions without a source archive", func() {
+			Ω(newSut().InstallFromSource(context.Background(), "1.21.5")).Should(MatchError(ContainSubstring("go1.21.5.src.tar.gz")))
+		})
+	})
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// mutatingAnnotation marks commands changing the installation, which print a ci summary if requested
+const mutatingAnnotation = "dfctl-go/mutating"
+
+// ciSummaryEnabled is set by the --ci-summary flag
+var ciSummaryEnabled bool
+
+// activeSummary collects the summary of the running mutating command if --ci-summary is set
+var activeSummary *ciSummary
+
+// ciSummary collects the outcome of a mutating command for the summary line printed for ci log parsers
+type ciSummary struct {
+	mu       sync.Mutex
+	Action   string
+	Versions []Version
+	Bytes    int64
+	Started  time.Time
+}
+
+// mutating marks c as changing the installation
+func mutating(c *cobra.Command) *cobra.Command {
+	if c.Annotations == nil {
+		c.Annotations = map[string]string{}
+	}
+	c.Annotations[mutatingAnnotation] = "true"
+	return c
+}
+
+// startCISummary starts collecting the summary of c if it is mutating and --ci-summary is set
+func startCISummary(c *cobra.Command) {
+	if !ciSummaryEnabled || c.Annotations[mutatingAnnotation] != "true" || isDryRun(c) {
+		return
+	}
+	action := strings.Join(strings.Fields(c.CommandPath())[1:], "-")
+	activeSummary = &ciSummary{Action: action, Started: time.Now()}
+}
+
+// printCISummary prints the summary of the mutating command to w, if one was collected
+func printCISummary(w io.Writer, err error) {
+	if activeSummary == nil {
+		return
+	}
+	_, _ = fmt.Fprintln(w, activeSummary.line(err, time.Now()))
+}
+
+func (s *ciSummary) addVersion(v Version) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, known := range s.Versions {
+		if known == v {
+			return
+		}
+	}
+	s.Versions = append(s.Versions, v)
+}
+
+func (s *ciSummary) addBytes(n int64) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Bytes += n
+}
+
+// line returns the summary line, which always contains every key in the same order; unknown values are -
+func (s *ciSummary) line(err error, now time.Time) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := "ok"
+	if err != nil {
+		result = "failed"
+	}
+	versions := "-"
+	if len(s.Versions) > 0 {
+		var vs []string
+		for _, v := range s.Versions {
+			vs = append(vs, v.String())
+		}
+		versions = strings.Join(vs, ",")
+	}
+	return fmt.Sprintf("RESULT=%s ACTION=%s VERSION=%s DURATION=%s BYTES=%s",
+		result, s.Action, versions, now.Sub(s.Started).Round(time.Second), summaryBytes(s.Bytes))
+}
+
+// summaryBytes formats n in decimal units without spaces, e.g. 142MB
+func summaryBytes(n int64) string {
+	switch {
+	case n >= 1000*1000*1000:
+		return fmt.Sprintf("%.1fGB", float64(n)/1e9)
+	case n >= 1000*1000:
+		return fmt.Sprintf("%dMB", n/(1000*1000))
+	case n >= 1000:
+		return fmt.Sprintf("%dKB", n/1000)
+	default:
+		return fmt.Sprintf("%dB", n)
+	}
+}
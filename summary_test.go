@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestCISummary(t *testing.T) {
+	testutils.Run(t, "ciSummary", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		started := time.Date(2024, 4, 3, 12, 0, 0, 0, time.UTC)
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+			activeSummary = nil
+			ciSummaryEnabled = false
+		})
+
+		g.It("prints every key in a fixed order", func() {
+			s := &ciSummary{Action: "install", Started: started}
+			s.addVersion("1.22.2")
+			s.addBytes(142 * 1000 * 1000)
+			Ω(s.line(nil, started.Add(52*time.Second))).Should(Equal("RESULT=ok ACTION=install VERSION=1.22.2 DURATION=52s BYTES=142MB"))
+		})
+
+		g.It("reports failures and unknown values", func() {
+			s := &ciSummary{Action: "uninstall", Started: started}
+			Ω(s.line(errors.New("boom"), started)).Should(Equal("RESULT=failed ACTION=uninstall VERSION=- DURATION=0s BYTES=0B"))
+		})
+
+		g.It("only collects summaries of mutating commands", func() {
+			cmd := NewCmd()
+			ciSummaryEnabled = true
+			list, _, _ := cmd.Find([]string{"list"})
+			startCISummary(list)
+			Ω(activeSummary).Should(BeNil())
+			sync, _, err := cmd.Find([]string{"mirror", "sync"})
+			Ω(err).Should(Succeed())
+			startCISummary(sync)
+			Ω(activeSummary.Action).Should(Equal("mirror-sync"))
+		})
+
+		g.It("collects the installed version and downloaded bytes", func() {
+			server := newReleaseServer("1.22.1")
+			defer server.Close()
+			summary := &ciSummary{Action: "install", Started: time.Now()}
+			sut := defaultExecutor()
+			sut.URL = server.URL
+			sut.Summary = summary
+			Ω(sut.Install("1.22.1")).Should(Succeed())
+			Ω(summary.Versions).Should(Equal([]Version{"1.22.1"}))
+			Ω(summary.Bytes).Should(Equal(int64(len(archiveData))))
+		})
+
+		g.It("prints nothing without summary", func() {
+			out := &bytes.Buffer{}
+			printCISummary(out, nil)
+			Ω(out.String()).Should(BeEmpty())
+		})
+	})
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/alex-held/dfctl-kit/pkg/env"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/afero"
+)
+
+// KeywordSystem selects the go installed by the operating system, which deactivates the management of dfctl-go like in rbenv and pyenv
+const KeywordSystem = "system"
+
+// SystemVersion is the pseudo-version of the go installed by the operating system
+const SystemVersion = Version(KeywordSystem)
+
+// systemMarker is the file in the install path marking that the system go is used instead of a current version
+const systemMarker = "current-system"
+
+var errNoSystemGo = errors.New("no go installed by the operating system found on PATH")
+var errSystemNotInstallable = errors.New("the system go is not installed by dfctl-go")
+
+// systemActive reports whether the system go is used instead of a current version
+func (e *executor) systemActive() bool {
+	exists, _ := afero.Exists(e.Fs, filepath.Join(e.InstallPath, systemMarker))
+	return exists
+}
+
+// systemGoroot returns the GOROOT of the first go binary on PATH which is not managed by dfctl-go
+func (e *executor) systemGoroot() (string, error) {
+	var unmanaged []string
+	for _, dir := range filepath.SplitList(env.GetVars().Get("PATH")) {
+		if !e.managedBinDir(dir) {
+			unmanaged = append(unmanaged, dir)
+		}
+	}
+	bin, err := lookPath("go", strings.Join(unmanaged, string(filepath.ListSeparator)))
+	if err != nil {
+		return "", errNoSystemGo
+	}
+	// package managers like Homebrew link the binary from the sdk into their bin directory
+	if resolved, err := filepath.EvalSymlinks(bin); err == nil {
+		bin = resolved
+	}
+	return filepath.Dir(filepath.Dir(bin)), nil
+}
+
+// UseSystem removes the current version, so the shims and the shell hook use the go installed by the operating system
+func (e *executor) UseSystem() error {
+	e.Summary.addVersion(SystemVersion)
+	current, _ := e.current()
+	if err := e.Fs.Remove(filepath.Join(e.InstallPath, "current")); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove the current link; err=%v", err)
+	}
+	_ = e.Fs.Remove(filepath.Join(e.InstallPath, externalMarker))
+	if err := e.Fs.MkdirAll(e.InstallPath, 0755); err != nil {
+		return err
+	}
+	if err := afero.WriteFile(e.Fs, filepath.Join(e.InstallPath, systemMarker), []byte(KeywordSystem+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to switch to the system go; err=%v", err)
+	}
+	e.recordPrevious(current, SystemVersion)
+	e.record(historyEntry{Op: historyUse, Version: SystemVersion, Previous: current})
+
+	root, err := e.systemGoroot()
+	if err != nil {
+		log.Warn().Err(err).Msg("the shims fail until a go is installed by the operating system")
+		return nil
+	}
+	e.infof("using the system go at %s\n", root)
+	return nil
+}
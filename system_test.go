@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/env"
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+)
+
+func TestSystemVersion(t *testing.T) {
+	testutils.Run(t, "system", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		system := filepath.Join(testutils.TempDir(t, "usr", "local"), "go")
+		wd, _ := os.Getwd()
+		outside := testutils.TempDir(t, "outside")
+		var out *Buffer
+
+		g.BeforeEach(func() {
+			createVersionDirs()
+			writeGoRoot(system, "go1.15.2")
+			symlink(afero.NewOsFs(), filepath.Join(InstallPath, "v1.16.8"), filepath.Join(InstallPath, "current"))
+			env.Overrides.Vars = env.Vars{SessionVersionEnv: "", "PATH": filepath.Join(InstallPath, "current", "bin") + ":" + filepath.Join(system, "bin")}
+			// outside of modules and projects the global version is in effect
+			_ = os.MkdirAll(outside, os.ModePerm)
+			_ = os.Chdir(outside)
+		})
+
+		g.AfterEach(func() {
+			env.ClearOverrides()
+			_ = os.RemoveAll(InstallPath)
+			_ = os.RemoveAll(system)
+			_ = os.Chdir(wd)
+		})
+
+		newSut := func() *executor {
+			out = &Buffer{&bytes.Buffer{}}
+			sut := defaultExecutor()
+			sut.Streams.Out = out
+			return sut
+		}
+
+		g.It("removes the current link and reports system as current version", func() {
+			Ω(newSut().Use(SystemVersion)).Should(Succeed())
+			Ω(out.String()).Should(Equal("using the system go at " + system + "\n"))
+			Ω(filepath.Join(InstallPath, "current")).ShouldNot(BeADirectory())
+
+			sut := newSut()
+			sut.Quiet = true
+			Ω(sut.Current()).Should(Succeed())
+			Ω(out.String()).Should(Equal("system\n"))
+		})
+
+		g.It("resolves the GOROOT of the system go, skipping managed directories", func() {
+			Ω(newSut().Use(SystemVersion)).Should(Succeed())
+			Ω(newSut().Path(context.Background(), "")).Should(Succeed())
+			Ω(out.String()).Should(Equal(system + "\n"))
+		})
+
+		g.It("resolves the system keyword among the installed versions only", func() {
+			Ω(newSut().resolveVersion(context.Background(), "system", installedScope, false)).Should(Equal(SystemVersion))
+			_, err := newSut().resolveVersion(context.Background(), "system", remoteScope, false)
+			Ω(errors.Is(err, errSystemNotInstallable)).Should(BeTrue())
+		})
+
+		g.It("switches back to the previous version", func() {
+			Ω(newSut().Use(SystemVersion)).Should(Succeed())
+			Ω(newSut().UsePrevious()).Should(Succeed())
+			Ω(newSut().current()).Should(Equal(Version("1.16.8")))
+			Ω(filepath.Join(InstallPath, systemMarker)).ShouldNot(BeAnExistingFile())
+		})
+
+		g.It("fails to resolve the GOROOT without a system go", func() {
+			env.Overrides.Vars = env.Vars{SessionVersionEnv: "", "PATH": filepath.Join(InstallPath, "current", "bin")}
+			Ω(newSut().Use(SystemVersion)).Should(Succeed())
+			_, err := newSut().goroot(SystemVersion)
+			Ω(errors.Is(err, errNoSystemGo)).Should(BeTrue())
+		})
+	})
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/alex-held/dfctl-kit/pkg/system"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/afero"
+)
+
+// KeywordTip names the development toolchain built from the master branch of the go repository, like gotip
+const KeywordTip = "tip"
+
+// TipVersion is the pseudo-version of the development toolchain, which is installed into the tip directory of the install path
+const TipVersion = Version(KeywordTip)
+
+// TipRepository is the git repository tip is built from
+var TipRepository = "https://go.googlesource.com/go"
+
+var errTipNotReleased = errors.New("tip is built from source; install it with 'dfctl-go install tip'")
+
+// runBuildCommand runs name with args in dir with the environment variables vars added and returns its combined output
+var runBuildCommand = func(ctx context.Context, dir string, vars []string, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	cmd.Env = withEnv(os.Environ(), vars...)
+	return cmd.CombinedOutput()
+}
+
+// runBuildStep runs a step of a source build and includes the tail of its output in the error
+func runBuildStep(ctx context.Context, dir string, vars []string, name string, args ...string) (string, error) {
+	output, err := runBuildCommand(ctx, dir, vars, name, args...)
+	if err != nil {
+		lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+		if len(lines) > 10 {
+			lines = lines[len(lines)-10:]
+		}
+		return "", fmt.Errorf("%s %s failed; err=%v\n%s", name, strings.Join(args, " "), err, strings.Join(lines, "\n"))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// makeScript returns the script building go from source in the src directory on the platform
+func makeScript(ri system.RuntimeInfo) string {
+	if ri.OS == "windows" {
+		return "make.bat"
+	}
+	return "./make.bash"
+}
+
+// tipTarget returns the next release train after the newest stable release, which tip becomes
+func tipTarget(remote []Version) (Version, error) {
+	for _, v := range remote {
+		if !v.IsStable() {
+			continue
+		}
+		sv, err := v.semver()
+		if err != nil {
+			continue
+		}
+		return Version(fmt.Sprintf("%d.%d.0", sv.Major(), sv.Minor()+1)), nil
+	}
+	return "", errors.New("failed to determine the next release of tip without the release feed")
+}
+
+// InstallTip clones the master branch of TipRepository and builds it with a bootstrap toolchain into the tip directory.
+// An installed tip is only rebuilt with update, which pulls the newest commit into it, or with Force.
+func (e *executor) InstallTip(ctx context.Context, update bool) error {
+	e.Summary.addVersion(TipVersion)
+	dir := filepath.Join(e.InstallPath, KeywordTip)
+	exists, _ := afero.DirExists(e.Fs, dir)
+	if exists && !update && !e.Force {
+		e.infof("go tip is installed in %s; pass --update to refresh it\n", dir)
+		return nil
+	}
+	remote, err := e.remoteVersions(ctx, false)
+	if err != nil {
+		return err
+	}
+	target, err := tipTarget(remote)
+	if err != nil {
+		return err
+	}
+	bootstrap, err := e.resolveBootstrap(ctx, target)
+	if err != nil {
+		return err
+	}
+
+	checkout := dir
+	if exists && update {
+		log.Debug().Msgf("pulling the newest commit of %s into %s", TipRepository, dir)
+		if _, err = runBuildStep(ctx, dir, nil, "git", "fetch", "--depth=1", "origin", "master"); err != nil {
+			return err
+		}
+		if _, err = runBuildStep(ctx, dir, nil, "git", "reset", "--hard", "FETCH_HEAD"); err != nil {
+			return err
+		}
+	} else {
+		checkout = dir + stagingSuffix
+		if err = e.Fs.RemoveAll(checkout); err != nil {
+			return fmt.Errorf("failed to remove the leftover staging directory %s; %w", checkout, err)
+		}
+		if err = e.Fs.MkdirAll(e.InstallPath, os.ModePerm); err != nil {
+			return fmt.Errorf("failed to create install directory at %s; %w", e.InstallPath, err)
+		}
+		defer func() {
+			_ = e.Fs.RemoveAll(checkout)
+		}()
+		log.Debug().Msgf("cloning %s into %s", TipRepository, checkout)
+		if _, err = runBuildStep(ctx, e.InstallPath, nil, "git", "clone", "--depth=1", TipRepository, checkout); err != nil {
+			return err
+		}
+	}
+	commit, err := runBuildStep(ctx, checkout, nil, "git", "rev-parse", "HEAD")
+	if err != nil {
+		return err
+	}
+
+	e.infof("building go tip at %.12s with the bootstrap toolchain %s\n", commit, bootstrap)
+	ri := system.OSRuntimeInfoGetter{}.Get()
+	if _, err = runBuildStep(ctx, filepath.Join(checkout, "src"), []string{"GOROOT_BOOTSTRAP=" + bootstrap}, makeScript(ri)); err != nil {
+		return err
+	}
+	source := TipRepository + "@" + commit
+	m := installManifest{Version: TipVersion, Source: source, InstalledAt: time.Now().UTC(), OS: ri.OS, Arch: ri.Arch, InstalledBy: hostFingerprint()}
+	if m.Checksums, err = criticalFiles(e.Fs, checkout); err != nil {
+		return fmt.Errorf("failed to checksum go tip; err=%v", err)
+	}
+	if err = e.writeManifest(checkout, m); err != nil {
+		return err
+	}
+	if checkout != dir {
+		if err = e.Fs.RemoveAll(dir); err != nil {
+			return fmt.Errorf("failed to replace %s; %w", dir, err)
+		}
+		if err = e.Fs.Rename(checkout, dir); err != nil {
+			return fmt.Errorf("failed to move go tip into place at %s; %w", dir, err)
+		}
+	}
+	e.record(historyEntry{Op: historyInstall, Version: TipVersion, Source: source})
+	e.rehashIfEnabled()
+	e.infof("installed go tip at %.12s in %s\n", commit, dir)
+	return nil
+}
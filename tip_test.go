@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/env"
+	"github.com/alex-held/dfctl-kit/pkg/system"
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+// fakeBuild replaces runBuildCommand with a fake git and make.bash recording the command lines
+func fakeBuild(commands *[]string) func() {
+	original := runBuildCommand
+	runBuildCommand = func(ctx context.Context, dir string, vars []string, name string, args ...string) ([]byte, error) {
+		*commands = append(*commands, strings.TrimSpace(strings.Join(append(vars, append([]string{name}, args...)...), " ")))
+		switch {
+		case name == "git" && args[0] == "clone":
+			_ = os.MkdirAll(filepath.Join(args[len(args)-1], "src"), os.ModePerm)
+		case name == "git" && args[0] == "rev-parse":
+			return []byte("0123456789abcdef0123456789abcdef01234567\n"), nil
+		case name == makeScript(system.OSRuntimeInfoGetter{}.Get()):
+			_ = os.MkdirAll(filepath.Join(dir, "..", "bin"), os.ModePerm)
+			_ = os.WriteFile(filepath.Join(dir, "..", "bin", "go"), []byte("#!/bin/sh\n"), 0755)
+		}
+		return nil, nil
+	}
+	return func() {
+		runBuildCommand = original
+	}
+}
+
+func TestInstallTip(t *testing.T) {
+	testutils.Run(t, "InstallTip", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		server := newReleaseServer("1.17.1", "1.16.8")
+		var out *Buffer
+		var commands []string
+		var restore func()
+
+		g.BeforeEach(func() {
+			env.Overrides.Vars = env.Vars{"PATH": ""}
+			commands = nil
+			restore = fakeBuild(&commands)
+		})
+
+		g.AfterEach(func() {
+			env.ClearOverrides()
+			restore()
+			_ = os.RemoveAll(filepath.Dir(InstallPath))
+		})
+
+		g.After(func() {
+			server.Close()
+		})
+
+		newSut := func() *executor {
+			out = &Buffer{&bytes.Buffer{}}
+			sut := defaultExecutor()
+			sut.URL = server.URL
+			sut.Streams.Out = out
+			return sut
+		}
+
+		g.It("builds the master branch with a bootstrap toolchain", func() {
+			Ω(newSut().InstallTip(context.Background(), false)).Should(Succeed())
+			dir := filepath.Join(InstallPath, KeywordTip)
+			bootstrap := filepath.Join(filepath.Dir(InstallPath), bootstrapDir, "1.17.1")
+			Ω(commands).Should(Equal([]string{
+				"git clone --depth=1 " + TipRepository + " " + dir + stagingSuffix,
+				"git rev-parse HEAD",
+				"GOROOT_BOOTSTRAP=" + bootstrap + " " + makeScript(system.OSRuntimeInfoGetter{}.Get()),
+			}))
+			Ω(filepath.Join(dir, "bin", "go")).Should(BeARegularFile())
+			Ω(out.String()).Should(ContainSubstring("installed go tip at 0123456789ab in " + dir))
+			m, err := newSut().readManifest(dir)
+			Ω(err).Should(Succeed())
+			Ω(m.Source).Should(Equal(TipRepository + "@0123456789abcdef0123456789abcdef01234567"))
+		})
+
+		g.It("keeps an installed tip without --update", func() {
+			Ω(newSut().InstallTip(context.Background(), false)).Should(Succeed())
+			commands = nil
+			Ω(newSut().InstallTip(context.Background(), false)).Should(Succeed())
+			Ω(commands).Should(BeEmpty())
+			Ω(out.String()).Should(ContainSubstring("pass --update to refresh it"))
+		})
+
+		g.It("pulls the newest commit into the installed tip with --update", func() {
+			Ω(newSut().InstallTip(context.Background(), false)).Should(Succeed())
+			commands = nil
+			Ω(newSut().InstallTip(context.Background(), true)).Should(Succeed())
+			Ω(commands[:2]).Should(Equal([]string{"git fetch --depth=1 origin master", "git reset --hard FETCH_HEAD"}))
+		})
+
+		g.It("is usable like an installed version", func() {
+			Ω(newSut().InstallTip(context.Background(), false)).Should(Succeed())
+			version, err := newSut().resolveVersion(context.Background(), "tip", installedScope, false)
+			Ω(err).Should(Succeed())
+			Ω(newSut().Use(version)).Should(Succeed())
+			Ω(newSut().current()).Should(Equal(TipVersion))
+			Ω(newSut().goroot(TipVersion)).Should(Equal(filepath.Join(InstallPath, KeywordTip)))
+		})
+
+		g.It("cannot be resolved against the release feed", func() {
+			_, err := newSut().resolveVersion(context.Background(), "tip", remoteScope, false)
+			Ω(errors.Is(err, errTipNotReleased)).Should(BeTrue())
+		})
+	})
+}
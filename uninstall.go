@@ -0,0 +1,71 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+)
+
+// UninstallAllExceptCurrent removes every installed version except the current version and prints the freed disk space
+func (e *executor) UninstallAllExceptCurrent() error {
+	current, err := e.current()
+	if err != nil {
+		return err
+	}
+	installs, err := e.installations()
+	if err != nil {
+		return err
+	}
+
+	var freed int64
+	var removed []versionEntry
+	var failures []bulkFailure
+	total := 0
+	for _, i := range installs {
+		if i.Version.Compare(current) == 0 {
+			continue
+		}
+		total++
+		dir := filepath.Join(e.InstallPath, i.Dir)
+		size, err := dirSize(e.Fs, dir)
+		if err != nil {
+			failures = append(failures, bulkFailure{Item: string(i.Version), Err: err})
+			continue
+		}
+		e.Summary.addVersion(i.Version)
+		if err = e.removeVersionDir(i.Version.String(), dir); err != nil {
+			failures = append(failures, bulkFailure{Item: string(i.Version), Err: err})
+			continue
+		}
+		freed += size
+		removed = append(removed, versionEntry{Version: i.Version, Path: dir, Size: size})
+		if e.Output != outputJSON {
+			e.infof("removed go %s\n", i.Version)
+		}
+	}
+	e.rehashIfEnabled()
+	err = newBulkError("uninstall", "versions", total, failures)
+	if e.Output == outputJSON {
+		if err != nil {
+			return err
+		}
+		return e.writeVersions(removed, nil)
+	}
+	e.infof("kept go %s; freed %s\n", current, formatBytes(freed))
+	return err
+}
+
+// dirSize returns the size of the regular files in dir
+func dirSize(fs afero.Fs, dir string) (size int64, err error) {
+	err = afero.Walk(fs, dir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if fi.Mode().IsRegular() {
+			size += fi.Size()
+		}
+		return nil
+	})
+	return size, err
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+)
+
+func TestUninstallAllExceptCurrent(t *testing.T) {
+	testutils.Run(t, "UninstallAllExceptCurrent", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		var out *Buffer
+		var sut *executor
+
+		g.BeforeEach(func() {
+			createVersionDirs()
+			_ = afero.WriteFile(afero.NewOsFs(), filepath.Join(InstallPath, "v1.16.8", "VERSION"), []byte("go1.16.8"), 0644)
+			out = &Buffer{&bytes.Buffer{}}
+			sut = defaultExecutor()
+			sut.Streams.Out = out
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+		})
+
+		g.It("removes every version except the current version", func() {
+			symlink(afero.NewOsFs(), filepath.Join(InstallPath, "v1.16.4"), filepath.Join(InstallPath, "current"))
+			Ω(sut.UninstallAllExceptCurrent()).Should(Succeed())
+			Ω(filepath.Join(InstallPath, "v1.16.4")).Should(BeADirectory())
+			for _, v := range []string{"v1.13.5", "v1.16.3", "v1.16.8", "v1.17.1"} {
+				Ω(filepath.Join(InstallPath, v)).ShouldNot(BeADirectory())
+			}
+			Ω(out.String()).Should(ContainSubstring("kept go 1.16.4; freed 8 B"))
+		})
+
+		g.It("fails without current version", func() {
+			Ω(sut.UninstallAllExceptCurrent()).Should(MatchError(errNoCurrentVersion))
+			Ω(filepath.Join(InstallPath, "v1.13.5")).Should(BeADirectory())
+		})
+	})
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var errUpgradePinned = errors.New("minor line is pinned")
+
+// supportedTrains is the number of minor lines the go team maintains with patch releases
+const supportedTrains = 2
+
+const (
+	upgradeStatusUpgraded = "upgraded"
+	upgradeStatusUpToDate = "up-to-date"
+	upgradeStatusPinned   = "pinned"
+	upgradeStatusFailed   = "failed"
+)
+
+// trainUpgrade is the outcome of upgrading a single release train
+type trainUpgrade struct {
+	Train     string
+	Before    Version
+	After     Version
+	Status    string
+	Supported bool
+	Err       error
+}
+
+func newUpgradeCmd() *cobra.Command {
+	var allMinors, removeOld bool
+
+	cmd := &cobra.Command{
+		Use:   "upgrade [minor|channel]",
+		Short: "upgrades installed go sdks to the latest patch of their minor line",
+		Long: "upgrades the minor line of the current version, or the given minor line like 1.21, to its latest patch release and relinks current if it belongs to the line; " +
+			"a channel like 1.23@rc upgrades to the newest release candidate of the line until its final release is published. " +
+			"--all-minors upgrades every installed minor line",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateMaxArgsForSubcommand("upgrade", args, 1); err != nil {
+				return err
+			}
+			e := defaultExecutor()
+			if allMinors {
+				if err := validateArgsForSubcommand("upgrade --all-minors", args, 0); err != nil {
+					return err
+				}
+				return e.UpgradeAllMinors(c.Context())
+			}
+			train := ""
+			if len(args) == 1 {
+				train = args[0]
+				if _, ok, _ := parseChannel(train); !ok {
+					v, err := ParseVersion(train)
+					if err != nil {
+						return err
+					}
+					train = v.Minor()
+				}
+			}
+			return e.Upgrade(c.Context(), train, removeOld)
+		},
+	}
+	cmd.Flags().BoolVar(&allMinors, "all-minors", false, "upgrade every installed minor line to its latest patch")
+	cmd.Flags().BoolVar(&removeOld, "remove-old", false, "remove the previously newest patch of the minor line after upgrading")
+
+	return cmd
+}
+
+// Upgrade installs the latest patch release of the release train, which defaults to the train of the current version,
+// relinks current if it belongs to the train and optionally removes the previously newest installed patch.
+// The train may be a channel pin like 1.23@rc, which also upgrades to newer release candidates;
+// a current release candidate follows its channel by default.
+func (e *executor) Upgrade(ctx context.Context, train string, removeOld bool) error {
+	current, currentErr := e.current()
+	channel, rc, err := parseChannel(train)
+	if err != nil {
+		return err
+	}
+	if rc {
+		train = channel
+	}
+	if train == "" {
+		if currentErr != nil {
+			return currentErr
+		}
+		train, rc = current.Minor(), current.IsReleaseCandidate()
+	}
+	cfg, err := e.config()
+	if err != nil {
+		return err
+	}
+	installed, err := e.installedVersions()
+	if err != nil {
+		return err
+	}
+	var before Version
+	for _, v := range installed {
+		if v.Minor() == train && (v.IsStable() || rc && v.IsReleaseCandidate()) {
+			before = v
+			break
+		}
+	}
+	if before != "" && cfg.pinned(before) {
+		return fmt.Errorf("%w; minor=%s; remove the pin from %s to upgrade it", errUpgradePinned, train, e.ConfigFile)
+	}
+
+	remote, err := e.remoteVersions(ctx, rc)
+	if err != nil {
+		return err
+	}
+	target, ok := latestPatch(remote, train, cfg)
+	if rc {
+		target, ok = latestInChannel(remote, train, cfg)
+	}
+	if !ok {
+		return fmt.Errorf("%w; minor=%s", errNoMatchingRelease, train)
+	}
+	if before != "" && target.Compare(before) <= 0 {
+		e.infof("go %s is %s\n", before, upgradeStatusUpToDate)
+		return nil
+	}
+	if err = e.Install(target); err != nil {
+		return err
+	}
+	if currentErr == nil && current.Minor() == train {
+		if err = e.Use(target); err != nil {
+			return fmt.Errorf("installed go %s but failed to link it as current; %w", target, err)
+		}
+	}
+	if before == "" {
+		e.infof("installed go %s\n", target)
+		return nil
+	}
+	e.infof("upgraded go %s to %s\n", before, target)
+	if removeOld {
+		if err = e.Uninstall(before); err != nil {
+			return fmt.Errorf("failed to remove go %s; %w", before, err)
+		}
+		e.infof("removed go %s\n", before)
+	}
+	return nil
+}
+
+// UpgradeAllMinors installs the latest patch release of every installed release train.
+// Installs run in parallel; the current link is moved afterwards if its train got upgraded.
+func (e *executor) UpgradeAllMinors(ctx context.Context) error {
+	upgrades, err := e.upgradeAllMinors(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(e.Streams.Out, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "MINOR\tBEFORE\tAFTER\tSTATUS\tSUPPORTED")
+	var failures []bulkFailure
+	for _, u := range upgrades {
+		if u.Err != nil {
+			failures = append(failures, bulkFailure{Item: u.Train, Err: u.Err})
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%t\n", u.Train, u.Before, u.After, u.Status, u.Supported)
+	}
+	_ = w.Flush()
+
+	return newBulkError("upgrade", "minor lines", len(upgrades), failures)
+}
+
+func (e *executor) upgradeAllMinors(ctx context.Context) ([]*trainUpgrade, error) {
+	cfg, err := e.config()
+	if err != nil {
+		return nil, err
+	}
+	installed, err := e.installedVersions()
+	if err != nil {
+		return nil, err
+	}
+	remote, err := e.remoteVersions(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	latest := map[string]Version{}
+	for _, v := range installed {
+		if _, ok := latest[v.Minor()]; !ok && v.IsStable() {
+			latest[v.Minor()] = v
+		}
+	}
+	supported := supportedMinors(remote)
+
+	upgrades := make([]*trainUpgrade, 0, len(latest))
+	wg := sync.WaitGroup{}
+	for train, before := range latest {
+		u := &trainUpgrade{Train: train, Before: before, After: before, Status: upgradeStatusUpToDate, Supported: supported[train]}
+		upgrades = append(upgrades, u)
+
+		if cfg.pinned(before) {
+			u.Status = upgradeStatusPinned
+			continue
+		}
+		target, ok := latestPatch(remote, train, cfg)
+		if !ok || target.Compare(before) <= 0 {
+			continue
+		}
+
+		wg.Add(1)
+		go func(u *trainUpgrade, target Version) {
+			defer wg.Done()
+			if err := e.Install(target); err != nil {
+				u.Status, u.Err = upgradeStatusFailed, err
+				return
+			}
+			u.Status, u.After = upgradeStatusUpgraded, target
+		}(u, target)
+	}
+	wg.Wait()
+
+	sort.Slice(upgrades, func(i, j int) bool {
+		return upgrades[i].Before.Compare(upgrades[j].Before) > 0
+	})
+
+	current, err := e.current()
+	if err != nil {
+		return upgrades, nil
+	}
+	for _, u := range upgrades {
+		if u.Status != upgradeStatusUpgraded || u.Train != current.Minor() {
+			continue
+		}
+		if err := e.Use(u.After); err != nil {
+			u.Status, u.Err = upgradeStatusFailed, fmt.Errorf("installed but failed to link as current; %w", err)
+		}
+	}
+	return upgrades, nil
+}
+
+// latestPatch returns the newest version of the release train, skipping quarantined versions.
+// remote must be sorted in descending order.
+func latestPatch(remote []Version, train string, cfg *Config) (Version, bool) {
+	for _, v := range remote {
+		if v.Minor() == train && !cfg.quarantined(v) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// supportedMinors returns the release trains which still receive patch releases
+func supportedMinors(remote []Version) map[string]bool {
+	supported := map[string]bool{}
+	for _, v := range remote {
+		if len(supported) == supportedTrains {
+			break
+		}
+		supported[v.Minor()] = true
+	}
+	return supported
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+func TestUpgradeAllMinors(t *testing.T) {
+	testutils.Run(t, "UpgradeAllMinors", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		ConfigFile = filepath.Join(testutils.TempDir(t), "go.yaml")
+		server := newReleaseServer("1.22.1", "1.22.0-rc.1", "1.21.9", "1.21.8", "1.21.3", "1.20.5", "1.20.1")
+
+		g.BeforeEach(func() {
+			for _, v := range []string{"1.20.1", "1.21.3"} {
+				_ = os.MkdirAll(filepath.Join(InstallPath, v), os.ModePerm)
+			}
+			symlink(afero.NewOsFs(), filepath.Join(InstallPath, "1.21.3"), filepath.Join(InstallPath, "current"))
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+			_ = os.Remove(ConfigFile)
+		})
+
+		g.After(func() {
+			server.Close()
+		})
+
+		writeConfig := func(content string) {
+			_ = os.MkdirAll(filepath.Dir(ConfigFile), os.ModePerm)
+			_ = os.WriteFile(ConfigFile, []byte(content), os.ModePerm)
+		}
+
+		newSut := func() *executor {
+			sut := defaultExecutor()
+			sut.URL = server.URL
+			sut.Streams.Out = &Buffer{&bytes.Buffer{}}
+			return sut
+		}
+
+		g.It("installs the latest patch of every minor line", func() {
+			Ω(newSut().UpgradeAllMinors(context.Background())).Should(Succeed())
+			Ω(filepath.Join(InstallPath, "1.21.9")).Should(BeADirectory())
+			Ω(filepath.Join(InstallPath, "1.20.5")).Should(BeADirectory())
+		})
+
+		g.It("relinks current to the upgraded version", func() {
+			sut := newSut()
+			Ω(sut.UpgradeAllMinors(context.Background())).Should(Succeed())
+			Ω(sut.current()).Should(Equal(Version("1.21.9")))
+		})
+
+		g.It("does not upgrade pinned minor lines", func() {
+			writeConfig("pins: [\"1.20\"]\n")
+			Ω(newSut().UpgradeAllMinors(context.Background())).Should(Succeed())
+			Ω(filepath.Join(InstallPath, "1.20.5")).ShouldNot(BeADirectory())
+		})
+
+		g.It("skips quarantined versions", func() {
+			writeConfig("quarantine: [\"1.21.9\"]\n")
+			Ω(newSut().UpgradeAllMinors(context.Background())).Should(Succeed())
+			Ω(filepath.Join(InstallPath, "1.21.9")).ShouldNot(BeADirectory())
+			Ω(filepath.Join(InstallPath, "1.21.8")).Should(BeADirectory())
+		})
+
+		g.It("prints a summary table", func() {
+			sut := newSut()
+			Ω(sut.UpgradeAllMinors(context.Background())).Should(Succeed())
+			out := sut.Streams.Out.(*Buffer).String()
+			Ω(out).Should(MatchRegexp(`1\.21\s+1\.21\.3\s+1\.21\.9\s+upgraded\s+true`))
+			Ω(out).Should(MatchRegexp(`1\.20\s+1\.20\.1\s+1\.20\.5\s+upgraded\s+false`))
+		})
+
+		g.It("continues past failing minor lines", func() {
+			failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if strings.Contains(r.URL.Path, "go1.20.5.") {
+					http.Error(w, "gone", http.StatusInternalServerError)
+					return
+				}
+				resp, err := http.Get(server.URL + r.URL.RequestURI())
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusBadGateway)
+					return
+				}
+				defer resp.Body.Close()
+				_, _ = io.Copy(w, resp.Body)
+			}))
+			defer failing.Close()
+
+			sut := newSut()
+			sut.URL = failing.URL
+			sut.CachePath = filepath.Join(InstallPath, ".cache")
+			err := sut.UpgradeAllMinors(context.Background())
+
+			var bulkErr *bulkError
+			Ω(errors.As(err, &bulkErr)).Should(BeTrue())
+			Ω(bulkErr.Failures).Should(HaveLen(1))
+			Ω(bulkErr.Failures[0].Item).Should(Equal("1.20"))
+			Ω(bulkErr.ExitCode()).Should(Equal(exitPartialFailure))
+			Ω(filepath.Join(InstallPath, "1.21.9")).Should(BeADirectory())
+			Ω(sut.Streams.Out.(*Buffer).String()).Should(MatchRegexp(`1\.20\s+1\.20\.1\s+1\.20\.1\s+failed`))
+		})
+	})
+}
+
+func TestUpgrade(t *testing.T) {
+	testutils.Run(t, "Upgrade", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		ConfigFile = filepath.Join(testutils.TempDir(t), "go.yaml")
+		server := newReleaseServer("1.22.1", "1.21.9", "1.21.3", "1.20.5", "1.20.1")
+		var out *Buffer
+
+		g.BeforeEach(func() {
+			for _, v := range []string{"1.20.1", "1.21.3"} {
+				_ = os.MkdirAll(filepath.Join(InstallPath, v), os.ModePerm)
+			}
+			symlink(afero.NewOsFs(), filepath.Join(InstallPath, "1.21.3"), filepath.Join(InstallPath, "current"))
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+			_ = os.Remove(ConfigFile)
+		})
+
+		g.After(func() {
+			server.Close()
+		})
+
+		newSut := func() *executor {
+			out = &Buffer{&bytes.Buffer{}}
+			sut := defaultExecutor()
+			sut.URL = server.URL
+			sut.Streams.Out = out
+			return sut
+		}
+
+		g.It("upgrades and relinks the current minor line", func() {
+			sut := newSut()
+			Ω(sut.Upgrade(context.Background(), "", false)).Should(Succeed())
+			Ω(sut.current()).Should(Equal(Version("1.21.9")))
+			Ω(filepath.Join(InstallPath, "1.21.3")).Should(BeADirectory())
+			Ω(filepath.Join(InstallPath, "1.20.5")).ShouldNot(BeADirectory())
+			Ω(out.String()).Should(ContainSubstring("upgraded go 1.21.3 to 1.21.9"))
+		})
+
+		g.It("removes the old patch", func() {
+			Ω(newSut().Upgrade(context.Background(), "", true)).Should(Succeed())
+			Ω(filepath.Join(InstallPath, "1.21.3")).ShouldNot(BeADirectory())
+		})
+
+		g.It("upgrades the given minor line without relinking current", func() {
+			sut := newSut()
+			Ω(sut.Upgrade(context.Background(), "1.20", false)).Should(Succeed())
+			Ω(filepath.Join(InstallPath, "1.20.5")).Should(BeADirectory())
+			Ω(sut.current()).Should(Equal(Version("1.21.3")))
+		})
+
+		g.It("reports up to date minor lines", func() {
+			Ω(newSut().Upgrade(context.Background(), "1.20", false)).Should(Succeed())
+			Ω(newSut().Upgrade(context.Background(), "1.20", false)).Should(Succeed())
+			Ω(out.String()).Should(ContainSubstring("go 1.20.5 is up-to-date"))
+		})
+
+		g.It("refuses pinned minor lines", func() {
+			_ = os.MkdirAll(filepath.Dir(ConfigFile), os.ModePerm)
+			_ = os.WriteFile(ConfigFile, []byte("pins: [\"1.21\"]\n"), os.ModePerm)
+			Ω(newSut().Upgrade(context.Background(), "", false)).Should(MatchError(ContainSubstring(errUpgradePinned.Error())))
+		})
+	})
+}
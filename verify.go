@@ -0,0 +1,187 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math/rand"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// quickSampleSize is the number of files verify --quick checks besides bin/go
+const quickSampleSize = 4
+
+var errModified = errors.New("go sdk was modified since its install")
+var errNoManifest = errors.New("go sdk has no install manifest")
+
+func newVerifyCmd() *cobra.Command {
+	var current, all, quick bool
+	cmd := &cobra.Command{
+		Use:   "verify [version]",
+		Short: "verifies installed go sdks against their install manifest or the published checksums",
+		Long: "verifies the checksums of the binaries in bin and pkg/tool of an installed go sdk against the checksums recorded in its install manifest " +
+			"and fails if a file was modified or removed; sdks without manifest are verified file by file against the official archive, whose published checksum is checked first. " +
+			"--quick only checks bin/go and a random sample of the other files, e.g. from a shell rc; --all verifies every installed version",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateMaxArgsForSubcommand("verify", args, 1); err != nil {
+				return err
+			}
+			e := defaultExecutor()
+			switch {
+			case all && (current || len(args) == 1):
+				return errors.New("--all verifies every version; do not pass --current or a version")
+			case all:
+				return e.VerifyAll(quick)
+			case current && len(args) == 1:
+				return errors.New("pass either --current or a version")
+			case current:
+				version, err := e.current()
+				if err != nil {
+					return err
+				}
+				return e.Verify(version, quick)
+			case len(args) == 1:
+				version, err := ParseVersion(args[0])
+				if err != nil {
+					return err
+				}
+				return e.Verify(version, quick)
+			}
+			return errors.New("pass a version, --current or --all")
+		},
+	}
+	cmd.Flags().BoolVar(&current, "current", false, "verify the current version")
+	cmd.Flags().BoolVar(&all, "all", false, "verify every installed version")
+	cmd.Flags().BoolVar(&quick, "quick", false, "only verify bin/go and a random sample of the other binaries")
+	return cmd
+}
+
+// Verify checks the files of the installed version against the checksums of its manifest,
+// or against the official archive if it has no manifest
+func (e *executor) Verify(version Version, quick bool) error {
+	dir, err := e.versionPath(version)
+	if err != nil {
+		return err
+	}
+	checksums, err := e.verifiedChecksums(version, dir)
+	if err != nil {
+		return err
+	}
+
+	files := make([]string, 0, len(checksums))
+	for file := range checksums {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	if quick {
+		files = quickSample(files, rand.New(rand.NewSource(time.Now().UnixNano())))
+	}
+
+	var problems []string
+	for _, file := range files {
+		sum, err := fileSHA256(e.Fs, filepath.Join(dir, filepath.FromSlash(file)))
+		switch {
+		case err != nil:
+			problems = append(problems, file+" is missing")
+		case sum != checksums[file]:
+			problems = append(problems, file+" was modified")
+		}
+	}
+	if m, err := e.readManifest(dir); err == nil && m.Files > 0 && !quick {
+		if n, err := countFiles(e.Fs, dir); err == nil && n-1 < m.Files {
+			problems = append(problems, fmt.Sprintf("%d of %d files are missing", m.Files-n+1, m.Files))
+		}
+	}
+	if len(problems) > 0 {
+		for _, p := range problems {
+			_, _ = fmt.Fprintf(e.Streams.Err, "go %s: %s\n", version, p)
+		}
+		return fmt.Errorf("%w; version=%s; files=%d; run 'dfctl-go repair %s' to restore it", errModified, version, len(problems), version)
+	}
+	_, _ = fmt.Fprintf(e.Streams.Out, "go %s: verified %d files\n", version, len(files))
+	return nil
+}
+
+// verifiedChecksums returns the checksums recorded in the manifest of the version directory dir,
+// or the checksums of all files of the official archive, whose published checksum gets verified on download
+func (e *executor) verifiedChecksums(version Version, dir string) (map[string]string, error) {
+	if m, err := e.readManifest(dir); err == nil && len(m.Checksums) > 0 {
+		return m.Checksums, nil
+	}
+	log.Debug().Msgf("go %s has no install manifest; verifying it against the official archive", version)
+	archive, err := e.dlArchive(version)
+	if err != nil {
+		return nil, fmt.Errorf("%w; version=%s; the official archive is unavailable; err=%v", errNoManifest, version, err)
+	}
+	return archiveChecksums(archive)
+}
+
+// archiveChecksums returns the checksums of the regular files of the sdk archive by slash separated path relative to its root
+func archiveChecksums(archive *bytes.Buffer) (map[string]string, error) {
+	r, err := tarStream(archive)
+	if err != nil {
+		return nil, err
+	}
+	checksums := map[string]string{}
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return checksums, nil
+		} else if err != nil {
+			return nil, err
+		}
+		parts := strings.SplitN(header.Name, "/", 2)
+		if header.Typeflag != tar.TypeReg || len(parts) != 2 {
+			continue
+		}
+		h := sha256.New()
+		if _, err = io.Copy(h, tr); err != nil {
+			return nil, err
+		}
+		checksums[parts[1]] = hex.EncodeToString(h.Sum(nil))
+	}
+}
+
+// VerifyAll verifies every installed version, continuing past failures
+func (e *executor) VerifyAll(quick bool) error {
+	installs, err := e.installations()
+	if err != nil {
+		return err
+	}
+	var failures []bulkFailure
+	for _, i := range installs {
+		if err = e.Verify(i.Version, quick); err != nil {
+			failures = append(failures, bulkFailure{Item: i.Version.String(), Err: err})
+		}
+	}
+	return newBulkError("verify", "versions", len(installs), failures)
+}
+
+// quickSample returns bin/go and up to quickSampleSize other random files
+func quickSample(files []string, r *rand.Rand) []string {
+	var sample, others []string
+	for _, file := range files {
+		if file == "bin/go" {
+			sample = append(sample, file)
+		} else {
+			others = append(others, file)
+		}
+	}
+	r.Shuffle(len(others), func(i, j int) { others[i], others[j] = others[j], others[i] })
+	if len(others) > quickSampleSize {
+		others = others[:quickSampleSize]
+	}
+	sort.Strings(others)
+	return append(sample, others...)
+}
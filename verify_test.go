@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestVerify(t *testing.T) {
+	testutils.Run(t, "Verify", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		var errOut *Buffer
+
+		g.BeforeEach(func() {
+			Ω(defaultExecutor().installArchive("1.22.1", bytes.NewBuffer(archiveData), "test")).Should(Succeed())
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+		})
+
+		newSut := func() *executor {
+			errOut = &Buffer{&bytes.Buffer{}}
+			sut := defaultExecutor()
+			sut.Streams.Out = &Buffer{&bytes.Buffer{}}
+			sut.Streams.Err = errOut
+			return sut
+		}
+
+		g.It("records the checksums of the binaries", func() {
+			m, err := defaultExecutor().readManifest(filepath.Join(InstallPath, "1.22.1"))
+			Ω(err).Should(Succeed())
+			Ω(m.Checksums).Should(HaveKey("bin/go"))
+		})
+
+		g.It("passes for untouched sdks", func() {
+			Ω(newSut().Verify("1.22.1", false)).Should(Succeed())
+			Ω(newSut().Verify("1.22.1", true)).Should(Succeed())
+		})
+
+		g.It("detects modified binaries", func() {
+			_ = os.WriteFile(filepath.Join(InstallPath, "1.22.1", "bin", "go"), []byte("tampered"), 0755)
+			err := newSut().Verify("1.22.1", true)
+			Ω(errors.Is(err, errModified)).Should(BeTrue())
+			Ω(errOut.String()).Should(ContainSubstring("bin/go was modified"))
+		})
+
+		g.It("detects removed binaries", func() {
+			_ = os.Remove(filepath.Join(InstallPath, "1.22.1", "bin", "go"))
+			Ω(newSut().Verify("1.22.1", false)).ShouldNot(Succeed())
+			Ω(errOut.String()).Should(ContainSubstring("bin/go is missing"))
+		})
+
+		g.It("detects missing files", func() {
+			_ = os.Remove(filepath.Join(InstallPath, "1.22.1", "VERSION"))
+			Ω(newSut().Verify("1.22.1", false)).ShouldNot(Succeed())
+			Ω(errOut.String()).Should(ContainSubstring("1 of 2 files are missing"))
+		})
+
+		g.It("verifies sdks without manifest against the official archive", func() {
+			server := newReleaseServer("1.22.1")
+			defer server.Close()
+			_ = os.Remove(filepath.Join(InstallPath, "1.22.1", ManifestFile))
+			sut := newSut()
+			sut.URL = server.URL
+			Ω(sut.Verify("1.22.1", false)).Should(Succeed())
+
+			_ = os.WriteFile(filepath.Join(InstallPath, "1.22.1", "VERSION"), []byte("go1.22.2"), 0644)
+			Ω(errors.Is(sut.Verify("1.22.1", false), errModified)).Should(BeTrue())
+			Ω(errOut.String()).Should(ContainSubstring("VERSION was modified"))
+		})
+
+		g.It("fails without manifest and official archive", func() {
+			_ = os.Remove(filepath.Join(InstallPath, "1.22.1", ManifestFile))
+			sut := newSut()
+			sut.URL = "http://127.0.0.1:1"
+			sut.CachePath = filepath.Join(InstallPath, ".cache")
+			Ω(errors.Is(sut.Verify("1.22.1", false), errNoManifest)).Should(BeTrue())
+		})
+
+		g.It("verifies all versions", func() {
+			Ω(defaultExecutor().installArchive("1.21.3", bytes.NewBuffer(archiveData), "test")).Should(Succeed())
+			_ = os.WriteFile(filepath.Join(InstallPath, "1.21.3", "bin", "go"), []byte("tampered"), 0755)
+			err := newSut().VerifyAll(false)
+			var bulkErr *bulkError
+			Ω(errors.As(err, &bulkErr)).Should(BeTrue())
+			Ω(bulkErr.Failures).Should(HaveLen(1))
+			Ω(bulkErr.Failures[0].Item).Should(Equal("1.21.3"))
+			Ω(bulkErr.Partial()).Should(BeTrue())
+			Ω(errors.Is(err, errModified)).Should(BeTrue())
+		})
+	})
+}
+
+func TestQuickSample(t *testing.T) {
+	testutils.Run(t, "quickSample", func(g *goblin.G) {
+		g.It("always samples bin/go", func() {
+			files := []string{"bin/go", "bin/gofmt"}
+			for i := 0; i < 10; i++ {
+				files = append(files, fmt.Sprintf("pkg/tool/linux_amd64/tool%d", i))
+			}
+			sample := quickSample(files, rand.New(rand.NewSource(1)))
+			Ω(sample).Should(HaveLen(quickSampleSize + 1))
+			Ω(sample[0]).Should(Equal("bin/go"))
+		})
+	})
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	semver2 "github.com/Masterminds/semver"
+)
+
+// goPrereleasePattern matches the pre-release names used by the go distribution, e.g. 1.21rc2 or 1.18beta1
+var goPrereleasePattern = regexp.MustCompile(`^(\d+)\.(\d+)(?:\.(\d+))?(beta|rc)(\d+)$`)
+
+type Version string
+
+func MustParseVersion(s string) Version {
+	v, err := ParseVersion(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// ParseVersion parses s into its canonical semver representation.
+// Besides semver it accepts the release names of the go distribution, e.g. go1.21.5 or go1.22rc1.
+func ParseVersion(s string) (Version, error) {
+	v, err := semver2.NewVersion(normalizeGoVersion(s))
+	if err != nil {
+		return "", err
+	}
+	return Version(v.String()), nil
+}
+
+// normalizeGoVersion rewrites a go release name into a string the semver parser understands
+func normalizeGoVersion(s string) string {
+	s = strings.TrimPrefix(s, "go")
+	m := goPrereleasePattern.FindStringSubmatch(s)
+	if m == nil {
+		return s
+	}
+	patch := m[3]
+	if patch == "" {
+		patch = "0"
+	}
+	return fmt.Sprintf("%s.%s.%s-%s.%s", m[1], m[2], patch, m[4], m[5])
+}
+
+func (v Version) Number() string {
+	return strings.TrimPrefix(string(v), "v")
+}
+
+func (v Version) String() string {
+	return string(v)
+}
+
+func (v Version) semver() (*semver2.Version, error) {
+	return semver2.NewVersion(normalizeGoVersion(string(v)))
+}
+
+// Minor returns the release train of the version, e.g. 1.21 for 1.21.5
+func (v Version) Minor() string {
+	sv, err := v.semver()
+	if err != nil {
+		return v.Number()
+	}
+	return fmt.Sprintf("%d.%d", sv.Major(), sv.Minor())
+}
+
+// IsStable reports whether v is a final release rather than a beta or release candidate
+func (v Version) IsStable() bool {
+	sv, err := v.semver()
+	return err == nil && sv.Prerelease() == ""
+}
+
+// IsReleaseCandidate reports whether v is a release candidate, e.g. 1.23rc1
+func (v Version) IsReleaseCandidate() bool {
+	sv, err := v.semver()
+	return err == nil && strings.HasPrefix(sv.Prerelease(), "rc")
+}
+
+// GoName returns the name the go distribution uses for the version.
+// Releases before go1.21 omit the .0 patch of the initial release and pre-releases are suffixed without separator.
+func (v Version) GoName() string {
+	sv, err := v.semver()
+	if err != nil {
+		return v.Number()
+	}
+	minor := fmt.Sprintf("%d.%d", sv.Major(), sv.Minor())
+	switch {
+	case sv.Prerelease() != "":
+		return minor + strings.ReplaceAll(sv.Prerelease(), ".", "")
+	case sv.Patch() == 0 && sv.Major() == 1 && sv.Minor() < 21:
+		return minor
+	default:
+		return fmt.Sprintf("%s.%d", minor, sv.Patch())
+	}
+}
+
+// Compare returns -1, 0 or 1 if v is lower, equal or greater than o.
+// Versions that cannot be parsed sort below every valid version.
+func (v Version) Compare(o Version) int {
+	sv, verr := v.semver()
+	so, oerr := o.semver()
+	switch {
+	case verr != nil && oerr != nil:
+		return strings.Compare(v.String(), o.String())
+	case verr != nil:
+		return -1
+	case oerr != nil:
+		return 1
+	}
+	return sv.Compare(so)
+}
+
+// sortVersions sorts versions in descending order
+func sortVersions(versions []Version) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].Compare(versions[j]) > 0
+	})
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+func TestParseVersion(t *testing.T) {
+	testutils.Run(t, "ParseVersion", func(g *goblin.G) {
+
+		g.It("parses semver", func() {
+			Ω(ParseVersion("v1.17.1")).Should(Equal(Version("1.17.1")))
+		})
+
+		g.It("parses go release names", func() {
+			Ω(ParseVersion("go1.20")).Should(Equal(Version("1.20.0")))
+		})
+
+		g.It("parses go pre-release names", func() {
+			Ω(ParseVersion("go1.21rc2")).Should(Equal(Version("1.21.0-rc.2")))
+		})
+
+		g.It("returns error for invalid versions", func() {
+			_, err := ParseVersion("current")
+			Ω(err).ShouldNot(Succeed())
+		})
+	})
+}
+
+func TestVersion(t *testing.T) {
+	testutils.Run(t, "Version", func(g *goblin.G) {
+
+		g.Describe("GoName", func() {
+			g.It("omits the .0 patch before go1.21", func() {
+				Ω(Version("1.20.0").GoName()).Should(Equal("1.20"))
+			})
+
+			g.It("keeps the .0 patch since go1.21", func() {
+				Ω(Version("1.21.0").GoName()).Should(Equal("1.21.0"))
+			})
+
+			g.It("formats pre-releases", func() {
+				Ω(Version("1.21.0-rc.2").GoName()).Should(Equal("1.21rc2"))
+			})
+		})
+
+		g.Describe("Minor", func() {
+			g.It("returns the release train", func() {
+				Ω(Version("v1.16.8").Minor()).Should(Equal("1.16"))
+			})
+		})
+
+		g.Describe("sortVersions", func() {
+			g.It("sorts in descending semver order", func() {
+				versions := []Version{"1.9.0", "1.21.0-rc.10", "1.21.0", "1.21.0-rc.2", "1.10.1"}
+				sortVersions(versions)
+				Ω(versions).Should(Equal([]Version{"1.21.0", "1.21.0-rc.10", "1.21.0-rc.2", "1.10.1", "1.9.0"}))
+			})
+		})
+	})
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// defaultWatchInterval is the time between two polls of the release feed by watch
+const defaultWatchInterval = 5 * time.Minute
+
+var errUnreleased = errors.New("go version is not released yet")
+
+func newWatchCmd() *cobra.Command {
+	var target string
+	var interval time.Duration
+	var install bool
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "waits for a version to be released",
+		Long: "polls the release feed until the version passed with --for is published, then prints and notifies about it and optionally installs it; " +
+			"a channel like 1.23@rc reports and optionally installs every new release candidate of the line until its final release is published",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateArgsForSubcommand("watch", args, 0); err != nil {
+				return err
+			}
+			if target == "" {
+				return errors.New("required flag --for is missing")
+			}
+			if train, ok, err := parseChannel(target); ok {
+				if err != nil {
+					return err
+				}
+				return defaultExecutor().WatchChannel(c.Context(), train, interval, install)
+			}
+			version, err := ParseVersion(target)
+			if err != nil {
+				return err
+			}
+			return defaultExecutor().Watch(c.Context(), version, interval, install)
+		},
+	}
+	cmd.Flags().StringVar(&target, "for", "", "the version or channel to wait for, e.g. 1.23.4 or 1.23@rc")
+	cmd.Flags().DurationVar(&interval, "interval", defaultWatchInterval, "time between two polls of the release feed")
+	cmd.Flags().BoolVar(&install, "install", false, "install the version once it is released")
+	return cmd
+}
+
+// released reports whether version is listed in the release feed
+func (e *executor) released(ctx context.Context, version Version) (bool, error) {
+	remote, err := e.remoteVersions(ctx, true)
+	if err != nil {
+		return false, err
+	}
+	for _, v := range remote {
+		if v.Compare(version) == 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// checkUnreleased fails with errUnreleased if version is newer than every published release,
+// e.g. because of a typo in a toolchain directive or early adoption.
+// An unavailable release feed is no error, so offline fallbacks keep working.
+func (e *executor) checkUnreleased(ctx context.Context, version Version) error {
+	remote, err := e.remoteVersions(ctx, true)
+	if err != nil || len(remote) == 0 || version.Compare(remote[0]) <= 0 {
+		return nil
+	}
+	return fmt.Errorf("%w; version=%s; newest=%s; run 'dfctl-go watch --for %s --install' to install it once it is published",
+		errUnreleased, version, remote[0], version)
+}
+
+// Watch polls the release feed every interval until version is released, then notifies about it and installs it if requested
+func (e *executor) Watch(ctx context.Context, version Version, interval time.Duration, install bool) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		released, err := e.released(ctx, version)
+		if err != nil {
+			log.Warn().Err(err).Msgf("failed to poll the release feed; retrying in %s", interval)
+		}
+		if released {
+			break
+		}
+		log.Debug().Msgf("go %s is not released yet; polling again in %s", version, interval)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+
+	return e.announceRelease(version, install)
+}
+
+// WatchChannel polls the release feed every interval and notifies about, or installs, every release of the rc channel of train
+// newer than the installed ones until the final release of train is published
+func (e *executor) WatchChannel(ctx context.Context, train string, interval time.Duration, install bool) error {
+	cfg, err := e.config()
+	if err != nil {
+		return err
+	}
+	var seen Version
+	if installed, err := e.installedVersions(); err == nil {
+		seen, _ = latestInChannel(installed, train, nil)
+	}
+	if seen.IsStable() {
+		_, _ = fmt.Fprintf(e.Streams.Out, "go %s is already installed\n", seen)
+		return nil
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		remote, err := e.remoteVersions(ctx, true)
+		if err != nil {
+			log.Warn().Err(err).Msgf("failed to poll the release feed; retrying in %s", interval)
+		}
+		if latest, ok := latestInChannel(remote, train, cfg); ok && (seen == "" || latest.Compare(seen) > 0) {
+			seen = latest
+			if err = e.announceRelease(latest, install); err != nil {
+				return err
+			}
+			if latest.IsStable() {
+				return nil
+			}
+		}
+		log.Debug().Msgf("go %s is not released yet; polling again in %s", train, interval)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// announceRelease prints and notifies about the released version and installs it if requested
+func (e *executor) announceRelease(version Version, install bool) error {
+	message := fmt.Sprintf("go %s is released", version)
+	_, _ = fmt.Fprintln(e.Streams.Out, message)
+	if !install {
+		e.notify("dfctl-go", message)
+		return nil
+	}
+	if err := e.Install(version); err != nil {
+		e.notify("dfctl-go", fmt.Sprintf("%s, but installing it failed", message))
+		return err
+	}
+	_, _ = fmt.Fprintf(e.Streams.Out, "installed go %s\n", version)
+	e.notify("dfctl-go", fmt.Sprintf("installed go %s", version))
+	return nil
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+func TestWatch(t *testing.T) {
+	testutils.Run(t, "Watch", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		before := newReleaseServer("1.23.3", "1.22.9")
+		after := newReleaseServer("1.23.4", "1.23.3", "1.22.9")
+		var polls int32
+		// the release feed publishes 1.23.4 on the third poll
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			upstream := before.URL
+			if r.URL.Query().Get("mode") != "json" || atomic.AddInt32(&polls, 1) > 2 {
+				upstream = after.URL
+			}
+			resp, err := http.Get(upstream + r.URL.RequestURI())
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadGateway)
+				return
+			}
+			defer resp.Body.Close()
+			_, _ = io.Copy(w, resp.Body)
+		}))
+
+		g.BeforeEach(func() {
+			atomic.StoreInt32(&polls, 0)
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(InstallPath)
+		})
+
+		g.After(func() {
+			server.Close()
+			before.Close()
+			after.Close()
+		})
+
+		newSut := func() *executor {
+			sut := defaultExecutor()
+			sut.URL = server.URL
+			sut.Streams.Out = &Buffer{&bytes.Buffer{}}
+			return sut
+		}
+
+		g.It("fails to resolve versions newer than every release", func() {
+			_, err := newSut().resolveVersion(context.Background(), "1.23.4", remoteScope, false)
+			Ω(errors.Is(err, errUnreleased)).Should(BeTrue())
+			Ω(err.Error()).Should(ContainSubstring("newest=1.23.3"))
+			Ω(err.Error()).Should(ContainSubstring("watch --for 1.23.4"))
+		})
+
+		g.It("resolves released versions", func() {
+			Ω(newSut().resolveVersion(context.Background(), "1.22.9", remoteScope, false)).Should(Equal(Version("1.22.9")))
+		})
+
+		g.It("polls until the version is released", func() {
+			sut := newSut()
+			Ω(sut.Watch(context.Background(), "1.23.4", time.Millisecond, false)).Should(Succeed())
+			Ω(atomic.LoadInt32(&polls)).Should(BeNumerically("==", 3))
+			Ω(sut.Streams.Out.(*Buffer).String()).Should(Equal("go 1.23.4 is released\n"))
+		})
+
+		g.It("installs the version once it is released", func() {
+			Ω(newSut().Watch(context.Background(), "1.23.4", time.Millisecond, true)).Should(Succeed())
+			Ω(filepath.Join(InstallPath, "1.23.4")).Should(BeADirectory())
+		})
+
+		g.It("stops when cancelled", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			Ω(newSut().Watch(ctx, "1.24.0", time.Millisecond, false)).Should(MatchError(context.DeadlineExceeded))
+		})
+
+		g.It("follows the release candidates of a channel until the final release", func() {
+			rc := newReleaseServer("1.23.0-rc.1", "1.22.9")
+			final := newReleaseServer("1.23.0", "1.23.0-rc.1", "1.22.9")
+			defer rc.Close()
+			defer final.Close()
+			atomic.StoreInt32(&polls, 0)
+			// the release feed publishes the final release on the third poll
+			channel := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				upstream := rc.URL
+				if r.URL.Query().Get("mode") != "json" || atomic.AddInt32(&polls, 1) > 2 {
+					upstream = final.URL
+				}
+				resp, err := http.Get(upstream + r.URL.RequestURI())
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusBadGateway)
+					return
+				}
+				defer resp.Body.Close()
+				_, _ = io.Copy(w, resp.Body)
+			}))
+			defer channel.Close()
+
+			sut := newSut()
+			sut.URL = channel.URL
+			Ω(sut.WatchChannel(context.Background(), "1.23", time.Millisecond, true)).Should(Succeed())
+			Ω(filepath.Join(InstallPath, "1.23.0-rc.1")).Should(BeADirectory())
+			Ω(filepath.Join(InstallPath, "1.23.0")).Should(BeADirectory())
+		})
+	})
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/alex-held/dfctl-kit/pkg/env"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var errBinaryNotFound = errors.New("binary not found")
+
+func newWhichCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "which [binary]",
+		Short: "prints the binary that runs for a command like go or gofmt",
+		Long: "prints the absolute path of the binary that runs for a command, go by default, " +
+			"following the shims and the current link to the sdk of the version in effect for the working directory, which helps to debug PATH confusion",
+		Example: "  dfctl-go which\n  dfctl-go which gofmt",
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := validateMaxArgsForSubcommand("which", args, 1); err != nil {
+				return err
+			}
+			name := "go"
+			if len(args) == 1 {
+				name = args[0]
+			}
+			wd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			e := defaultExecutor()
+			bin, err := e.which(name, wd)
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintln(e.Streams.Out, bin)
+			return nil
+		},
+	}
+}
+
+// which returns the binary that runs for name in dir.
+// The first match on PATH is followed through the shims to the sdk of the version in effect for dir
+// and through the current link to the sdk it links to; other matches are not managed and returned as is.
+func (e *executor) which(name, dir string) (string, error) {
+	found, err := lookPath(name, env.GetVars().Get("PATH"))
+	if err != nil {
+		return "", fmt.Errorf("%w; name=%s; no directory on PATH contains it", errBinaryNotFound, name)
+	}
+	found, err = filepath.Abs(found)
+	if err != nil {
+		return "", err
+	}
+
+	switch bin := filepath.Dir(found); bin {
+	case filepath.Clean(e.ShimPath):
+		version, origin, err := e.resolved(dir)
+		if err != nil {
+			return "", err
+		}
+		goroot, err := e.goroot(version)
+		if err != nil {
+			return "", fmt.Errorf("%w; version=%s; origin=%s", err, version, origin)
+		}
+		target, err := lookPath(name, filepath.Join(goroot, "bin"))
+		if err != nil {
+			return "", fmt.Errorf("%w; name=%s; version=%s; the sdk does not provide it", errBinaryNotFound, name, version)
+		}
+		log.Debug().Msgf("%s is the shim %s running go %s from %s", name, found, version, origin)
+		return target, nil
+	case filepath.Join(e.InstallPath, "current", "bin"):
+		target, err := filepath.EvalSymlinks(found)
+		if err != nil {
+			return "", fmt.Errorf("failed to follow the current link of %s; err=%v", found, err)
+		}
+		log.Debug().Msgf("%s runs from the current link %s", name, found)
+		return target, nil
+	}
+	if !e.managedBinDir(filepath.Dir(found)) {
+		log.Debug().Msgf("%s is not managed by dfctl-go", found)
+	}
+	return found, nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/env"
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+)
+
+func TestWhich(t *testing.T) {
+	testutils.Run(t, "which", func(g *goblin.G) {
+		InstallPath = installPath(t)
+		ShimPath = testutils.TempDir(t, "shims")
+		other := testutils.TempDir(t, "other")
+		outside := testutils.TempDir(t, "outside")
+
+		writeBinary := func(dir, name string) string {
+			_ = os.MkdirAll(dir, os.ModePerm)
+			_ = os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"), 0755)
+			return filepath.Join(dir, name)
+		}
+
+		g.BeforeEach(func() {
+			createVersionDirs()
+			writeBinary(filepath.Join(InstallPath, "v1.17.1", "bin"), "go")
+			writeBinary(filepath.Join(InstallPath, "v1.16.8", "bin"), "go")
+			symlink(afero.NewOsFs(), filepath.Join(InstallPath, "v1.16.8"), filepath.Join(InstallPath, "current"))
+			env.Overrides.Vars = env.Vars{SessionVersionEnv: ""}
+		})
+
+		g.AfterEach(func() {
+			env.ClearOverrides()
+			_ = os.RemoveAll(InstallPath)
+			_ = os.RemoveAll(ShimPath)
+			_ = os.RemoveAll(other)
+		})
+
+		g.It("follows the shims to the sdk of the version in effect", func() {
+			writeBinary(ShimPath, "go")
+			env.Overrides.Vars = env.Vars{SessionVersionEnv: "1.17", "PATH": ShimPath + ":" + other}
+			Ω(defaultExecutor().which("go", outside)).Should(Equal(filepath.Join(InstallPath, "v1.17.1", "bin", "go")))
+		})
+
+		g.It("follows the current link", func() {
+			env.Overrides.Vars = env.Vars{SessionVersionEnv: "", "PATH": filepath.Join(InstallPath, "current", "bin")}
+			Ω(defaultExecutor().which("go", outside)).Should(Equal(filepath.Join(InstallPath, "v1.16.8", "bin", "go")))
+		})
+
+		g.It("returns binaries which are not managed as is", func() {
+			foreign := writeBinary(other, "go")
+			env.Overrides.Vars = env.Vars{SessionVersionEnv: "", "PATH": other + ":" + filepath.Join(InstallPath, "current", "bin")}
+			Ω(defaultExecutor().which("go", outside)).Should(Equal(foreign))
+		})
+
+		g.It("fails if the sdk in effect does not provide the binary", func() {
+			writeBinary(ShimPath, "gofmt")
+			env.Overrides.Vars = env.Vars{SessionVersionEnv: "1.17", "PATH": ShimPath}
+			_, err := defaultExecutor().which("gofmt", outside)
+			Ω(err).Should(MatchError(ContainSubstring("the sdk does not provide it")))
+		})
+
+		g.It("fails if no directory on PATH contains the binary", func() {
+			env.Overrides.Vars = env.Vars{SessionVersionEnv: "", "PATH": other}
+			_, err := defaultExecutor().which("go", outside)
+			Ω(err).Should(MatchError(ContainSubstring(errBinaryNotFound.Error())))
+		})
+	})
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/afero"
+)
+
+// zstdCommand is the zstd binary used to compress and decompress .tar.zst artifacts
+var zstdCommand = "zstd"
+
+// ZstdArtifact is a zstd recompressed copy of a release archive, which mirrors advertise in their index
+type ZstdArtifact struct {
+	Filename string `json:"filename"`
+	SHA256   string `json:"sha256"`
+	Size     int64  `json:"size"`
+	// TarSHA256 is the checksum of the decompressed tar, computed from the archive verified against the official checksum
+	TarSHA256 string `json:"tar_sha256"`
+}
+
+// zstdAvailable reports whether the zstd binary is on PATH
+func zstdAvailable() bool {
+	_, err := exec.LookPath(zstdCommand)
+	return err == nil
+}
+
+// runZstd pipes in through zstd with the given args into out
+func runZstd(ctx context.Context, in io.Reader, out io.Writer, args ...string) error {
+	stderr := &bytes.Buffer{}
+	cmd := exec.CommandContext(ctx, zstdCommand, append(args, "-q", "-c")...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = in, out, stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s %s failed; err=%v; stderr=%s", zstdCommand, strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// dlZstdArchive downloads the .tar.zst artifact the configured mirror advertises for the archive filename and
+// returns the decompressed tar verified against its tar checksum; ok is false if the archive has to be downloaded
+// instead, because no mirror is configured, the mirror advertises no zstd artifact or zstd is not installed
+func (e *executor) dlZstdArchive(ctx context.Context, filename string) (archive *bytes.Buffer, ok bool, err error) {
+	if e.URL == DownloadURL || !zstdAvailable() {
+		return nil, false, nil
+	}
+	f, err := e.releaseFile(ctx, filename)
+	if err != nil || f.Zstd == nil {
+		log.Debug().Err(err).Msgf("mirror %s advertises no zstd artifact for %s", e.URL, filename)
+		return nil, false, nil
+	}
+
+	compressed := &bytes.Buffer{}
+	h := sha256.New()
+	if err = e.downloadWithProgress(ctx, e.URL+"/dl/"+f.Zstd.Filename, io.MultiWriter(compressed, h), f.Zstd.Filename); err != nil {
+		log.Warn().Err(err).Msgf("failed to download %s; falling back to %s", f.Zstd.Filename, filename)
+		return nil, false, nil
+	}
+	if sum := hex.EncodeToString(h.Sum(nil)); sum != f.Zstd.SHA256 {
+		return nil, true, fmt.Errorf("%w; file=%s; expected=%s; actual=%s", errChecksumMismatch, f.Zstd.Filename, f.Zstd.SHA256, sum)
+	}
+
+	archive = &bytes.Buffer{}
+	h = sha256.New()
+	if err = runZstd(ctx, compressed, io.MultiWriter(archive, h), "-d"); err != nil {
+		return nil, true, err
+	}
+	if sum := hex.EncodeToString(h.Sum(nil)); sum != f.Zstd.TarSHA256 {
+		return nil, true, fmt.Errorf("%w; file=%s; decompressed tar differs from %s; expected=%s; actual=%s", errChecksumMismatch, f.Zstd.Filename, filename, f.Zstd.TarSHA256, sum)
+	}
+	return archive, true, nil
+}
+
+// mirrorZstd recompresses the mirrored .tar.gz artifact in dir with zstd, unless the .tar.zst artifact is up to date
+func (e *executor) mirrorZstd(ctx context.Context, dir string, f ReleaseFile, upToDate bool) (*ZstdArtifact, error) {
+	source := filepath.Join(dir, f.Filename)
+	target := strings.TrimSuffix(source, ".tar.gz") + ".tar.zst"
+	// the sidecar keeps the tar checksum, which is expensive to recompute, for later syncs
+	sidecar := target + ".json"
+	if upToDate {
+		z := &ZstdArtifact{}
+		if data, err := afero.ReadFile(e.Fs, sidecar); err == nil && json.Unmarshal(data, z) == nil {
+			if sum, err := fileSHA256(e.Fs, target); err == nil && sum == z.SHA256 {
+				return z, nil
+			}
+		}
+	}
+
+	in, err := e.Fs.Open(source)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+	gr, err := gzip.NewReader(in)
+	if err != nil {
+		return nil, err
+	}
+	tmp := target + ".partial"
+	out, err := e.Fs.Create(tmp)
+	if err != nil {
+		return nil, err
+	}
+	tarHash, zstdHash := sha256.New(), sha256.New()
+	cw := &countingWriter{Writer: io.MultiWriter(out, zstdHash)}
+	err = runZstd(ctx, io.TeeReader(gr, tarHash), cw, "-19", "-T0")
+	_ = out.Close()
+	if err != nil {
+		_ = e.Fs.Remove(tmp)
+		return nil, err
+	}
+	if err = e.Fs.Rename(tmp, target); err != nil {
+		return nil, err
+	}
+
+	z := &ZstdArtifact{
+		Filename:  strings.TrimSuffix(f.Filename, ".tar.gz") + ".tar.zst",
+		SHA256:    hex.EncodeToString(zstdHash.Sum(nil)),
+		Size:      cw.n,
+		TarSHA256: hex.EncodeToString(tarHash.Sum(nil)),
+	}
+	data, err := json.Marshal(z)
+	if err != nil {
+		return nil, err
+	}
+	return z, afero.WriteFile(e.Fs, sidecar, data, 0644)
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	io.Writer
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.Writer.Write(p)
+	w.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alex-held/dfctl-kit/pkg/system"
+	"github.com/alex-held/dfctl-kit/pkg/testutils"
+	"github.com/franela/goblin"
+	. "github.com/onsi/gomega"
+)
+
+// newMirrorServer serves the mirror synced into dest with its index as directory index
+func newMirrorServer(dest string) *httptest.Server {
+	files := http.FileServer(http.Dir(dest))
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/dl/" {
+			http.ServeFile(w, r, filepath.Join(dest, "dl", MirrorIndexFile))
+			return
+		}
+		files.ServeHTTP(w, r)
+	}))
+}
+
+func TestZstdArchive(t *testing.T) {
+	testutils.Run(t, "dlZstdArchive", func(g *goblin.G) {
+		if !zstdAvailable() {
+			t.Skip("zstd is not installed")
+		}
+		dest := testutils.TempDir(t, "mirror")
+		upstream := newReleaseServer("1.22.1")
+		mirror := newMirrorServer(dest)
+		filename := formatGoArchiveArtifactName(system.OSRuntimeInfoGetter{}.Get(), "1.22.1")
+
+		g.BeforeEach(func() {
+			sut := defaultExecutor()
+			sut.URL = upstream.URL
+			sut.Streams.Out = &Buffer{&bytes.Buffer{}}
+			Ω(sut.MirrorSync(context.Background(), mirrorOptions{Dest: dest, Zstd: true})).Should(Succeed())
+		})
+
+		g.AfterEach(func() {
+			_ = os.RemoveAll(dest)
+		})
+
+		g.After(func() {
+			upstream.Close()
+			mirror.Close()
+		})
+
+		newSut := func() *executor {
+			sut := defaultExecutor()
+			sut.URL = mirror.URL
+			return sut
+		}
+
+		g.It("advertises the zstd artifact in the mirror index", func() {
+			f, err := newSut().releaseFile(context.Background(), filename)
+			Ω(err).Should(Succeed())
+			Ω(f.Zstd).ShouldNot(BeNil())
+			Ω(filepath.Join(dest, "dl", f.Zstd.Filename)).Should(BeARegularFile())
+		})
+
+		g.It("prefers the zstd artifact of mirrors", func() {
+			archive, ok, err := newSut().dlZstdArchive(context.Background(), filename)
+			Ω(err).Should(Succeed())
+			Ω(ok).Should(BeTrue())
+			files, _, err := tarGzipStats(archive.Bytes())
+			Ω(err).Should(Succeed())
+			Ω(files).Should(Equal(4))
+		})
+
+		g.It("falls back to the archive without zstd artifact", func() {
+			sut := newSut()
+			sut.URL = upstream.URL
+			_, ok, err := sut.dlZstdArchive(context.Background(), filename)
+			Ω(err).Should(Succeed())
+			Ω(ok).Should(BeFalse())
+		})
+
+		g.It("fails if the decompressed tar differs from the official archive", func() {
+			index := filepath.Join(dest, "dl", MirrorIndexFile)
+			data, _ := os.ReadFile(index)
+			var releases []Release
+			Ω(json.Unmarshal(data, &releases)).Should(Succeed())
+			releases[0].Files[0].Zstd.TarSHA256 = "0000"
+			data, _ = json.Marshal(releases)
+			_ = os.WriteFile(index, data, 0644)
+
+			_, _, err := newSut().dlZstdArchive(context.Background(), filename)
+			Ω(err).Should(MatchError(ContainSubstring(errChecksumMismatch.Error())))
+		})
+	})
+}